@@ -12,15 +12,22 @@ import (
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/app"
 	configuration "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/config"
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/metrics"
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories/db"
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories/memory"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/scheduler"
+	internalgrpc "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/grpc"
 	internalhttp "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/http"
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/http/handlers"
 	eventservice "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/services"
 )
 
+// defaultShutdownTimeout - время на завершение обрабатываемых запросов при остановке.
+const defaultShutdownTimeout = 3 * time.Second
+
 var configFile string
 
 func init() {
@@ -41,57 +48,99 @@ func main() {
 		os.Exit(1)
 	}
 
-	logg := logger.New(config.Logger.Level, os.Stdout)
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(config.DB, flag.Arg(1)); err != nil {
+			fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	logg := logger.NewWithFormat(config.Logger.Level, config.Logger.Format, os.Stdout)
 
 	if err := run(config, logg); err != nil {
-		logg.Error("application error: " + err.Error())
+		logg.Error("application error", "error", err)
 		os.Exit(1)
 	}
 }
 
 func run(config *configuration.Config, logg logger.Logger) error {
-	txManager, cleanup, err := initDatabase(config.DB, logg)
+	appMetrics := metrics.New()
+
+	txManager, cleanup, err := initDatabase(config.DB, appMetrics, logg)
 	if err != nil {
 		return fmt.Errorf("failed to setup database: %w", err)
 	}
 	defer cleanup()
 
-	eventRepo, err := initEventRepository(config.DB, txManager)
+	eventRepo, txManager, storage, closeRepo, err := initEventRepository(config.DB, txManager, appMetrics, logg)
 	if err != nil {
 		return fmt.Errorf("failed to setup event repository: %w", err)
 	}
+	defer closeRepo()
 
-	eventService := eventservice.NewEventService(eventRepo, txManager)
+	weekStart, err := domain.ParseWeekday(config.Calendar.WeekStart)
+	if err != nil {
+		return fmt.Errorf("invalid calendar week_start %q: %w", config.Calendar.WeekStart, err)
+	}
+
+	eventService := metrics.InstrumentEventService(eventservice.NewEventService(eventRepo, txManager), appMetrics)
 	var notifyService eventservice.NotificationService
-	calendar := app.New(eventService, notifyService, logg)
+	calendar := app.New(eventService, notifyService, logg, weekStart)
 
-	server := initHTTPServer(config.HTTP, calendar, logg)
+	httpServer, err := initHTTPServer(config.HTTP, calendar, appMetrics, logg)
+	if err != nil {
+		return fmt.Errorf("failed to setup http server: %w", err)
+	}
+	httpServer.RegisterHealth(internalhttp.Health{
+		Storage:    config.DB.Type,
+		Checker:    storage,
+		Build:      internalhttp.BuildInfo{Release: release, BuildDate: buildDate, GitHash: gitHash},
+		DrainDelay: config.HTTP.DrainDelay,
+	})
+	grpcServer := initGRPCServer(config.GRPC, calendar, appMetrics, logg)
+	metricsServer := metrics.NewServer(logg, appMetrics, config.Metrics.Host+":"+config.Metrics.Port)
+
+	configReloader := &reloader{path: configFile, config: config, logg: logg.With(logger.ComponentKey, "config"), httpServer: httpServer, db: txManager.GetDB()}
+
+	// Хранилище в памяти не видно calendar_scheduler, поэтому старые события удаляет сам calendar
+	var cleaner *scheduler.Cleaner
+	if config.DB.Type == "memory" {
+		cleaner = initCleaner(config.Retention, eventRepo, txManager, logg)
+	}
 
-	return runHTTPServer(server, logg)
+	shutdownTimeout := config.HTTP.DrainDelay + defaultShutdownTimeout
+	return runServers(httpServer, grpcServer, metricsServer, cleaner, configReloader, shutdownTimeout, logg)
 }
 
 type cleanupFunc func()
 
-func initDatabase(dbConf configuration.DBConf, logg logger.Logger) (database.TxManager, cleanupFunc, error) {
+func initDatabase(dbConf configuration.DBConf, appMetrics *metrics.Metrics, logg logger.Logger) (database.TxManager, cleanupFunc, error) {
 	switch dbConf.Type {
 	case "memory":
 		logg.Info("using in-memory storage")
 		return nil, func() {}, nil
 
-	case "db":
-		logg.Info("connecting to PostgreSQL database...")
-		poolConfig := database.DefaultConnectionConfig(dbConf.DSN)
-		sqlxDB, err := database.NewConnection(poolConfig)
+	case "db", "sqlite":
+		sqlxDB, err := database.Connect(dbConf, logg)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
 		}
 		logg.Info("database connection established")
 
-		txManager := database.NewTxManager(sqlxDB)
+		if dbConf.MigrateOnStart {
+			if err := migrateOnStart(sqlxDB, migrationDialect(dbConf.Type), logg); err != nil {
+				_ = sqlxDB.Close()
+				return nil, nil, err
+			}
+		}
+
+		appMetrics.RegisterDB(sqlxDB.DB)
+		txManager := database.NewTxManagerWithObserver(sqlxDB, database.RetryPolicyFrom(dbConf.Retry), appMetrics)
 
 		cleanup := func() {
 			if err := sqlxDB.Close(); err != nil {
-				logg.Error("failed to close database connection: " + err.Error())
+				logg.Error("failed to close database connection", "error", err)
 			}
 		}
 
@@ -102,34 +151,63 @@ func initDatabase(dbConf configuration.DBConf, logg logger.Logger) (database.TxM
 	}
 }
 
-func initEventRepository(dbConf configuration.DBConf, txManager database.TxManager) (repositories.CompositeEventRepository, error) {
+// initEventRepository создает репозиторий событий и его менеджер транзакций; cleanupFunc сохраняет хранилище при остановке.
+func initEventRepository(
+	dbConf configuration.DBConf, txManager database.TxManager, appMetrics *metrics.Metrics, logg logger.Logger,
+) (repositories.CompositeEventRepository, database.TxManager, repositories.Connector, cleanupFunc, error) {
 	switch dbConf.Type {
 	case "memory":
-		return initMemoryEventRepository()
-	case "db":
-		return initDBEventRepository(txManager)
+		return initMemoryEventRepository(dbConf.Persistence, appMetrics, logg)
+	case "db", "sqlite":
+		repo, storage, cleanup, err := initSQLEventRepository(dbConf.Type, txManager)
+		return repo, txManager, storage, cleanup, err
 	default:
-		return nil, fmt.Errorf("unknown database type: %s", dbConf.Type)
+		return nil, nil, nil, nil, fmt.Errorf("unknown database type: %s", dbConf.Type)
 	}
 }
 
-func initMemoryEventRepository() (repositories.CompositeEventRepository, error) {
+func initMemoryEventRepository(
+	persistenceConf configuration.PersistenceConf, appMetrics *metrics.Metrics, logg logger.Logger,
+) (repositories.CompositeEventRepository, database.TxManager, repositories.Connector, cleanupFunc, error) {
 	crudRepo := memory.NewEventCrudRepository()
+	if persistenceConf.Path != "" {
+		var err error
+		crudRepo, err = memory.NewPersistentEventCrudRepository(memory.PersistenceConfig{
+			Dir:             persistenceConf.Path,
+			FSync:           persistenceConf.FSync,
+			CompactInterval: persistenceConf.CompactInterval,
+		}, logg)
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("failed to open memory storage: %w", err)
+		}
+	}
+
 	repo, err := memory.NewEventRepository(crudRepo)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create memory event repository: %w", err)
+		return nil, nil, nil, nil, fmt.Errorf("failed to create memory event repository: %w", err)
+	}
+	cleanup := func() {
+		if err := crudRepo.Close(context.Background()); err != nil {
+			logg.Error("failed to save memory storage", "error", err)
+		}
 	}
-	return repo, nil
+	txManager := memory.NewTxManagerWithObserver(crudRepo, appMetrics)
+	return repo, txManager, crudRepo, cleanup, nil
 }
 
-func initDBEventRepository(txManager database.TxManager) (repositories.CompositeEventRepository, error) {
-	sqlxDB := txManager.GetDB()
-	crudRepo := db.NewEventCrudRepository(sqlxDB)
+func initSQLEventRepository(
+	dbType string, txManager database.TxManager,
+) (repositories.CompositeEventRepository, repositories.Connector, cleanupFunc, error) {
+	dialect, err := db.DialectOf(dbType)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	crudRepo := db.NewEventCrudRepository(txManager.GetDB(), dialect)
 	repo, err := db.NewEventRepository(crudRepo)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create db event repository: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to create %s event repository: %w", dbType, err)
 	}
-	return repo, nil
+	return repo, crudRepo, func() {}, nil
 }
 
 // TODO: Примеры создания других репозиториев:
@@ -156,33 +234,104 @@ func initDBEventRepository(txManager database.TxManager) (repositories.Composite
 //     }
 // }
 
-func initHTTPServer(httpConf configuration.HTTPConf, calendar *app.App, logg logger.Logger) *internalhttp.ServerNew {
+func initCleaner(
+	retentionConf configuration.RetentionConf, eventRepo repositories.CompositeEventRepository,
+	txManager database.TxManager, logg logger.Logger,
+) *scheduler.Cleaner {
+	return scheduler.NewCleaner(eventRepo, txManager, logg, scheduler.CleanerConfig{
+		Retention:      retentionConf.Period,
+		TrashRetention: retentionConf.TrashPeriod,
+		Interval:       retentionConf.Interval,
+		BatchSize:      retentionConf.BatchSize,
+	})
+}
+
+func initHTTPServer(
+	httpConf configuration.HTTPConf, calendar *app.App, appMetrics *metrics.Metrics, logg logger.Logger,
+) (*internalhttp.ServerNew, error) {
+	metricsMiddleware, err := handlers.MetricsMiddleware(appMetrics)
+	if err != nil {
+		return nil, err
+	}
 	eventHandler := handlers.NewEventHandler(calendar, logg)
 	serverAddr := httpConf.Host + ":" + httpConf.Port
-	return internalhttp.NewServerWithGeneratedHandlers(logg, eventHandler, serverAddr)
+	server := internalhttp.NewServerWithGeneratedHandlers(logg, eventHandler, serverAddr, metricsMiddleware)
+	server.ApplySettings(httpSettings(httpConf))
+	return server, nil
+}
+
+func initGRPCServer(grpcConf configuration.GRPCConf, calendar *app.App, appMetrics *metrics.Metrics, logg logger.Logger) *internalgrpc.Server {
+	eventServer := internalgrpc.NewEventServer(calendar, logg)
+	serverAddr := grpcConf.Host + ":" + grpcConf.Port
+	return internalgrpc.NewServer(logg, eventServer, serverAddr, internalgrpc.MetricsInterceptor(appMetrics))
 }
 
-func runHTTPServer(server *internalhttp.ServerNew, logg logger.Logger) error {
-	ctx, cancel := signal.NotifyContext(context.Background(),
-		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+func runServers(
+	httpServer *internalhttp.ServerNew, grpcServer *internalgrpc.Server, metricsServer *metrics.Server,
+	cleaner *scheduler.Cleaner, configReloader *reloader, shutdownTimeout time.Duration, logg logger.Logger,
+) error {
+	// SIGHUP не останавливает сервис, а перечитывает конфигурацию.
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
+	go configReloader.run(ctx)
+
+	cleanerDone := make(chan struct{})
+	go func() {
+		defer close(cleanerDone)
+		if cleaner != nil {
+			_ = cleaner.Run(ctx)
+		}
+	}()
+
 	go func() {
 		<-ctx.Done()
 		logg.Info("shutdown signal received")
 
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 3*time.Second)
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
 		defer shutdownCancel()
 
-		if err := server.Stop(shutdownCtx); err != nil {
-			logg.Error("failed to stop http server: " + err.Error())
+		if err := httpServer.Stop(shutdownCtx); err != nil {
+			logg.Error("failed to stop http server", "error", err)
 		}
+		if err := grpcServer.Stop(shutdownCtx); err != nil {
+			logg.Error("failed to stop grpc server", "error", err)
+		}
+		if err := metricsServer.Stop(shutdownCtx); err != nil {
+			logg.Error("failed to stop metrics server", "error", err)
+		}
+	}()
+
+	grpcErr := make(chan error, 1)
+	go func() {
+		err := grpcServer.Start(ctx)
+		// Если gRPC-сервер не смог запуститься, HTTP-сервер тоже останавливается
+		cancel()
+		grpcErr <- err
+	}()
+
+	metricsErr := make(chan error, 1)
+	go func() {
+		err := metricsServer.Start(ctx)
+		cancel()
+		metricsErr <- err
 	}()
 
 	logg.Info("calendar is running...")
 
-	if err := server.Start(ctx); err != nil {
-		return fmt.Errorf("server error: %w", err)
+	httpErr := httpServer.Start(ctx)
+	cancel()
+	// Очистка должна закончиться до сохранения хранилища на диск
+	<-cleanerDone
+
+	if err := <-grpcErr; err != nil {
+		return fmt.Errorf("grpc server error: %w", err)
+	}
+	if err := <-metricsErr; err != nil {
+		return fmt.Errorf("metrics server error: %w", err)
+	}
+	if httpErr != nil {
+		return fmt.Errorf("server error: %w", httpErr)
 	}
 
 	return nil
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	configuration "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// runMigrate выполняет подкоманду "calendar --config=... migrate up|down|redo|status".
+func runMigrate(dbConf configuration.DBConf, command string) error {
+	if dbConf.Type != "db" && dbConf.Type != "sqlite" {
+		return fmt.Errorf("migrations require database type \"db\" or \"sqlite\", got %q", dbConf.Type)
+	}
+
+	var (
+		sqlxDB *sqlx.DB
+		err    error
+	)
+	if dbConf.Type == "sqlite" {
+		sqlxDB, err = database.NewSQLiteConnection(database.ConnectionConfigFrom(dbConf))
+	} else {
+		sqlxDB, err = database.NewConnection(database.ConnectionConfigFrom(dbConf))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer sqlxDB.Close()
+
+	return database.Migrate(context.Background(), sqlxDB.DB, migrationDialect(dbConf.Type), command, os.Stdout)
+}
+
+// migrationDialect возвращает набор миграций для database.type.
+func migrationDialect(dbType string) string {
+	if dbType == "sqlite" {
+		return database.DialectSQLite
+	}
+	return database.DialectPostgres
+}
+
+// migrateOnStart применяет новые миграции при запуске сервиса (database.migrate_on_start).
+func migrateOnStart(sqlxDB *sqlx.DB, dialect string, logg logger.Logger) error {
+	logg.Info("applying database migrations...")
+
+	var out strings.Builder
+	if err := database.Migrate(context.Background(), sqlxDB.DB, dialect, database.MigrateUp, &out); err != nil {
+		return err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		logg.Info("migration", "output", line)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	configuration "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	internalhttp "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/http"
+	"github.com/jmoiron/sqlx"
+)
+
+// reloader перечитывает конфигурацию по SIGHUP и применяет параметры, не требующие перезапуска:
+// уровень логирования, таймауты и CORS HTTP-сервера, размеры пула соединений с БД.
+type reloader struct {
+	path       string
+	config     *configuration.Config
+	logg       logger.Logger
+	httpServer *internalhttp.ServerNew
+	// db - пул соединений, nil для хранилища в памяти.
+	db *sqlx.DB
+}
+
+func (r *reloader) run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.reload()
+		}
+	}
+}
+
+func (r *reloader) reload() {
+	r.logg.Info("reloading", "path", r.path)
+
+	next, err := configuration.NewConfig(r.path)
+	if err != nil {
+		r.logg.Error("reload failed, keeping current configuration", "error", err)
+		return
+	}
+
+	config, changes, restart := r.config.Reload(next)
+	for _, field := range restart {
+		r.logg.Warn("change requires restart, ignored", "field", field)
+	}
+	if len(changes) == 0 {
+		r.logg.Info("no changes applied")
+		return
+	}
+	for _, change := range changes {
+		r.logg.Info("setting changed", "field", change.Field, "old", change.Old, "new", change.New)
+	}
+
+	if setter, ok := r.logg.(logger.LevelSetter); ok {
+		setter.SetLevel(config.Logger.Level)
+	}
+	r.httpServer.ApplySettings(httpSettings(config.HTTP))
+	if r.db != nil {
+		database.ApplyPoolSettings(r.db.DB, database.ConnectionConfigFrom(config.DB))
+	}
+	r.config = config
+}
+
+func httpSettings(httpConf configuration.HTTPConf) internalhttp.Settings {
+	return internalhttp.Settings{
+		ReadTimeout:  httpConf.ReadTimeout,
+		WriteTimeout: httpConf.WriteTimeout,
+		CORSOrigins:  httpConf.CORSOrigins,
+	}
+}
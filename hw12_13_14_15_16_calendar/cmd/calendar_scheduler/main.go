@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	configuration "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/metrics"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+	filequeue "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue/file"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories/db"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/scheduler"
+)
+
+var configFile string
+
+func init() {
+	flag.StringVar(&configFile, "config", "./configs/scheduler_config.yaml", "Path to configuration file")
+}
+
+func main() {
+	flag.Parse()
+
+	if flag.Arg(0) == "version" {
+		printVersion()
+		return
+	}
+
+	config, err := configuration.NewConfig(configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	if err := config.ValidateShared(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	logg := logger.NewWithFormat(config.Logger.Level, config.Logger.Format, os.Stdout)
+
+	if err := run(config, logg); err != nil {
+		logg.Error("scheduler error", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(config *configuration.Config, logg logger.Logger) error {
+	schedulerMetrics := metrics.New()
+
+	txManager, cleanup, err := initDatabase(config.DB, schedulerMetrics, logg)
+	if err != nil {
+		return fmt.Errorf("failed to setup database: %w", err)
+	}
+	defer cleanup()
+
+	eventRepo, err := initEventRepository(config.DB, txManager)
+	if err != nil {
+		return fmt.Errorf("failed to setup event repository: %w", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(),
+		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer cancel()
+
+	producer, err := initProducer(ctx, config.Queue, logg)
+	if err != nil {
+		return fmt.Errorf("failed to setup queue producer: %w", err)
+	}
+	defer func() {
+		if err := producer.Close(); err != nil {
+			logg.Error("failed to close queue producer", "error", err)
+		}
+	}()
+
+	notifyScheduler := scheduler.New(eventRepo, txManager, metrics.InstrumentProducer(producer, schedulerMetrics), logg, scheduler.Config{
+		Topic:     config.Queue.Topic,
+		Interval:  config.Scheduler.Interval,
+		BatchSize: config.Scheduler.BatchSize,
+	})
+
+	cleaner := scheduler.NewCleaner(eventRepo, txManager, logg, scheduler.CleanerConfig{
+		Retention:      config.Retention.Period,
+		TrashRetention: config.Retention.TrashPeriod,
+		Interval:       config.Retention.Interval,
+		BatchSize:      config.Retention.BatchSize,
+	})
+
+	metricsServer := metrics.NewServer(logg, schedulerMetrics, config.Metrics.Host+":"+config.Metrics.Port)
+
+	logg.Info("calendar scheduler is running...")
+
+	cleanerDone := make(chan error, 1)
+	go func() {
+		cleanerDone <- cleaner.Run(ctx)
+	}()
+
+	metricsDone := make(chan error, 1)
+	go func() {
+		err := metricsServer.Start(ctx)
+		// Если сервер метрик не смог запуститься, планировщик тоже останавливается
+		cancel()
+		metricsDone <- err
+	}()
+
+	err = notifyScheduler.Run(ctx)
+	cancel()
+	if cleanerErr := <-cleanerDone; err == nil {
+		err = cleanerErr
+	}
+	if metricsErr := <-metricsDone; err == nil && metricsErr != nil {
+		err = fmt.Errorf("metrics server error: %w", metricsErr)
+	}
+	return err
+}
+
+type cleanupFunc func()
+
+func initDatabase(dbConf configuration.DBConf, schedulerMetrics *metrics.Metrics, logg logger.Logger) (database.TxManager, cleanupFunc, error) {
+	switch dbConf.Type {
+	case "db", "sqlite":
+		sqlxDB, err := database.Connect(dbConf, logg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		logg.Info("database connection established")
+
+		cleanup := func() {
+			if err := sqlxDB.Close(); err != nil {
+				logg.Error("failed to close database connection", "error", err)
+			}
+		}
+
+		schedulerMetrics.RegisterDB(sqlxDB.DB)
+		return database.NewTxManagerWithObserver(sqlxDB, database.RetryPolicyFrom(dbConf.Retry), schedulerMetrics), cleanup, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown database type: %s", dbConf.Type)
+	}
+}
+
+// initEventRepository создает репозиторий событий поверх соединения txManager.
+func initEventRepository(dbConf configuration.DBConf, txManager database.TxManager) (repositories.CompositeEventRepository, error) {
+	dialect, err := db.DialectOf(dbConf.Type)
+	if err != nil {
+		return nil, err
+	}
+	return db.NewEventRepository(db.NewEventCrudRepository(txManager.GetDB(), dialect))
+}
+
+func initProducer(ctx context.Context, queueConf configuration.QueueConf, logg logger.Logger) (queue.Producer, error) {
+	switch queueConf.Type {
+	case "file":
+		logg.Info("connecting to file message broker", "path", queueConf.Path)
+		return queue.Connect(ctx, queueBackoff(queueConf.Backoff), func(context.Context) (queue.Producer, error) {
+			producer, err := filequeue.NewProducer(filequeue.Config{
+				Dir:  queueConf.Path,
+				Sync: queueConf.Sync,
+			})
+			if err != nil {
+				logg.Warn("message broker is unavailable, retrying", "error", err)
+				return nil, err
+			}
+			return producer, nil
+		})
+	default:
+		return nil, fmt.Errorf("unknown queue type: %s", queueConf.Type)
+	}
+}
+
+func queueBackoff(conf configuration.BackoffConf) queue.Backoff {
+	backoff := queue.DefaultBackoff()
+	backoff.InitialInterval = conf.InitialInterval
+	backoff.MaxInterval = conf.MaxInterval
+	backoff.MaxAttempts = conf.MaxAttempts
+	return backoff
+}
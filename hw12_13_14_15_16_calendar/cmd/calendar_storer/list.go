@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	configuration "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/storer"
+)
+
+// runList выполняет подкоманду "calendar_storer --config=... list <user_id>": выводит сохраненные
+// уведомления пользователя в out по одному JSON-объекту в строке.
+func runList(config *configuration.Config, userID string, out io.Writer) error {
+	if userID == "" {
+		return errors.New("usage: calendar_storer list <user_id>")
+	}
+
+	// Логи идут в stderr, чтобы не смешиваться с выводом уведомлений
+	logg := logger.NewWithFormat(config.Logger.Level, config.Logger.Format, os.Stderr)
+
+	sqlxDB, cleanup, err := initDatabase(config.DB, logg)
+	if err != nil {
+		return fmt.Errorf("failed to setup database: %w", err)
+	}
+	defer cleanup()
+
+	notificationRepo, err := initNotificationRepository(config.DB, sqlxDB)
+	if err != nil {
+		return fmt.Errorf("failed to setup notification repository: %w", err)
+	}
+
+	notifications, err := storer.New(notificationRepo, nil, queue.ConsumeOptions{}, logg).ListByUser(context.Background(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	encoder := json.NewEncoder(out)
+	for _, notification := range notifications {
+		if err := encoder.Encode(notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
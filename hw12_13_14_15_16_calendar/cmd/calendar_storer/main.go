@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	configuration "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+	filequeue "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue/file"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories/db"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/storer"
+	"github.com/jmoiron/sqlx"
+)
+
+var configFile string
+
+func init() {
+	flag.StringVar(&configFile, "config", "./configs/storer_config.yaml", "Path to configuration file")
+}
+
+func main() {
+	flag.Parse()
+
+	if flag.Arg(0) == "version" {
+		printVersion()
+		return
+	}
+
+	config, err := configuration.NewConfig(configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	if err := config.ValidateShared(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if flag.Arg(0) == "list" {
+		if err := runList(config, flag.Arg(1), os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "list: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	logg := logger.NewWithFormat(config.Logger.Level, config.Logger.Format, os.Stdout)
+
+	if err := run(config, logg); err != nil {
+		logg.Error("storer error", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(config *configuration.Config, logg logger.Logger) error {
+	sqlxDB, cleanup, err := initDatabase(config.DB, logg)
+	if err != nil {
+		return fmt.Errorf("failed to setup database: %w", err)
+	}
+	defer cleanup()
+
+	notificationRepo, err := initNotificationRepository(config.DB, sqlxDB)
+	if err != nil {
+		return fmt.Errorf("failed to setup notification repository: %w", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(),
+		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer cancel()
+
+	consumer, err := initConsumer(ctx, config.Queue, logg)
+	if err != nil {
+		return fmt.Errorf("failed to setup queue consumer: %w", err)
+	}
+	defer func() {
+		if err := consumer.Close(); err != nil {
+			logg.Error("failed to close queue consumer", "error", err)
+		}
+	}()
+
+	deadLetter, err := initDeadLetterProducer(config.Queue, logg)
+	if err != nil {
+		return fmt.Errorf("failed to setup dead letter producer: %w", err)
+	}
+	defer func() {
+		if err := deadLetter.Close(); err != nil {
+			logg.Error("failed to close dead letter producer", "error", err)
+		}
+	}()
+
+	notificationStorer := storer.New(notificationRepo, consumer, queue.ConsumeOptions{
+		Backoff:         queueBackoff(config.Queue.Backoff),
+		MaxAttempts:     config.Queue.MaxDeliveries,
+		DeadLetter:      deadLetter,
+		DeadLetterTopic: config.Queue.DeadLetterTopic,
+	}, logg)
+
+	logg.Info("calendar storer is running...")
+
+	return notificationStorer.Run(ctx)
+}
+
+type cleanupFunc func()
+
+func initDatabase(dbConf configuration.DBConf, logg logger.Logger) (*sqlx.DB, cleanupFunc, error) {
+	switch dbConf.Type {
+	case "db", "sqlite":
+		sqlxDB, err := database.Connect(dbConf, logg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		logg.Info("database connection established")
+
+		cleanup := func() {
+			if err := sqlxDB.Close(); err != nil {
+				logg.Error("failed to close database connection", "error", err)
+			}
+		}
+
+		return sqlxDB, cleanup, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown database type: %s", dbConf.Type)
+	}
+}
+
+func initNotificationRepository(dbConf configuration.DBConf, sqlxDB *sqlx.DB) (repositories.NotificationRepository, error) {
+	dialect, err := db.DialectOf(dbConf.Type)
+	if err != nil {
+		return nil, err
+	}
+	return db.NewNotificationRepository(sqlxDB, dialect), nil
+}
+
+func initConsumer(ctx context.Context, queueConf configuration.QueueConf, logg logger.Logger) (queue.Consumer, error) {
+	switch queueConf.Type {
+	case "file":
+		logg.Info("connecting to file message broker", "path", queueConf.Path)
+		return queue.Connect(ctx, queueBackoff(queueConf.Backoff), func(context.Context) (queue.Consumer, error) {
+			consumer, err := filequeue.NewConsumer(filequeue.Config{
+				Dir:          queueConf.Path,
+				PollInterval: queueConf.PollInterval,
+			}, queueConf.Topic, queueConf.Group)
+			if err != nil {
+				logg.Warn("message broker is unavailable, retrying", "error", err)
+				return nil, err
+			}
+			return consumer, nil
+		})
+	default:
+		return nil, fmt.Errorf("unknown queue type: %s", queueConf.Type)
+	}
+}
+
+// initDeadLetterProducer создает производителя для сообщений, которые хранитель не смог обработать.
+func initDeadLetterProducer(queueConf configuration.QueueConf, logg logger.Logger) (queue.Producer, error) {
+	switch queueConf.Type {
+	case "file":
+		logg.Info("rejected messages go to dead letter topic", "topic", queueConf.DeadLetterTopic)
+		return filequeue.NewProducer(filequeue.Config{
+			Dir:  queueConf.Path,
+			Sync: queueConf.Sync,
+		})
+	default:
+		return nil, fmt.Errorf("unknown queue type: %s", queueConf.Type)
+	}
+}
+
+func queueBackoff(conf configuration.BackoffConf) queue.Backoff {
+	backoff := queue.DefaultBackoff()
+	backoff.InitialInterval = conf.InitialInterval
+	backoff.MaxInterval = conf.MaxInterval
+	backoff.MaxAttempts = conf.MaxAttempts
+	return backoff
+}
@@ -11,37 +11,76 @@ import (
 
 // Линтер так настоял
 
-const (
-	appName                = "app: "
-	failedSendNotification = "app: failed to send notification: "
-)
+const failedSendNotification = "failed to send notification"
 
 type Application interface {
 	CreateEvent(ctx context.Context, event events.Event) (*events.Event, error)
+	// UpdateEvent и DeleteEvent проверяют версию события, если она передана (не 0):
+	// при несовпадении возвращается services.ErrVersionMismatch.
 	UpdateEvent(ctx context.Context, id string, event events.Event) (*events.Event, error)
-	DeleteEvent(ctx context.Context, id string) error
+	// PatchEvent частично изменяет событие, см. services.EventService.PatchEvent.
+	PatchEvent(ctx context.Context, id string, patch events.EventPatch, version int64) (*events.Event, error)
+	// DeleteEvent переносит событие в корзину.
+	DeleteEvent(ctx context.Context, id string, version int64) error
+	// FindTrash возвращает события в корзине, последние удаленные - первыми.
+	FindTrash(ctx context.Context, userID string) ([]events.Event, error)
+	// RestoreEvent возвращает событие из корзины или services.ErrDateBusy, если его время занято.
+	RestoreEvent(ctx context.Context, id string) (*events.Event, error)
 	GetEventByID(ctx context.Context, id string) (*events.Event, error)
-	FindEvent(ctx context.Context, userID string, startFrom, startTo, endFrom, endTo *time.Time) ([]events.Event, error)
+	// FindEvent возвращает страницу событий и курсор следующей страницы, nil - на последней.
+	FindEvent(
+		ctx context.Context, userID string, startFrom, startTo, endFrom, endTo *time.Time, page events.Page,
+	) ([]events.Event, *events.Cursor, error)
+	// FindDayEvents, FindWeekEvents и FindMonthEvents возвращают события, пересекающиеся
+	// с периодом, содержащим date. Границы периода считаются в часовом поясе date.
+	FindDayEvents(ctx context.Context, userID string, date time.Time) ([]events.Event, error)
+	FindWeekEvents(ctx context.Context, userID string, date time.Time) ([]events.Event, error)
+	FindMonthEvents(ctx context.Context, userID string, date time.Time) ([]events.Event, error)
+	// ExportEvents возвращает события пользователя, серии - одной записью без разворачивания.
+	ExportEvents(ctx context.Context, userID string) ([]events.Event, error)
+	// ImportEvents создает события пользователя по одному. Ошибки сервиса по отдельному событию
+	// (например, занятое время) попадают в результат и не прерывают импорт, прочие ошибки
+	// прерывают его, уже созданные события при этом остаются.
+	ImportEvents(ctx context.Context, userID string, imported []events.Event) ([]ImportResult, error)
+}
+
+// ImportResult - итог импорта одного события: созданное событие или ошибка сервиса.
+type ImportResult struct {
+	Event *events.Event
+	Err   error
 }
+
 type App struct {
 	eventService  services.EventService
 	notifyService services.NotificationService
 	logger        logger.Logger
+	weekStart     time.Weekday
 }
 
-func New(eventService services.EventService, notifyService services.NotificationService, log logger.Logger) *App {
+func New(
+	eventService services.EventService,
+	notifyService services.NotificationService,
+	log logger.Logger,
+	weekStart time.Weekday,
+) *App {
 	return &App{
 		eventService:  eventService,
 		notifyService: notifyService,
-		logger:        log,
+		logger:        log.With(logger.ComponentKey, "app"),
+		weekStart:     weekStart,
 	}
 }
 
+// log возвращает логгер с идентификатором текущего запроса.
+func (a *App) log(ctx context.Context) logger.Logger {
+	return logger.WithContext(ctx, a.logger)
+}
+
 func (a *App) CreateEvent(ctx context.Context, event events.Event) (*events.Event, error) {
-	a.logger.Debug(appName + "creating event " + event.ID)
+	a.log(ctx).Debug("creating event", "user_id", event.UserID)
 	createdEvent, err := a.eventService.CreateEvent(ctx, event)
 	if err != nil {
-		a.logger.Error(appName + "failed to create event: " + err.Error())
+		a.log(ctx).Error("failed to create event", "error", err)
 		return nil, err
 	}
 
@@ -49,19 +88,19 @@ func (a *App) CreateEvent(ctx context.Context, event events.Event) (*events.Even
 	if a.notifyService != nil {
 		if err := a.notifyService.NotifyEventCreated(ctx, event); err != nil {
 			// Логируем ошибку, но не прерываем выполнение
-			a.logger.Warn(failedSendNotification + err.Error())
+			a.log(ctx).Warn(failedSendNotification, "error", err)
 		}
 	}
 
-	a.logger.Info(appName + "event created successfully: " + event.ID)
+	a.log(ctx).Info("event created successfully", "event_id", createdEvent.ID)
 	return createdEvent, nil
 }
 
 func (a *App) UpdateEvent(ctx context.Context, id string, event events.Event) (*events.Event, error) {
-	a.logger.Debug(appName + "updating event " + id)
+	a.log(ctx).Debug("updating event", "event_id", id)
 	updatedEvent, err := a.eventService.UpdateEvent(ctx, id, event)
 	if err != nil {
-		a.logger.Error(appName + "failed to update event: " + err.Error())
+		a.log(ctx).Error("failed to update event", "event_id", id, "error", err)
 		return nil, err
 	}
 
@@ -69,39 +108,182 @@ func (a *App) UpdateEvent(ctx context.Context, id string, event events.Event) (*
 	if a.notifyService != nil {
 		event.ID = id
 		if err := a.notifyService.NotifyEventUpdated(ctx, event); err != nil {
-			a.logger.Warn(failedSendNotification + err.Error())
+			a.log(ctx).Warn(failedSendNotification, "error", err)
 		}
 	}
 
-	a.logger.Info(appName + "event updated successfully: " + id)
+	a.log(ctx).Info("event updated successfully", "event_id", id)
 	return updatedEvent, nil
 }
 
-func (a *App) DeleteEvent(ctx context.Context, id string) error {
-	a.logger.Debug(appName + "deleting event " + id)
+func (a *App) PatchEvent(ctx context.Context, id string, patch events.EventPatch, version int64) (*events.Event, error) {
+	a.log(ctx).Debug("patching event", "event_id", id)
+	patchedEvent, err := a.eventService.PatchEvent(ctx, id, patch, version)
+	if err != nil {
+		a.log(ctx).Error("failed to patch event", "event_id", id, "error", err)
+		return nil, err
+	}
+
+	// Отправка уведомления (если сервис доступен)
+	if a.notifyService != nil {
+		if err := a.notifyService.NotifyEventUpdated(ctx, *patchedEvent); err != nil {
+			a.log(ctx).Warn(failedSendNotification, "error", err)
+		}
+	}
+
+	a.log(ctx).Info("event patched successfully", "event_id", id)
+	return patchedEvent, nil
+}
+
+func (a *App) DeleteEvent(ctx context.Context, id string, version int64) error {
+	a.log(ctx).Debug("deleting event", "event_id", id)
 
-	if err := a.eventService.DeleteEvent(ctx, id); err != nil {
-		a.logger.Error(appName + "failed to delete event: " + err.Error())
+	if err := a.eventService.DeleteEvent(ctx, id, version); err != nil {
+		a.log(ctx).Error("failed to delete event", "event_id", id, "error", err)
 		return err
 	}
 
 	// Отправка уведомления (если сервис доступен)
 	if a.notifyService != nil {
 		if err := a.notifyService.NotifyEventDeleted(ctx, id); err != nil {
-			a.logger.Warn(failedSendNotification + err.Error())
+			a.log(ctx).Warn(failedSendNotification, "error", err)
 		}
 	}
 
-	a.logger.Info(appName + "event deleted successfully: " + id)
+	a.log(ctx).Info("event deleted successfully", "event_id", id)
 	return nil
 }
 
+func (a *App) FindTrash(ctx context.Context, userID string) ([]events.Event, error) {
+	a.log(ctx).Debug("finding trashed events", "user_id", userID)
+	return a.eventService.FindTrash(ctx, userID)
+}
+
+func (a *App) RestoreEvent(ctx context.Context, id string) (*events.Event, error) {
+	a.log(ctx).Debug("restoring event", "event_id", id)
+	restoredEvent, err := a.eventService.RestoreEvent(ctx, id)
+	if err != nil {
+		a.log(ctx).Error("failed to restore event", "event_id", id, "error", err)
+		return nil, err
+	}
+
+	// Восстановленное событие снова появляется в календаре, как созданное
+	if a.notifyService != nil {
+		if err := a.notifyService.NotifyEventCreated(ctx, *restoredEvent); err != nil {
+			a.log(ctx).Warn(failedSendNotification, "error", err)
+		}
+	}
+
+	a.log(ctx).Info("event restored successfully", "event_id", id)
+	return restoredEvent, nil
+}
+
 func (a *App) GetEventByID(ctx context.Context, id string) (*events.Event, error) {
-	a.logger.Debug(appName + "getting event " + id)
+	a.log(ctx).Debug("getting event", "event_id", id)
 	return a.eventService.GetEventByID(ctx, id)
 }
 
-func (a *App) FindEvent(ctx context.Context, userID string, startFrom, startTo, endFrom, endTo *time.Time) ([]events.Event, error) {
-	a.logger.Debug(appName + "finding events")
-	return a.eventService.FindEvent(ctx, userID, startFrom, startTo, endFrom, endTo)
+func (a *App) FindEvent(
+	ctx context.Context, userID string, startFrom, startTo, endFrom, endTo *time.Time, page events.Page,
+) ([]events.Event, *events.Cursor, error) {
+	a.log(ctx).Debug("finding events", "user_id", userID, "limit", page.Limit)
+	return a.eventService.FindEventPage(ctx, userID, startFrom, startTo, endFrom, endTo, page)
+}
+
+func (a *App) FindDayEvents(ctx context.Context, userID string, date time.Time) ([]events.Event, error) {
+	a.log(ctx).Debug("finding events for day", "user_id", userID, "date", date.Format(time.DateOnly))
+	return a.findInPeriod(ctx, userID, events.DayPeriod(date))
+}
+
+func (a *App) FindWeekEvents(ctx context.Context, userID string, date time.Time) ([]events.Event, error) {
+	a.log(ctx).Debug("finding events for week", "user_id", userID, "date", date.Format(time.DateOnly))
+	return a.findInPeriod(ctx, userID, events.WeekPeriod(date, a.weekStart))
+}
+
+func (a *App) FindMonthEvents(ctx context.Context, userID string, date time.Time) ([]events.Event, error) {
+	a.log(ctx).Debug("finding events for month", "user_id", userID, "date", date.Format(time.DateOnly))
+	return a.findInPeriod(ctx, userID, events.MonthPeriod(date))
+}
+
+func (a *App) findInPeriod(ctx context.Context, userID string, period events.Period) ([]events.Event, error) {
+	// Фильтры FindEvent включают границы, а период - полуинтервал.
+	// Микросекунда - точность хранения времени в PostgreSQL.
+	startTo := period.End.Add(-time.Microsecond)
+	endFrom := period.Start
+
+	found, err := a.eventService.FindEvent(ctx, userID, nil, &startTo, &endFrom, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// События, закончившиеся ровно в начале периода, с ним не пересекаются
+	result := make([]events.Event, 0, len(found))
+	for _, event := range found {
+		if !event.EndDate.After(period.Start) && !event.StartDate.Equal(period.Start) {
+			continue
+		}
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+func (a *App) ExportEvents(ctx context.Context, userID string) ([]events.Event, error) {
+	a.log(ctx).Debug("exporting events", "user_id", userID)
+
+	found, err := a.eventService.FindEvent(ctx, userID, nil, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// FindEvent разворачивает серии во вхождения, для выгрузки нужна сама серия
+	result := make([]events.Event, 0, len(found))
+	seen := make(map[string]struct{}, len(found))
+	for _, event := range found {
+		if _, ok := seen[event.ID]; ok {
+			continue
+		}
+		seen[event.ID] = struct{}{}
+
+		if event.IsRecurring() {
+			series, err := a.eventService.GetEventByID(ctx, event.ID)
+			if err != nil {
+				return nil, err
+			}
+			event = *series
+		}
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+func (a *App) ImportEvents(ctx context.Context, userID string, imported []events.Event) ([]ImportResult, error) {
+	a.log(ctx).Debug("importing events", "user_id", userID, "count", len(imported))
+
+	results := make([]ImportResult, 0, len(imported))
+	created := 0
+	for _, event := range imported {
+		event.ID = ""
+		event.UserID = userID
+
+		createdEvent, err := a.eventService.CreateEvent(ctx, event)
+		if err != nil {
+			if _, ok := services.AsError(err); !ok {
+				a.log(ctx).Error("failed to import events", "error", err)
+				return nil, err
+			}
+			results = append(results, ImportResult{Err: err})
+			continue
+		}
+
+		if a.notifyService != nil {
+			if err := a.notifyService.NotifyEventCreated(ctx, *createdEvent); err != nil {
+				a.log(ctx).Warn(failedSendNotification, "error", err)
+			}
+		}
+		results = append(results, ImportResult{Event: createdEvent})
+		created++
+	}
+
+	a.log(ctx).Info("events imported", "created", created, "total", len(imported))
+	return results, nil
 }
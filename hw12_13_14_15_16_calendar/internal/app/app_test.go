@@ -0,0 +1,126 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories/memory"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func titles(found []domain.Event) []string {
+	result := make([]string, 0, len(found))
+	for _, event := range found {
+		result = append(result, event.Title)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestApp_FindPeriodEvents(t *testing.T) {
+	ctx := context.Background()
+	repo, err := memory.NewEventRepository(memory.NewEventCrudRepository())
+	require.NoError(t, err)
+
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	create := func(title string, start, end time.Time) {
+		_, err := repo.Create(ctx, nil, domain.Event{Title: title, StartDate: start, EndDate: end, UserID: "user-1"})
+		require.NoError(t, err)
+	}
+	// Среда 11.02.2026
+	create("ends at day start", time.Date(2026, 2, 10, 23, 0, 0, 0, moscow), time.Date(2026, 2, 11, 0, 0, 0, 0, moscow))
+	create("crosses day start", time.Date(2026, 2, 10, 23, 30, 0, 0, moscow), time.Date(2026, 2, 11, 0, 30, 0, 0, moscow))
+	create("inside day", time.Date(2026, 2, 11, 10, 0, 0, 0, moscow), time.Date(2026, 2, 11, 11, 0, 0, 0, moscow))
+	create("starts at next day", time.Date(2026, 2, 12, 0, 0, 0, 0, moscow), time.Date(2026, 2, 12, 1, 0, 0, 0, moscow))
+	create("sunday", time.Date(2026, 2, 15, 12, 0, 0, 0, moscow), time.Date(2026, 2, 15, 13, 0, 0, 0, moscow))
+	create("next month", time.Date(2026, 3, 1, 12, 0, 0, 0, moscow), time.Date(2026, 3, 1, 13, 0, 0, 0, moscow))
+
+	date := time.Date(2026, 2, 11, 0, 0, 0, 0, moscow)
+	log := logger.New("ERROR", &bytes.Buffer{})
+
+	t.Run("day", func(t *testing.T) {
+		a := New(services.NewEventService(repo, nil), nil, log, time.Monday)
+		found, err := a.FindDayEvents(ctx, "user-1", date)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"crosses day start", "inside day"}, titles(found))
+	})
+
+	t.Run("week starting on monday includes sunday", func(t *testing.T) {
+		a := New(services.NewEventService(repo, nil), nil, log, time.Monday)
+		found, err := a.FindWeekEvents(ctx, "user-1", date)
+		require.NoError(t, err)
+		assert.Contains(t, titles(found), "sunday")
+	})
+
+	t.Run("week starting on sunday excludes next sunday", func(t *testing.T) {
+		a := New(services.NewEventService(repo, nil), nil, log, time.Sunday)
+		found, err := a.FindWeekEvents(ctx, "user-1", date)
+		require.NoError(t, err)
+		assert.NotContains(t, titles(found), "sunday")
+	})
+
+	t.Run("month", func(t *testing.T) {
+		a := New(services.NewEventService(repo, nil), nil, log, time.Monday)
+		found, err := a.FindMonthEvents(ctx, "user-1", date)
+		require.NoError(t, err)
+		assert.Len(t, found, 5)
+		assert.NotContains(t, titles(found), "next month")
+	})
+
+	t.Run("other time zone shifts day boundaries", func(t *testing.T) {
+		a := New(services.NewEventService(repo, nil), nil, log, time.Monday)
+		found, err := a.FindDayEvents(ctx, "user-1", time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		// 00:00-00:30 по Москве - это 21:00-21:30 UTC предыдущего дня
+		assert.Equal(t, []string{"inside day", "starts at next day"}, titles(found))
+	})
+}
+
+func TestApp_ExportImportEvents(t *testing.T) {
+	ctx := context.Background()
+	repo, err := memory.NewEventRepository(memory.NewEventCrudRepository())
+	require.NoError(t, err)
+	a := New(services.NewEventService(repo, nil), nil, logger.New("ERROR", &bytes.Buffer{}), time.Monday)
+
+	start := time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC)
+	imported := []domain.Event{
+		{ID: "foreign-uid", Title: "Standup", StartDate: start, EndDate: start.Add(15 * time.Minute), RRule: "FREQ=DAILY;COUNT=5"},
+		{Title: "Overlaps standup", StartDate: start.AddDate(0, 0, 2), EndDate: start.AddDate(0, 0, 2).Add(time.Hour)},
+		{Title: "", StartDate: start, EndDate: start.Add(time.Hour)},
+		{Title: "Review", StartDate: start.Add(2 * time.Hour), EndDate: start.Add(3 * time.Hour)},
+	}
+
+	results, err := a.ImportEvents(ctx, "user-1", imported)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	require.NoError(t, results[0].Err)
+	assert.NotEqual(t, "foreign-uid", results[0].Event.ID)
+	assert.Equal(t, "user-1", results[0].Event.UserID)
+	require.ErrorIs(t, results[1].Err, services.ErrDateBusy)
+	require.ErrorIs(t, results[2].Err, services.ErrInvalidEventTitle)
+	require.NoError(t, results[3].Err)
+
+	exported, err := a.ExportEvents(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Review", "Standup"}, titles(exported))
+	for _, event := range exported {
+		if event.Title == "Standup" {
+			assert.Equal(t, start, event.StartDate)
+			assert.Equal(t, "FREQ=DAILY;COUNT=5", event.RRule)
+		}
+	}
+
+	exported, err = a.ExportEvents(ctx, "user-2")
+	require.NoError(t, err)
+	assert.Empty(t, exported)
+}
@@ -4,31 +4,116 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Logger LoggerConf `toml:"logger" yaml:"logger"`
-	HTTP   HTTPConf   `toml:"http" yaml:"http"`
-	DB     DBConf     `toml:"database" yaml:"database"`
+	Logger    LoggerConf    `toml:"logger" yaml:"logger"`
+	HTTP      HTTPConf      `toml:"http" yaml:"http"`
+	GRPC      GRPCConf      `toml:"grpc" yaml:"grpc"`
+	Metrics   MetricsConf   `toml:"metrics" yaml:"metrics"`
+	DB        DBConf        `toml:"database" yaml:"database"`
+	Queue     QueueConf     `toml:"queue" yaml:"queue"`
+	Scheduler SchedulerConf `toml:"scheduler" yaml:"scheduler"`
+	Retention RetentionConf `toml:"retention" yaml:"retention"`
+	Calendar  CalendarConf  `toml:"calendar" yaml:"calendar"`
 }
 
 type LoggerConf struct {
 	Level string `toml:"level" yaml:"level"`
+	// Format - формат записей: "text" или "json".
+	Format string `toml:"format" yaml:"format"`
 }
 
 type HTTPConf struct {
+	Host         string        `toml:"host" yaml:"host"`
+	Port         string        `toml:"port" yaml:"port"`
+	ReadTimeout  time.Duration `toml:"read_timeout" yaml:"read_timeout"`
+	WriteTimeout time.Duration `toml:"write_timeout" yaml:"write_timeout"`
+	// CORSOrigins - разрешенные источники запросов, "*" разрешает любой.
+	CORSOrigins []string `toml:"cors_origins" yaml:"cors_origins"`
+	// DrainDelay - сколько при остановке /readyz отвечает 503 до закрытия сервера.
+	DrainDelay time.Duration `toml:"drain_delay" yaml:"drain_delay"`
+}
+
+type GRPCConf struct {
+	Host string `toml:"host" yaml:"host"`
+	Port string `toml:"port" yaml:"port"`
+}
+
+// MetricsConf - адрес, на котором процесс отдает метрики Prometheus (/metrics).
+type MetricsConf struct {
 	Host string `toml:"host" yaml:"host"`
 	Port string `toml:"port" yaml:"port"`
 }
 
 type DBConf struct {
+	// Type - хранилище событий: "memory", "db" (PostgreSQL) или "sqlite".
 	Type string `toml:"type" yaml:"type"`
-	DSN  string `toml:"dsn" yaml:"dsn"`
+	// DSN - строка подключения PostgreSQL или путь к файлу базы SQLite.
+	DSN             string        `toml:"dsn" yaml:"dsn"`
+	MaxOpenConns    int           `toml:"max_open_conns" yaml:"max_open_conns"`
+	MaxIdleConns    int           `toml:"max_idle_conns" yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `toml:"conn_max_lifetime" yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `toml:"conn_max_idle_time" yaml:"conn_max_idle_time"`
+	// MigrateOnStart - применять встроенные миграции при запуске сервиса.
+	MigrateOnStart bool `toml:"migrate_on_start" yaml:"migrate_on_start"`
+	// Persistence - сохранение хранилища memory на диск.
+	Persistence PersistenceConf `toml:"persistence" yaml:"persistence"`
+	// Retry - повтор транзакций PostgreSQL, MaxAttempts - общее число попыток.
+	Retry BackoffConf `toml:"retry" yaml:"retry"`
+}
+
+// PersistenceConf - снимок и журнал изменений хранилища memory. Пустой Path - события только в памяти.
+type PersistenceConf struct {
+	Path string `toml:"path" yaml:"path"`
+	// FSync - политика fsync журнала: "always", "interval" (раз в секунду) или "never".
+	FSync           string        `toml:"fsync" yaml:"fsync"`
+	CompactInterval time.Duration `toml:"compact_interval" yaml:"compact_interval"`
+}
+
+type QueueConf struct {
+	Type         string        `toml:"type" yaml:"type"`
+	Topic        string        `toml:"topic" yaml:"topic"`
+	Group        string        `toml:"group" yaml:"group"`
+	Path         string        `toml:"path" yaml:"path"`
+	Sync         bool          `toml:"sync" yaml:"sync"`
+	PollInterval time.Duration `toml:"poll_interval" yaml:"poll_interval"`
+	Backoff      BackoffConf   `toml:"backoff" yaml:"backoff"`
+	// MaxDeliveries - сколько раз доставлять сообщение, прежде чем отправить его в DeadLetterTopic.
+	MaxDeliveries   int    `toml:"max_deliveries" yaml:"max_deliveries"`
+	DeadLetterTopic string `toml:"dead_letter_topic" yaml:"dead_letter_topic"`
+}
+
+type BackoffConf struct {
+	InitialInterval time.Duration `toml:"initial_interval" yaml:"initial_interval"`
+	MaxInterval     time.Duration `toml:"max_interval" yaml:"max_interval"`
+	MaxAttempts     int           `toml:"max_attempts" yaml:"max_attempts"`
+}
+
+type SchedulerConf struct {
+	Interval  time.Duration `toml:"interval" yaml:"interval"`
+	BatchSize int           `toml:"batch_size" yaml:"batch_size"`
 }
 
+// RetentionConf - сроки хранения прошедших событий (Period) и событий в корзине (TrashPeriod).
+type RetentionConf struct {
+	Period      time.Duration `toml:"period" yaml:"period"`
+	TrashPeriod time.Duration `toml:"trash_period" yaml:"trash_period"`
+	Interval    time.Duration `toml:"interval" yaml:"interval"`
+	BatchSize   int           `toml:"batch_size" yaml:"batch_size"`
+}
+
+type CalendarConf struct {
+	// WeekStart - первый день недели для выборки событий за неделю ("monday", "sunday", ...).
+	WeekStart string `toml:"week_start" yaml:"week_start"`
+}
+
+// NewConfig читает конфигурацию из файла path, переопределяет ее переменными окружения CALENDAR_*,
+// заполняет значения по умолчанию и проверяет результат.
 func NewConfig(path string) (*Config, error) {
 	confData, err := os.ReadFile(path)
 	if err != nil {
@@ -52,18 +137,125 @@ func NewConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("unsupported config format: %s (supported: .yaml, .yml, .toml)", ext)
 	}
 
+	if err = applyEnv(&config, os.LookupEnv); err != nil {
+		return nil, err
+	}
+
 	if config.Logger.Level == "" {
 		config.Logger.Level = "INFO"
 	}
+	if config.Logger.Format == "" {
+		config.Logger.Format = "text"
+	}
 	if config.HTTP.Host == "" {
 		config.HTTP.Host = "localhost"
 	}
 	if config.HTTP.Port == "" {
 		config.HTTP.Port = "8080"
 	}
+	if config.HTTP.ReadTimeout == 0 {
+		config.HTTP.ReadTimeout = 10 * time.Second
+	}
+	if config.HTTP.WriteTimeout == 0 {
+		config.HTTP.WriteTimeout = 10 * time.Second
+	}
+	if len(config.HTTP.CORSOrigins) == 0 {
+		config.HTTP.CORSOrigins = []string{"*"}
+	}
+	if config.GRPC.Host == "" {
+		config.GRPC.Host = "localhost"
+	}
+	if config.GRPC.Port == "" {
+		config.GRPC.Port = "50051"
+	}
+	if config.Metrics.Host == "" {
+		config.Metrics.Host = "localhost"
+	}
+	if config.Metrics.Port == "" {
+		config.Metrics.Port = "9091"
+	}
 	if config.DB.Type == "" {
 		config.DB.Type = "memory"
 	}
+	if config.DB.MaxOpenConns == 0 {
+		config.DB.MaxOpenConns = 15
+	}
+	if config.DB.MaxIdleConns == 0 {
+		config.DB.MaxIdleConns = 5
+	}
+	if config.DB.ConnMaxLifetime == 0 {
+		config.DB.ConnMaxLifetime = 5 * time.Minute
+	}
+	if config.DB.ConnMaxIdleTime == 0 {
+		config.DB.ConnMaxIdleTime = 5 * time.Minute
+	}
+	if config.DB.Persistence.FSync == "" {
+		config.DB.Persistence.FSync = "interval"
+	}
+	if config.DB.Persistence.CompactInterval == 0 {
+		config.DB.Persistence.CompactInterval = 10 * time.Minute
+	}
+	if config.DB.Retry.MaxAttempts == 0 {
+		config.DB.Retry.MaxAttempts = 3
+	}
+	if config.DB.Retry.InitialInterval == 0 {
+		config.DB.Retry.InitialInterval = 10 * time.Millisecond
+	}
+	if config.DB.Retry.MaxInterval == 0 {
+		config.DB.Retry.MaxInterval = 200 * time.Millisecond
+	}
+	if config.Queue.Type == "" {
+		config.Queue.Type = "file"
+	}
+	if config.Queue.Topic == "" {
+		config.Queue.Topic = "notifications"
+	}
+	if config.Queue.Group == "" {
+		config.Queue.Group = "storer"
+	}
+	if config.Queue.Path == "" {
+		config.Queue.Path = "./queue"
+	}
+	if config.Queue.PollInterval == 0 {
+		config.Queue.PollInterval = 200 * time.Millisecond
+	}
+	if config.Queue.Backoff.InitialInterval == 0 {
+		config.Queue.Backoff.InitialInterval = 500 * time.Millisecond
+	}
+	if config.Queue.Backoff.MaxInterval == 0 {
+		config.Queue.Backoff.MaxInterval = 30 * time.Second
+	}
+	if config.Queue.MaxDeliveries == 0 {
+		config.Queue.MaxDeliveries = 10
+	}
+	if config.Queue.DeadLetterTopic == "" {
+		config.Queue.DeadLetterTopic = config.Queue.Topic + ".dlq"
+	}
+	if config.Scheduler.Interval == 0 {
+		config.Scheduler.Interval = time.Minute
+	}
+	if config.Scheduler.BatchSize == 0 {
+		config.Scheduler.BatchSize = 100
+	}
+	if config.Retention.Period == 0 {
+		config.Retention.Period = 365 * 24 * time.Hour
+	}
+	if config.Retention.TrashPeriod == 0 {
+		config.Retention.TrashPeriod = 30 * 24 * time.Hour
+	}
+	if config.Retention.Interval == 0 {
+		config.Retention.Interval = time.Hour
+	}
+	if config.Retention.BatchSize == 0 {
+		config.Retention.BatchSize = 1000
+	}
+	if config.Calendar.WeekStart == "" {
+		config.Calendar.WeekStart = "monday"
+	}
+
+	if err = config.Validate(); err != nil {
+		return nil, err
+	}
 
 	return &config, nil
 }
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestNewConfig_Files(t *testing.T) {
+	for _, name := range []string{"config.yaml", "config.toml", "config.prod.yaml", "scheduler_config.yaml", "storer_config.yaml"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(filepath.Join("..", "..", "configs", name))
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNewConfig_Defaults(t *testing.T) {
+	config, err := NewConfig(writeConfig(t, "config.yaml", "logger:\n  level: DEBUG\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "DEBUG", config.Logger.Level)
+	assert.Equal(t, "text", config.Logger.Format)
+	assert.Equal(t, "8080", config.HTTP.Port)
+	assert.Equal(t, "memory", config.DB.Type)
+	assert.Equal(t, 3, config.DB.Retry.MaxAttempts)
+	assert.Equal(t, time.Minute, config.Scheduler.Interval)
+	assert.Equal(t, 30*24*time.Hour, config.Retention.TrashPeriod)
+	assert.Equal(t, 10, config.Queue.MaxDeliveries)
+	assert.Equal(t, "notifications.dlq", config.Queue.DeadLetterTopic)
+}
+
+func TestNewConfig_Env(t *testing.T) {
+	path := writeConfig(t, "config.yaml", "http:\n  port: \"8080\"\ndatabase:\n  type: memory\n")
+
+	t.Setenv("CALENDAR_HTTP_PORT", "8181")
+	t.Setenv("CALENDAR_LOGGER_FORMAT", "json")
+	t.Setenv("CALENDAR_DATABASE_TYPE", "db")
+	t.Setenv("CALENDAR_DATABASE_DSN", "postgres://user:secret@db:5432/calendar")
+	t.Setenv("CALENDAR_QUEUE_SYNC", "true")
+	t.Setenv("CALENDAR_QUEUE_BACKOFF_MAX_ATTEMPTS", "5")
+	t.Setenv("CALENDAR_QUEUE_BACKOFF_MAX_INTERVAL", "1m")
+	t.Setenv("CALENDAR_HTTP_CORS_ORIGINS", "https://a.example.com, https://b.example.com")
+
+	config, err := NewConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "8181", config.HTTP.Port)
+	assert.Equal(t, "json", config.Logger.Format)
+	assert.Equal(t, "db", config.DB.Type)
+	assert.Equal(t, "postgres://user:secret@db:5432/calendar", config.DB.DSN)
+	assert.True(t, config.Queue.Sync)
+	assert.Equal(t, 5, config.Queue.Backoff.MaxAttempts)
+	assert.Equal(t, time.Minute, config.Queue.Backoff.MaxInterval)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, config.HTTP.CORSOrigins)
+}
+
+func TestNewConfig_InvalidEnv(t *testing.T) {
+	path := writeConfig(t, "config.yaml", "")
+
+	t.Setenv("CALENDAR_SCHEDULER_BATCH_SIZE", "many")
+	_, err := NewConfig(path)
+	require.ErrorContains(t, err, "CALENDAR_SCHEDULER_BATCH_SIZE")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr []string
+	}{
+		{
+			name:    "unknown db type",
+			env:     map[string]string{"CALENDAR_DATABASE_TYPE": "mongo"},
+			wantErr: []string{`database.type: unsupported value "mongo"`},
+		},
+		{
+			name:    "empty dsn",
+			env:     map[string]string{"CALENDAR_DATABASE_TYPE": "db"},
+			wantErr: []string{"database.dsn: must be set"},
+		},
+		{
+			name:    "empty sqlite path",
+			env:     map[string]string{"CALENDAR_DATABASE_TYPE": "sqlite"},
+			wantErr: []string{`database.dsn: must be set when database.type is "sqlite"`},
+		},
+		{
+			name:    "negative retry attempts",
+			env:     map[string]string{"CALENDAR_DATABASE_RETRY_MAX_ATTEMPTS": "-1"},
+			wantErr: []string{"database.retry.max_attempts: must be positive"},
+		},
+		{
+			name:    "invalid ports",
+			env:     map[string]string{"CALENDAR_HTTP_PORT": "http", "CALENDAR_GRPC_PORT": "70000"},
+			wantErr: []string{`http.port: invalid port "http"`, `grpc.port: invalid port "70000"`},
+		},
+		{
+			name:    "invalid logger",
+			env:     map[string]string{"CALENDAR_LOGGER_LEVEL": "TRACE", "CALENDAR_LOGGER_FORMAT": "xml"},
+			wantErr: []string{"logger.level", "logger.format"},
+		},
+		{
+			name:    "negative interval",
+			env:     map[string]string{"CALENDAR_SCHEDULER_INTERVAL": "-1s"},
+			wantErr: []string{"scheduler.interval: must be positive"},
+		},
+		{
+			name: "invalid dead letter settings",
+			env: map[string]string{
+				"CALENDAR_QUEUE_MAX_DELIVERIES":    "-1",
+				"CALENDAR_QUEUE_DEAD_LETTER_TOPIC": "notifications",
+			},
+			wantErr: []string{"queue.max_deliveries: must be positive", "queue.dead_letter_topic: must differ"},
+		},
+		{
+			name:    "queue in memory",
+			env:     map[string]string{"CALENDAR_QUEUE_TYPE": "memory"},
+			wantErr: []string{`queue.type: unsupported value "memory" (supported: file)`},
+		},
+		{
+			name:    "negative retention period",
+			env:     map[string]string{"CALENDAR_RETENTION_PERIOD": "-8760h"},
+			wantErr: []string{"retention.period: must be positive"},
+		},
+		{
+			name:    "negative trash period",
+			env:     map[string]string{"CALENDAR_RETENTION_TRASH_PERIOD": "-1h"},
+			wantErr: []string{"retention.trash_period: must be positive"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			_, err := NewConfig(writeConfig(t, "config.yaml", ""))
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateShared(t *testing.T) {
+	config, err := NewConfig(writeConfig(t, "config.yaml", ""))
+	require.NoError(t, err)
+
+	err = config.ValidateShared()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `database.type: "memory" is not shared`)
+
+	t.Setenv("CALENDAR_DATABASE_TYPE", "sqlite")
+	t.Setenv("CALENDAR_DATABASE_DSN", "./calendar.db")
+	config, err = NewConfig(writeConfig(t, "config.yaml", ""))
+	require.NoError(t, err)
+	require.NoError(t, config.ValidateShared())
+}
+
+func TestConfig_Reload(t *testing.T) {
+	current, err := NewConfig(writeConfig(t, "config.yaml", "logger:\n  level: INFO\nhttp:\n  port: \"8080\"\n"))
+	require.NoError(t, err)
+
+	next, err := NewConfig(writeConfig(t, "config.yaml", `
+logger:
+  level: DEBUG
+  format: json
+http:
+  port: "8181"
+  read_timeout: 30s
+  cors_origins: ["https://calendar.example.com"]
+database:
+  max_open_conns: 30
+`))
+	require.NoError(t, err)
+
+	result, changes, restart := current.Reload(next)
+
+	assert.ElementsMatch(t, []string{"logger.format", "http.port"}, restart)
+	assert.ElementsMatch(t, []Change{
+		{Field: "logger.level", Old: "INFO", New: "DEBUG"},
+		{Field: "http.read_timeout", Old: 10 * time.Second, New: 30 * time.Second},
+		{Field: "http.cors_origins", Old: []string{"*"}, New: []string{"https://calendar.example.com"}},
+		{Field: "database.max_open_conns", Old: 15, New: 30},
+	}, changes)
+
+	assert.Equal(t, "DEBUG", result.Logger.Level)
+	assert.Equal(t, "text", result.Logger.Format)
+	assert.Equal(t, "8080", result.HTTP.Port)
+	assert.Equal(t, 30*time.Second, result.HTTP.ReadTimeout)
+	assert.Equal(t, 30, result.DB.MaxOpenConns)
+	assert.Equal(t, "INFO", current.Logger.Level)
+
+	_, changes, restart = result.Reload(result)
+	assert.Empty(t, changes)
+	assert.Empty(t, restart)
+}
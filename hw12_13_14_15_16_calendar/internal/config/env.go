@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvPrefix - префикс переменных окружения, переопределяющих конфигурацию.
+// Имя переменной складывается из yaml-тегов полей: database.dsn -> CALENDAR_DATABASE_DSN,
+// queue.backoff.max_attempts -> CALENDAR_QUEUE_BACKOFF_MAX_ATTEMPTS. Списки задаются через запятую.
+const EnvPrefix = "CALENDAR"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv переопределяет поля config значениями переменных окружения, найденными через lookup.
+func applyEnv(config *Config, lookup func(string) (string, bool)) error {
+	return applyEnvToStruct(reflect.ValueOf(config).Elem(), EnvPrefix, lookup)
+}
+
+func applyEnvToStruct(v reflect.Value, prefix string, lookup func(string) (string, bool)) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		name := prefix + "_" + strings.ToUpper(tag)
+		value := v.Field(i)
+
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnvToStruct(value, name, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		raw, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := setValue(value, raw); err != nil {
+			return fmt.Errorf("invalid value of %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func setValue(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type %s", v.Type())
+		}
+		items := strings.Split(raw, ",")
+		for i := range items {
+			items[i] = strings.TrimSpace(items[i])
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// reloadable - параметры, которые можно менять без перезапуска процесса.
+var reloadable = map[string]bool{
+	"logger.level":                true,
+	"http.read_timeout":           true,
+	"http.write_timeout":          true,
+	"http.cors_origins":           true,
+	"database.max_open_conns":     true,
+	"database.max_idle_conns":     true,
+	"database.conn_max_lifetime":  true,
+	"database.conn_max_idle_time": true,
+}
+
+// Change - измененный параметр конфигурации.
+type Change struct {
+	Field string
+	Old   any
+	New   any
+}
+
+// Reload сравнивает текущую конфигурацию с перечитанной next и переносит из next только параметры,
+// которые можно менять без перезапуска. Возвращает итоговую конфигурацию, примененные изменения
+// и имена измененных параметров, для которых нужен перезапуск (они остаются прежними).
+func (c *Config) Reload(next *Config) (*Config, []Change, []string) {
+	result := *c
+	var (
+		changes []Change
+		restart []string
+	)
+	reloadStruct(reflect.ValueOf(&result).Elem(), reflect.ValueOf(next).Elem(), "", &changes, &restart)
+	return &result, changes, restart
+}
+
+func reloadStruct(current, next reflect.Value, prefix string, changes *[]Change, restart *[]string) {
+	t := current.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		name := tag
+		if prefix != "" {
+			name = prefix + "." + tag
+		}
+		oldValue, newValue := current.Field(i), next.Field(i)
+
+		if oldValue.Kind() == reflect.Struct {
+			reloadStruct(oldValue, newValue, name, changes, restart)
+			continue
+		}
+		if reflect.DeepEqual(oldValue.Interface(), newValue.Interface()) {
+			continue
+		}
+		if !reloadable[name] {
+			*restart = append(*restart, name)
+			continue
+		}
+		*changes = append(*changes, Change{Field: name, Old: oldValue.Interface(), New: newValue.Interface()})
+		oldValue.Set(newValue)
+	}
+}
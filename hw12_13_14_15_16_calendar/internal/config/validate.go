@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	dbTypes    = []string{"memory", "db", "sqlite"}
+	queueTypes = []string{"file"}
+	logLevels  = []string{"DEBUG", "INFO", "WARN", "WARNING", "ERROR"}
+	logFormats = []string{"text", "json"}
+	fsyncModes = []string{"always", "interval", "never"}
+)
+
+// Validate проверяет итоговую конфигурацию и возвращает все найденные ошибки разом.
+func (c *Config) Validate() error {
+	errs := []error{
+		oneOf("logger.level", strings.ToUpper(c.Logger.Level), logLevels),
+		oneOf("logger.format", strings.ToLower(c.Logger.Format), logFormats),
+		validPort("http.port", c.HTTP.Port),
+		positive("http.read_timeout", c.HTTP.ReadTimeout),
+		positive("http.write_timeout", c.HTTP.WriteTimeout),
+		validPort("grpc.port", c.GRPC.Port),
+		validPort("metrics.port", c.Metrics.Port),
+		oneOf("database.type", c.DB.Type, dbTypes),
+		oneOf("database.persistence.fsync", c.DB.Persistence.FSync, fsyncModes),
+		oneOf("queue.type", c.Queue.Type, queueTypes),
+		positive("queue.poll_interval", c.Queue.PollInterval),
+		positive("scheduler.interval", c.Scheduler.Interval),
+		positive("retention.period", c.Retention.Period),
+		positive("retention.trash_period", c.Retention.TrashPeriod),
+		positive("retention.interval", c.Retention.Interval),
+	}
+	if (c.DB.Type == "db" || c.DB.Type == "sqlite") && c.DB.DSN == "" {
+		errs = append(errs, fmt.Errorf("database.dsn: must be set when database.type is %q", c.DB.Type))
+	}
+	if c.HTTP.DrainDelay < 0 {
+		errs = append(errs, fmt.Errorf("http.drain_delay: must not be negative, got %s", c.HTTP.DrainDelay))
+	}
+	if c.DB.MaxOpenConns <= 0 {
+		errs = append(errs, fmt.Errorf("database.max_open_conns: must be positive, got %d", c.DB.MaxOpenConns))
+	}
+	if c.DB.MaxIdleConns < 0 {
+		errs = append(errs, fmt.Errorf("database.max_idle_conns: must not be negative, got %d", c.DB.MaxIdleConns))
+	}
+	if c.DB.Retry.MaxAttempts <= 0 {
+		errs = append(errs, fmt.Errorf("database.retry.max_attempts: must be positive, got %d", c.DB.Retry.MaxAttempts))
+	}
+	if c.Queue.MaxDeliveries <= 0 {
+		errs = append(errs, fmt.Errorf("queue.max_deliveries: must be positive, got %d", c.Queue.MaxDeliveries))
+	}
+	if c.Queue.DeadLetterTopic == c.Queue.Topic {
+		errs = append(errs, fmt.Errorf("queue.dead_letter_topic: must differ from queue.topic %q", c.Queue.Topic))
+	}
+	if c.Scheduler.BatchSize <= 0 {
+		errs = append(errs, fmt.Errorf("scheduler.batch_size: must be positive, got %d", c.Scheduler.BatchSize))
+	}
+	if c.Retention.BatchSize <= 0 {
+		errs = append(errs, fmt.Errorf("retention.batch_size: must be positive, got %d", c.Retention.BatchSize))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	return nil
+}
+
+// ValidateShared запрещает хранилище в памяти: его не видят другие процессы.
+func (c *Config) ValidateShared() error {
+	if c.DB.Type == "memory" {
+		return errors.New(`invalid config: database.type: "memory" is not shared between processes, use "db" or "sqlite"`)
+	}
+	return nil
+}
+
+func oneOf(name, value string, allowed []string) error {
+	if slices.Contains(allowed, value) {
+		return nil
+	}
+	return fmt.Errorf("%s: unsupported value %q (supported: %s)", name, value, strings.Join(allowed, ", "))
+}
+
+func validPort(name, value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("%s: invalid port %q (expected number 1-65535)", name, value)
+	}
+	return nil
+}
+
+func positive(name string, value time.Duration) error {
+	if value <= 0 {
+		return fmt.Errorf("%s: must be positive, got %s", name, value)
+	}
+	return nil
+}
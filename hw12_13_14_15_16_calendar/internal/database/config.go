@@ -0,0 +1,37 @@
+package database
+
+import (
+	configuration "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Connect подключается к PostgreSQL или открывает файл SQLite в зависимости от database.type.
+func Connect(dbConf configuration.DBConf, logg logger.Logger) (*sqlx.DB, error) {
+	if dbConf.Type == "sqlite" {
+		logg.Info("opening SQLite database...", "path", dbConf.DSN)
+		return NewSQLiteConnection(ConnectionConfigFrom(dbConf))
+	}
+	logg.Info("connecting to PostgreSQL database...")
+	return NewConnection(ConnectionConfigFrom(dbConf))
+}
+
+// ConnectionConfigFrom возвращает настройки соединения из раздела database конфигурации.
+func ConnectionConfigFrom(dbConf configuration.DBConf) ConnectionConfig {
+	return ConnectionConfig{
+		DSN:             dbConf.DSN,
+		MaxOpenConns:    dbConf.MaxOpenConns,
+		MaxIdleConns:    dbConf.MaxIdleConns,
+		ConnMaxLifetime: dbConf.ConnMaxLifetime,
+		ConnMaxIdleTime: dbConf.ConnMaxIdleTime,
+	}
+}
+
+// RetryPolicyFrom возвращает политику повторов транзакций из database.retry.
+func RetryPolicyFrom(conf configuration.BackoffConf) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     conf.MaxAttempts,
+		InitialInterval: conf.InitialInterval,
+		MaxInterval:     conf.MaxInterval,
+	}
+}
@@ -0,0 +1,26 @@
+package database_test
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	configuration "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnect_PoolSettings(t *testing.T) {
+	sqlxDB, err := database.Connect(configuration.DBConf{
+		Type:         "sqlite",
+		DSN:          filepath.Join(t.TempDir(), "calendar.db"),
+		MaxOpenConns: 3,
+		MaxIdleConns: 1,
+	}, logger.New("ERROR", io.Discard))
+	require.NoError(t, err)
+	defer sqlxDB.Close()
+
+	assert.Equal(t, 3, sqlxDB.Stats().MaxOpenConnections)
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -32,10 +33,7 @@ func NewConnection(config ConnectionConfig) (*sqlx.DB, error) {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	db.SetMaxOpenConns(config.MaxOpenConns)
-	db.SetMaxIdleConns(config.MaxIdleConns)
-	db.SetConnMaxLifetime(config.ConnMaxLifetime)
-	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+	ApplyPoolSettings(db.DB, config)
 
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
@@ -43,3 +41,11 @@ func NewConnection(config ConnectionConfig) (*sqlx.DB, error) {
 
 	return db, nil
 }
+
+// ApplyPoolSettings задает параметры пула соединений; безопасно вызывать на работающем пуле.
+func ApplyPoolSettings(db *sql.DB, config ConnectionConfig) {
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/migrations"
+	sqlitemigrations "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/migrations/sqlite"
+	"github.com/pressly/goose/v3"
+)
+
+// Команды миграций, как у goose.
+const (
+	MigrateUp     = "up"
+	MigrateDown   = "down"
+	MigrateStatus = "status"
+	MigrateRedo   = "redo"
+)
+
+// Диалекты SQL: у каждого свой набор миграций.
+const (
+	DialectPostgres = "postgres"
+	DialectSQLite   = "sqlite"
+)
+
+var (
+	// ErrUnknownMigrateCommand - команда миграций не поддерживается.
+	ErrUnknownMigrateCommand = errors.New("unknown migrate command")
+	// ErrUnknownDialect - для диалекта нет миграций.
+	ErrUnknownDialect = errors.New("unknown database dialect")
+)
+
+// Migrate выполняет команду command над встроенными миграциями и пишет результат в out:
+// up применяет все новые миграции, down откатывает последнюю, redo откатывает и применяет
+// последнюю заново, status выводит состояние каждой миграции. Набор миграций выбирается по dialect.
+func Migrate(ctx context.Context, db *sql.DB, dialect, command string, out io.Writer) error {
+	gooseDialect, fsys, err := dialectMigrations(dialect)
+	if err != nil {
+		return err
+	}
+	provider, err := goose.NewProvider(gooseDialect, db, fsys)
+	if err != nil {
+		return fmt.Errorf("failed to create migration provider: %w", err)
+	}
+
+	switch command {
+	case MigrateUp:
+		results, err := provider.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(out, "no migrations to apply")
+		}
+		for _, result := range results {
+			fmt.Fprintln(out, result)
+		}
+	case MigrateDown:
+		result, err := provider.Down(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to roll back migration: %w", err)
+		}
+		fmt.Fprintln(out, result)
+	case MigrateRedo:
+		result, err := provider.Down(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to roll back migration: %w", err)
+		}
+		fmt.Fprintln(out, result)
+		if result, err = provider.UpByOne(ctx); err != nil {
+			return fmt.Errorf("failed to apply migration: %w", err)
+		}
+		fmt.Fprintln(out, result)
+	case MigrateStatus:
+		statuses, err := provider.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get migration status: %w", err)
+		}
+		fmt.Fprintf(out, "%-30s %s\n", "Applied At", "Migration")
+		for _, status := range statuses {
+			appliedAt := "Pending"
+			if status.State == goose.StateApplied {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Fprintf(out, "%-30s %s\n", appliedAt, status.Source.Path)
+		}
+	default:
+		return fmt.Errorf("%w %q (supported: up, down, redo, status)", ErrUnknownMigrateCommand, command)
+	}
+	return nil
+}
+
+func dialectMigrations(dialect string) (goose.Dialect, fs.FS, error) {
+	switch dialect {
+	case DialectPostgres:
+		return goose.DialectPostgres, migrations.FS, nil
+	case DialectSQLite:
+		return goose.DialectSQLite3, sqlitemigrations.FS, nil
+	default:
+		return "", nil, fmt.Errorf("%w %q (supported: %s, %s)", ErrUnknownDialect, dialect, DialectPostgres, DialectSQLite)
+	}
+}
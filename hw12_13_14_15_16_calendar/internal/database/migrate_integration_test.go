@@ -0,0 +1,46 @@
+//go:build integration
+// +build integration
+
+package database_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/testhelpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate_WithTestcontainers(t *testing.T) {
+	pc := testhelpers.SetupPostgresContainer(t, "calendar_migrate_test")
+	db := pc.DB.DB
+	ctx := context.Background()
+
+	status := func() string {
+		var out bytes.Buffer
+		require.NoError(t, database.Migrate(ctx, db, database.DialectPostgres, database.MigrateStatus, &out))
+		return out.String()
+	}
+
+	require.NotContains(t, status(), "Pending")
+
+	var out bytes.Buffer
+	require.NoError(t, database.Migrate(ctx, db, database.DialectPostgres, database.MigrateUp, &out))
+	require.Equal(t, "no migrations to apply\n", out.String())
+
+	require.NoError(t, database.Migrate(ctx, db, database.DialectPostgres, database.MigrateDown, io.Discard))
+	require.Equal(t, 1, strings.Count(status(), "Pending"))
+
+	require.NoError(t, database.Migrate(ctx, db, database.DialectPostgres, database.MigrateUp, io.Discard))
+	out.Reset()
+	require.NoError(t, database.Migrate(ctx, db, database.DialectPostgres, database.MigrateRedo, &out))
+	require.Contains(t, out.String(), "down")
+	require.Contains(t, out.String(), "up")
+	require.NotContains(t, status(), "Pending")
+
+	require.ErrorIs(t, database.Migrate(ctx, db, database.DialectPostgres, "sideways", io.Discard), database.ErrUnknownMigrateCommand)
+}
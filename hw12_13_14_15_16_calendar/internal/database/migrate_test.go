@@ -0,0 +1,49 @@
+package database_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate_SQLite(t *testing.T) {
+	sqlxDB, err := database.NewSQLiteConnection(database.DefaultConnectionConfig(filepath.Join(t.TempDir(), "calendar.db")))
+	require.NoError(t, err)
+	defer sqlxDB.Close()
+
+	db := sqlxDB.DB
+	ctx := context.Background()
+
+	status := func() string {
+		var out bytes.Buffer
+		require.NoError(t, database.Migrate(ctx, db, database.DialectSQLite, database.MigrateStatus, &out))
+		return out.String()
+	}
+
+	require.Equal(t, 6, strings.Count(status(), "Pending"))
+
+	require.NoError(t, database.Migrate(ctx, db, database.DialectSQLite, database.MigrateUp, io.Discard))
+	require.NotContains(t, status(), "Pending")
+
+	var out bytes.Buffer
+	require.NoError(t, database.Migrate(ctx, db, database.DialectSQLite, database.MigrateUp, &out))
+	require.Equal(t, "no migrations to apply\n", out.String())
+
+	require.NoError(t, database.Migrate(ctx, db, database.DialectSQLite, database.MigrateDown, io.Discard))
+	require.Equal(t, 1, strings.Count(status(), "Pending"))
+
+	out.Reset()
+	require.NoError(t, database.Migrate(ctx, db, database.DialectSQLite, database.MigrateRedo, &out))
+	require.Contains(t, out.String(), "down")
+	require.Contains(t, out.String(), "up")
+	require.Equal(t, 1, strings.Count(status(), "Pending"))
+
+	require.ErrorIs(t, database.Migrate(ctx, db, database.DialectSQLite, "sideways", io.Discard), database.ErrUnknownMigrateCommand)
+	require.ErrorIs(t, database.Migrate(ctx, db, "oracle", database.MigrateUp, io.Discard), database.ErrUnknownDialect)
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite" // SQLite driver
+)
+
+// SQLiteDriver - имя драйвера SQLite в database/sql.
+const SQLiteDriver = "sqlite"
+
+// sqliteDefaults - параметры соединения, которые добавляются к DSN, если не заданы в нем явно:
+// ожидание блокировки вместо ошибки SQLITE_BUSY, WAL для чтения параллельно с записью,
+// проверка внешних ключей и захват блокировки записи в начале транзакции.
+var sqliteDefaults = []struct{ key, param string }{
+	{"busy_timeout", "_pragma=busy_timeout(5000)"},
+	{"journal_mode", "_pragma=journal_mode(WAL)"},
+	{"foreign_keys", "_pragma=foreign_keys(1)"},
+	{"_txlock", "_txlock=immediate"},
+}
+
+func init() {
+	sqlx.BindDriver(SQLiteDriver, sqlx.QUESTION)
+}
+
+// NewSQLiteConnection открывает файл базы SQLite; config.DSN - путь к файлу,
+// допускаются параметры драйвера modernc.org/sqlite после "?".
+func NewSQLiteConnection(config ConnectionConfig) (*sqlx.DB, error) {
+	db, err := sqlx.Open(SQLiteDriver, sqliteDSN(config.DSN))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	ApplyPoolSettings(db.DB, config)
+
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return db, nil
+}
+
+func sqliteDSN(dsn string) string {
+	for _, def := range sqliteDefaults {
+		if strings.Contains(dsn, def.key) {
+			continue
+		}
+		if strings.Contains(dsn, "?") {
+			dsn += "&" + def.param
+		} else {
+			dsn += "?" + def.param
+		}
+	}
+	return dsn
+}
@@ -3,7 +3,10 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -12,37 +15,133 @@ type contextKey string
 
 const txKey contextKey = "tx"
 
+// Коды SQLSTATE, после которых транзакцию можно повторить.
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
 type TxManager interface {
+	// WithTransaction выполняет fn в транзакции, вызов внутри открытой транзакции - в ней же.
 	WithTransaction(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx *sqlx.Tx) error) error
 	GetDB() *sqlx.DB
 }
 
+// TxObserver получает итоги транзакций: фиксацию или откат каждой попытки и каждый повтор.
+// Точки сохранения вложенных вызовов транзакциями не считаются.
+type TxObserver interface {
+	TransactionFinished(committed bool)
+	TransactionRetried()
+}
+
+type noopObserver struct{}
+
+func (noopObserver) TransactionFinished(bool) {}
+func (noopObserver) TransactionRetried()      {}
+
+// RetryPolicy - повтор транзакции с паузой, растущей вдвое от InitialInterval до MaxInterval.
+type RetryPolicy struct {
+	MaxAttempts     int
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     3,
+		InitialInterval: 10 * time.Millisecond,
+		MaxInterval:     200 * time.Millisecond,
+	}
+}
+
+// backoff возвращает паузу перед попыткой с номером attempt+1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialInterval
+	for i := 1; i < attempt && (p.MaxInterval <= 0 || d < p.MaxInterval); i++ {
+		d *= 2
+	}
+	if p.MaxInterval > 0 && d > p.MaxInterval {
+		d = p.MaxInterval
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1) //nolint:gosec // разброс пауз не требует криптостойкости
+}
+
+// txState - открытая транзакция в контексте.
+type txState struct {
+	db         *sqlx.DB
+	tx         *sqlx.Tx
+	savepoints int
+}
+
 type txManager struct {
-	db *sqlx.DB
+	db       *sqlx.DB
+	retry    RetryPolicy
+	observer TxObserver
 }
 
 func NewTxManager(db *sqlx.DB) TxManager {
-	return &txManager{db: db}
+	return NewTxManagerWithRetry(db, DefaultRetryPolicy())
+}
+
+func NewTxManagerWithRetry(db *sqlx.DB, retry RetryPolicy) TxManager {
+	return NewTxManagerWithObserver(db, retry, nil)
+}
+
+// NewTxManagerWithObserver создает менеджер, сообщающий observer об итогах транзакций, nil - без наблюдения.
+func NewTxManagerWithObserver(db *sqlx.DB, retry RetryPolicy, observer TxObserver) TxManager {
+	if observer == nil {
+		observer = noopObserver{}
+	}
+	return &txManager{db: db, retry: retry, observer: observer}
 }
 
 func (tm *txManager) GetDB() *sqlx.DB {
 	return tm.db
 }
 
+// WithTransaction повторяет транзакцию по RetryPolicy. Вложенный вызов - точка сохранения, его повторяет внешний.
 func (tm *txManager) WithTransaction(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx *sqlx.Tx) error) error {
+	if state, ok := ctx.Value(txKey).(*txState); ok && state.db == tm.db {
+		return withSavepoint(ctx, state, fn)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := tm.transaction(ctx, opts, fn)
+		if err == nil || !isRetryable(err) || attempt >= tm.retry.MaxAttempts {
+			return err
+		}
+
+		timer := time.NewTimer(tm.retry.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		tm.observer.TransactionRetried()
+	}
+}
+
+func (tm *txManager) transaction(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx *sqlx.Tx) error) error {
 	tx, err := tm.db.BeginTxx(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
+	committed := false
 	defer func() {
 		if p := recover(); p != nil {
 			_ = tx.Rollback()
+			tm.observer.TransactionFinished(false)
 			panic(p)
 		}
+		tm.observer.TransactionFinished(committed)
 	}()
 
-	ctx = context.WithValue(ctx, txKey, tx)
+	ctx = context.WithValue(ctx, txKey, &txState{db: tm.db, tx: tx})
 
 	if err := fn(ctx, tx); err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
@@ -55,5 +154,47 @@ func (tm *txManager) WithTransaction(ctx context.Context, opts *sql.TxOptions, f
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	committed = true
 	return nil
 }
+
+func withSavepoint(ctx context.Context, state *txState, fn func(ctx context.Context, tx *sqlx.Tx) error) error {
+	state.savepoints++
+	savepoint := fmt.Sprintf("sp_%d", state.savepoints)
+	if _, err := state.tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = state.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+			panic(p)
+		}
+	}()
+
+	if err := fn(ctx, state.tx); err != nil {
+		if _, rbErr := state.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); rbErr != nil {
+			return fmt.Errorf("savepoint rollback error: %w, original error: %w", rbErr, err)
+		}
+		return err
+	}
+
+	if _, err := state.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
+
+// isRetryable сообщает, можно ли повторить транзакцию после ошибки драйвера pgx или lib/pq.
+func isRetryable(err error) bool {
+	var sqlErr interface{ SQLState() string }
+	if !errors.As(err, &sqlErr) {
+		return false
+	}
+	switch sqlErr.SQLState() {
+	case sqlStateSerializationFailure, sqlStateDeadlockDetected:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,233 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sqlStateError - ошибка драйвера с кодом SQLSTATE.
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func openItemsDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+	db, err := database.NewSQLiteConnection(database.DefaultConnectionConfig(filepath.Join(t.TempDir(), "tx.db")))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.Exec("CREATE TABLE items (name TEXT NOT NULL)")
+	require.NoError(t, err)
+	return db
+}
+
+func insertItem(ctx context.Context, tx *sqlx.Tx, name string) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO items (name) VALUES (?)", name)
+	return err
+}
+
+func itemNames(t *testing.T, db *sqlx.DB) []string {
+	t.Helper()
+	var names []string
+	require.NoError(t, db.Select(&names, "SELECT name FROM items ORDER BY name"))
+	return names
+}
+
+func TestTxManager_Nested(t *testing.T) {
+	ctx := context.Background()
+	errNested := errors.New("nested")
+
+	t.Run("nested error rolls back savepoint only", func(t *testing.T) {
+		db := openItemsDB(t)
+		tm := database.NewTxManager(db)
+
+		err := tm.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+			if err := insertItem(ctx, tx, "outer"); err != nil {
+				return err
+			}
+			err := tm.WithTransaction(ctx, nil, func(ctx context.Context, nested *sqlx.Tx) error {
+				assert.Same(t, tx, nested, "nested call reuses the outer transaction")
+				if err := insertItem(ctx, nested, "nested"); err != nil {
+					return err
+				}
+				return errNested
+			})
+			require.ErrorIs(t, err, errNested)
+
+			return tm.WithTransaction(ctx, nil, func(ctx context.Context, nested *sqlx.Tx) error {
+				return insertItem(ctx, nested, "released")
+			})
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"outer", "released"}, itemNames(t, db))
+	})
+
+	t.Run("outer error rolls back nested changes", func(t *testing.T) {
+		db := openItemsDB(t)
+		tm := database.NewTxManager(db)
+
+		err := tm.WithTransaction(ctx, nil, func(ctx context.Context, _ *sqlx.Tx) error {
+			if err := tm.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+				return insertItem(ctx, tx, "nested")
+			}); err != nil {
+				return err
+			}
+			return errNested
+		})
+		require.ErrorIs(t, err, errNested)
+		assert.Empty(t, itemNames(t, db))
+	})
+
+	t.Run("nested panic rolls back savepoint", func(t *testing.T) {
+		db := openItemsDB(t)
+		tm := database.NewTxManager(db)
+
+		err := tm.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+			if err := insertItem(ctx, tx, "outer"); err != nil {
+				return err
+			}
+			assert.Panics(t, func() {
+				_ = tm.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+					if err := insertItem(ctx, tx, "nested"); err != nil {
+						return err
+					}
+					panic("nested panic")
+				})
+			})
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"outer"}, itemNames(t, db))
+	})
+}
+
+func TestTxManager_Retry(t *testing.T) {
+	ctx := context.Background()
+	retry := database.RetryPolicy{MaxAttempts: 3, InitialInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+
+	tests := []struct {
+		name         string
+		err          error
+		failures     int
+		wantAttempts int
+		wantErr      bool
+	}{
+		{name: "serialization failure", err: sqlStateError("40001"), failures: 2, wantAttempts: 3},
+		{name: "deadlock", err: sqlStateError("40P01"), failures: 1, wantAttempts: 2},
+		{name: "attempts exhausted", err: sqlStateError("40001"), failures: 5, wantAttempts: 3, wantErr: true},
+		{name: "other sqlstate", err: sqlStateError("23505"), failures: 1, wantAttempts: 1, wantErr: true},
+		{name: "plain error", err: errors.New("boom"), failures: 1, wantAttempts: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openItemsDB(t)
+			tm := database.NewTxManagerWithRetry(db, retry)
+
+			attempts := 0
+			err := tm.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+				attempts++
+				if err := insertItem(ctx, tx, "item"); err != nil {
+					return err
+				}
+				if attempts <= tt.failures {
+					return tt.err
+				}
+				return nil
+			})
+
+			assert.Equal(t, tt.wantAttempts, attempts)
+			if tt.wantErr {
+				require.ErrorIs(t, err, tt.err)
+				assert.Empty(t, itemNames(t, db))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{"item"}, itemNames(t, db), "only the successful attempt is committed")
+		})
+	}
+
+	t.Run("canceled context stops retries", func(t *testing.T) {
+		tm := database.NewTxManagerWithRetry(openItemsDB(t), database.RetryPolicy{MaxAttempts: 5, InitialInterval: time.Hour})
+		ctx, cancel := context.WithCancel(ctx)
+
+		attempts := 0
+		err := tm.WithTransaction(ctx, nil, func(context.Context, *sqlx.Tx) error {
+			attempts++
+			cancel()
+			return sqlStateError("40001")
+		})
+		require.ErrorIs(t, err, sqlStateError("40001"))
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+// txCounter считает итоги транзакций, о которых сообщает менеджер.
+type txCounter struct {
+	commits, rollbacks, retries int
+}
+
+func (c *txCounter) TransactionFinished(committed bool) {
+	if committed {
+		c.commits++
+	} else {
+		c.rollbacks++
+	}
+}
+
+func (c *txCounter) TransactionRetried() {
+	c.retries++
+}
+
+func TestTxManager_Observer(t *testing.T) {
+	ctx := context.Background()
+	retry := database.RetryPolicy{MaxAttempts: 3, InitialInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+
+	t.Run("retried attempts", func(t *testing.T) {
+		var counter txCounter
+		tm := database.NewTxManagerWithObserver(openItemsDB(t), retry, &counter)
+
+		attempts := 0
+		err := tm.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+			attempts++
+			if attempts < 3 {
+				return sqlStateError("40001")
+			}
+			return insertItem(ctx, tx, "item")
+		})
+		require.NoError(t, err)
+		assert.Equal(t, txCounter{commits: 1, rollbacks: 2, retries: 2}, counter)
+	})
+
+	t.Run("savepoints are not transactions", func(t *testing.T) {
+		var counter txCounter
+		tm := database.NewTxManagerWithObserver(openItemsDB(t), retry, &counter)
+
+		err := tm.WithTransaction(ctx, nil, func(ctx context.Context, _ *sqlx.Tx) error {
+			_ = tm.WithTransaction(ctx, nil, func(context.Context, *sqlx.Tx) error { return errors.New("nested") })
+			return tm.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+				return insertItem(ctx, tx, "item")
+			})
+		})
+		require.NoError(t, err)
+		assert.Equal(t, txCounter{commits: 1}, counter)
+	})
+
+	t.Run("panic rolls back", func(t *testing.T) {
+		var counter txCounter
+		tm := database.NewTxManagerWithObserver(openItemsDB(t), retry, &counter)
+
+		assert.Panics(t, func() {
+			_ = tm.WithTransaction(ctx, nil, func(context.Context, *sqlx.Tx) error { panic("boom") })
+		})
+		assert.Equal(t, txCounter{rollbacks: 1}, counter)
+	})
+}
@@ -12,4 +12,53 @@ type Event struct {
 	Description string        `db:"description" json:"description"`
 	UserID      string        `db:"user_id" json:"userId"`
 	OffsetTime  time.Duration `db:"offset_time" json:"offsetTime"`
+	// NotifiedAt - время отправки последнего уведомления.
+	NotifiedAt *time.Time `db:"notified_at" json:"-"`
+	// NextNotifyAt - момент уведомления о следующем вхождении, nil - уведомлять больше не о чем.
+	// При сохранении хранилище ставит NotifyAt первого вхождения, дальше его двигает планировщик.
+	NextNotifyAt *time.Time `db:"next_notify_at" json:"-"`
+	// RRule - правило повторения в формате RFC 5545, пусто для разового события.
+	RRule   string  `db:"rrule" json:"rrule,omitempty"`
+	ExDates ExDates `db:"exdates" json:"exdates,omitempty"`
+	// SeriesEnd - окончание последнего вхождения, nil - бесконечная серия.
+	// Вычисляется хранилищем при сохранении.
+	SeriesEnd *time.Time `db:"series_end" json:"-"`
+	// Version - номер версии события: задается хранилищем и растет при каждом изменении.
+	Version int64 `db:"version" json:"-"`
+	// DeletedAt - время переноса события в корзину, nil - событие не удалено.
+	DeletedAt *time.Time `db:"deleted_at" json:"-"`
+	// TimeZone - IANA-имя часового пояса, в котором разворачивается серия. Если не задано,
+	// хранилище берет его из StartDate (например, TZID при импорте iCalendar), так как даты
+	// из БД читаются в UTC.
+	TimeZone string `db:"time_zone" json:"timeZone,omitempty"`
+}
+
+// Location возвращает часовой пояс события по ZoneName. Фиксированные смещения не сохраняются,
+// поэтому такие серии, как и серии без часового пояса, разворачиваются в UTC во всех хранилищах.
+func (e Event) Location() *time.Location {
+	if loc, err := time.LoadLocation(e.ZoneName()); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// ZoneName возвращает значение TimeZone для сохранения: заданное TimeZone или имя часового
+// пояса StartDate. Для UTC, местного времени процесса и фиксированных смещений - пустая строка.
+func (e Event) ZoneName() string {
+	if e.TimeZone != "" {
+		return e.TimeZone
+	}
+	loc := e.StartDate.Location()
+	if loc == time.UTC || loc == time.Local {
+		return ""
+	}
+	if _, err := time.LoadLocation(loc.String()); err != nil {
+		return ""
+	}
+	return loc.String()
+}
+
+// NotifyAt возвращает момент, когда по событию нужно отправить уведомление.
+func (e Event) NotifyAt() time.Time {
+	return e.StartDate.Add(-e.OffsetTime)
 }
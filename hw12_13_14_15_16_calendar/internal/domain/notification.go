@@ -2,9 +2,21 @@ package domain
 
 import "time"
 
+// Notification - уведомление о событии, передается через очередь хранителю.
 type Notification struct {
-	ID     string
-	Title  string
-	Date   time.Time
-	UserID string
+	ID       string    `db:"event_id" json:"id"`
+	Title    string    `db:"title" json:"title"`
+	Date     time.Time `db:"event_date" json:"date"`
+	UserID   string    `db:"user_id" json:"userId"`
+	NotifyAt time.Time `db:"notify_at" json:"notifyAt"`
+}
+
+func NewNotification(event Event) Notification {
+	return Notification{
+		ID:       event.ID,
+		Title:    event.Title,
+		Date:     event.StartDate,
+		UserID:   event.UserID,
+		NotifyAt: event.NotifyAt(),
+	}
 }
@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageLimit = 100
+	MaxPageLimit     = 1000
+)
+
+var (
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidPageLimit = fmt.Errorf("page limit must be between 1 and %d", MaxPageLimit)
+)
+
+// Cursor - позиция в выборке, упорядоченной по (StartDate, ID). Клиенту отдается
+// в непрозрачном виде, см. Encode.
+type Cursor struct {
+	StartDate time.Time
+	ID        string
+}
+
+// Page - параметры постраничной выборки. Limit = 0 - без ограничения,
+// After - курсор последнего события предыдущей страницы.
+type Page struct {
+	Limit      int
+	After      *Cursor
+	Descending bool
+}
+
+// NewPage собирает параметры страницы из запроса: limit = 0 заменяется на DefaultPageLimit,
+// пустой cursor - первая страница.
+func NewPage(limit int, cursor string, descending bool) (Page, error) {
+	if limit == 0 {
+		limit = DefaultPageLimit
+	}
+	if limit < 0 || limit > MaxPageLimit {
+		return Page{}, ErrInvalidPageLimit
+	}
+
+	page := Page{Limit: limit, Descending: descending}
+	if cursor != "" {
+		after, err := DecodeCursor(cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		page.After = after
+	}
+	return page, nil
+}
+
+// Window - границы начала вхождений серий, которые могут попасть на страницу, nil - без границы.
+type Window struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// SeriesWindow возвращает окно, вне которого вхождения серий не попадут на страницу.
+// Курсор отсекает вхождения до него. singles - разовые события после курсора в порядке
+// страницы: если их больше Limit, вхождения дальше события с индексом Limit тоже не нужны.
+func (p Page) SeriesWindow(singles []Event) Window {
+	var window Window
+	if p.After != nil {
+		after := p.After.StartDate
+		if p.Descending {
+			window.To = &after
+		} else {
+			window.From = &after
+		}
+	}
+	if p.Limit > 0 && len(singles) > p.Limit {
+		edge := singles[p.Limit].StartDate
+		if p.Descending {
+			window.From = &edge
+		} else {
+			window.To = &edge
+		}
+	}
+	return window
+}
+
+// Head упорядочивает события и оставляет первые Limit+1 после курсора: лишнее событие
+// показывает, есть ли следующая страница. При Limit = 0 события только упорядочиваются.
+func (p Page) Head(list []Event) []Event {
+	limit := p.Limit
+	if limit > 0 {
+		limit++
+	}
+	head, _ := Paginate(list, Page{Limit: limit, After: p.After, Descending: p.Descending})
+	return head
+}
+
+// SeriesOrder - порядок выборки серий для страницы: вхождения серий, идущих после last,
+// начинаются не раньше last.StartDate (не позже last.SeriesEnd при Descending).
+func (p Page) SeriesOrder() string {
+	if p.Descending {
+		return "series_end DESC NULLS FIRST, id DESC"
+	}
+	return "start_date ASC, id ASC"
+}
+
+// Settled сообщает, что серии, идущие в порядке SeriesOrder после last, уже не изменят head,
+// полученный через Head: все их вхождения окажутся дальше последнего события head.
+func (p Page) Settled(head []Event, last Event) bool {
+	if p.Limit <= 0 || len(head) <= p.Limit {
+		return false
+	}
+	edge := head[p.Limit].StartDate
+	if p.Descending {
+		return last.SeriesEnd != nil && edge.After(*last.SeriesEnd)
+	}
+	return edge.Before(last.StartDate)
+}
+
+// Empty сообщает, что в окно не попадает ни одно вхождение.
+func (w Window) Empty() bool {
+	return w.From != nil && w.To != nil && w.To.Before(*w.From)
+}
+
+func CursorOf(e Event) Cursor {
+	return Cursor{StartDate: e.StartDate, ID: e.ID}
+}
+
+func (c Cursor) Encode() string {
+	raw := c.StartDate.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	start, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidCursor
+	}
+	startDate, err := time.Parse(time.RFC3339Nano, start)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{StartDate: startDate, ID: id}, nil
+}
+
+// Less сравнивает события в порядке (StartDate, ID).
+func (c Cursor) Less(other Cursor) bool {
+	if !c.StartDate.Equal(other.StartDate) {
+		return c.StartDate.Before(other.StartDate)
+	}
+	return c.ID < other.ID
+}
+
+// Paginate упорядочивает события, отбрасывает все до курсора включительно и обрезает
+// список до Limit. Второй результат - курсор следующей страницы, nil на последней.
+func Paginate(list []Event, page Page) ([]Event, *Cursor) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if page.Descending {
+			return CursorOf(list[j]).Less(CursorOf(list[i]))
+		}
+		return CursorOf(list[i]).Less(CursorOf(list[j]))
+	})
+
+	if page.After != nil {
+		after := *page.After
+		skip := sort.Search(len(list), func(i int) bool {
+			if page.Descending {
+				return CursorOf(list[i]).Less(after)
+			}
+			return after.Less(CursorOf(list[i]))
+		})
+		list = list[skip:]
+	}
+
+	if page.Limit <= 0 || len(list) <= page.Limit {
+		return list, nil
+	}
+	list = list[:page.Limit]
+	next := CursorOf(list[len(list)-1])
+	return list, &next
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPage(t *testing.T) {
+	page, err := NewPage(0, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, Page{Limit: DefaultPageLimit}, page)
+
+	cursor := Cursor{StartDate: time.Date(2024, 1, 1, 10, 0, 0, 500, time.UTC), ID: "event-1"}
+	page, err = NewPage(10, cursor.Encode(), true)
+	require.NoError(t, err)
+	assert.Equal(t, 10, page.Limit)
+	assert.True(t, page.Descending)
+	require.NotNil(t, page.After)
+	assert.Equal(t, cursor, *page.After)
+
+	for _, limit := range []int{-1, MaxPageLimit + 1} {
+		_, err = NewPage(limit, "", false)
+		require.ErrorIs(t, err, ErrInvalidPageLimit)
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, s := range []string{"***", "bm8tc2VwYXJhdG9y", "bm90LWEtZGF0ZXxpZA", "MjAyNC0wMS0wMVQxMDowMDowMFp8"} {
+		_, err := DecodeCursor(s)
+		require.ErrorIs(t, err, ErrInvalidCursor, s)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	day := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	list := func() []Event {
+		return []Event{
+			{ID: "c", StartDate: day.Add(24 * time.Hour)},
+			{ID: "b", StartDate: day},
+			{ID: "a", StartDate: day},
+			{ID: "d", StartDate: day.Add(48 * time.Hour)},
+		}
+	}
+	ids := func(events []Event) []string {
+		result := make([]string, 0, len(events))
+		for _, e := range events {
+			result = append(result, e.ID)
+		}
+		return result
+	}
+
+	t.Run("ascending pages", func(t *testing.T) {
+		page, next := Paginate(list(), Page{Limit: 3})
+		assert.Equal(t, []string{"a", "b", "c"}, ids(page))
+		require.NotNil(t, next)
+		assert.Equal(t, Cursor{StartDate: day.Add(24 * time.Hour), ID: "c"}, *next)
+
+		page, next = Paginate(list(), Page{Limit: 3, After: next})
+		assert.Equal(t, []string{"d"}, ids(page))
+		assert.Nil(t, next)
+	})
+
+	t.Run("descending pages", func(t *testing.T) {
+		page, next := Paginate(list(), Page{Limit: 2, Descending: true})
+		assert.Equal(t, []string{"d", "c"}, ids(page))
+		require.NotNil(t, next)
+
+		page, next = Paginate(list(), Page{Limit: 2, After: next, Descending: true})
+		assert.Equal(t, []string{"b", "a"}, ids(page))
+		assert.Nil(t, next)
+	})
+
+	t.Run("cursor between equal start dates", func(t *testing.T) {
+		page, next := Paginate(list(), Page{After: &Cursor{StartDate: day, ID: "a"}})
+		assert.Equal(t, []string{"b", "c", "d"}, ids(page))
+		assert.Nil(t, next)
+	})
+
+	t.Run("exact limit has no next page", func(t *testing.T) {
+		page, next := Paginate(list(), Page{Limit: 4})
+		assert.Len(t, page, 4)
+		assert.Nil(t, next)
+	})
+}
+
+func TestPage_SeriesWindow(t *testing.T) {
+	day := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	singles := []Event{
+		{ID: "a", StartDate: day},
+		{ID: "b", StartDate: day.AddDate(0, 0, 1)},
+		{ID: "c", StartDate: day.AddDate(0, 0, 2)},
+	}
+	after := &Cursor{StartDate: day.AddDate(0, 0, -1), ID: "z"}
+
+	t.Run("no bounds", func(t *testing.T) {
+		window := Page{Limit: 3}.SeriesWindow(singles)
+		assert.Nil(t, window.From)
+		assert.Nil(t, window.To)
+		assert.False(t, window.Empty())
+	})
+
+	t.Run("ascending", func(t *testing.T) {
+		window := Page{Limit: 2, After: after}.SeriesWindow(singles)
+		require.NotNil(t, window.From)
+		require.NotNil(t, window.To)
+		assert.True(t, after.StartDate.Equal(*window.From))
+		assert.True(t, singles[2].StartDate.Equal(*window.To))
+	})
+
+	t.Run("descending", func(t *testing.T) {
+		reversed := []Event{singles[2], singles[1], singles[0]}
+		window := Page{Limit: 1, After: after, Descending: true}.SeriesWindow(reversed)
+		require.NotNil(t, window.From)
+		require.NotNil(t, window.To)
+		assert.True(t, singles[1].StartDate.Equal(*window.From))
+		assert.True(t, after.StartDate.Equal(*window.To))
+		assert.True(t, window.Empty())
+	})
+}
+
+func TestPage_Settled(t *testing.T) {
+	day := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	head := []Event{
+		{ID: "a", StartDate: day},
+		{ID: "b", StartDate: day.AddDate(0, 0, 1)},
+		{ID: "c", StartDate: day.AddDate(0, 0, 2)},
+	}
+	seriesEnd := day.AddDate(0, 0, -1)
+
+	t.Run("page not full", func(t *testing.T) {
+		assert.False(t, Page{Limit: 3}.Settled(head, Event{StartDate: day.AddDate(1, 0, 0)}))
+		assert.False(t, Page{}.Settled(head, Event{StartDate: day.AddDate(1, 0, 0)}))
+	})
+
+	t.Run("ascending", func(t *testing.T) {
+		page := Page{Limit: 2}
+		assert.True(t, page.Settled(head, Event{StartDate: day.AddDate(0, 0, 3)}))
+		assert.False(t, page.Settled(head, Event{StartDate: day.AddDate(0, 0, 2)}))
+	})
+
+	t.Run("descending", func(t *testing.T) {
+		page := Page{Limit: 2, Descending: true}
+		reversed := []Event{head[2], head[1], head[0]}
+		assert.True(t, page.Settled(reversed, Event{SeriesEnd: &seriesEnd}))
+		assert.False(t, page.Settled(reversed, Event{SeriesEnd: &day}))
+		assert.False(t, page.Settled(reversed, Event{}))
+	})
+}
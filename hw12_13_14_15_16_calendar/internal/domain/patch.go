@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// EventPatch - частичное изменение события по правилам JSON Merge Patch (RFC 7396).
+// nil - поле не изменяется, указатель на нулевое значение - поле сбрасывается (null в патче).
+type EventPatch struct {
+	Title       *string
+	StartDate   *time.Time
+	EndDate     *time.Time
+	Description *string
+	UserID      *string
+	OffsetTime  *time.Duration
+	RRule       *string
+	ExDates     *ExDates
+	TimeZone    *string
+}
+
+// Apply возвращает событие с примененным патчем. Служебные поля события не изменяются.
+func (p EventPatch) Apply(event Event) Event {
+	apply(&event.Title, p.Title)
+	apply(&event.StartDate, p.StartDate)
+	apply(&event.EndDate, p.EndDate)
+	apply(&event.Description, p.Description)
+	apply(&event.UserID, p.UserID)
+	apply(&event.OffsetTime, p.OffsetTime)
+	apply(&event.RRule, p.RRule)
+	apply(&event.ExDates, p.ExDates)
+	apply(&event.TimeZone, p.TimeZone)
+	return event
+}
+
+func apply[T any](field *T, value *T) {
+	if value != nil {
+		*field = *value
+	}
+}
+
+// Reschedules сообщает, меняет ли изменение события from на to занятое им время: начало,
+// окончание, правило повторения, часовой пояс, исключения или владельца.
+func Reschedules(from, to Event) bool {
+	if !from.StartDate.Equal(to.StartDate) || !from.EndDate.Equal(to.EndDate) ||
+		from.RRule != to.RRule || from.UserID != to.UserID || from.ZoneName() != to.ZoneName() ||
+		len(from.ExDates) != len(to.ExDates) {
+		return true
+	}
+	for i := range from.ExDates {
+		if !from.ExDates[i].Equal(to.ExDates[i]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventPatch_Apply(t *testing.T) {
+	start := time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC)
+	event := Event{
+		ID:          "event-1",
+		Title:       "Meeting",
+		StartDate:   start,
+		EndDate:     start.Add(time.Hour),
+		Description: "Weekly sync",
+		UserID:      "user-1",
+		OffsetTime:  15 * time.Minute,
+		RRule:       "FREQ=WEEKLY",
+		ExDates:     ExDates{start.AddDate(0, 0, 7)},
+		Version:     3,
+	}
+
+	t.Run("empty patch keeps event", func(t *testing.T) {
+		assert.Equal(t, event, EventPatch{}.Apply(event))
+	})
+
+	t.Run("values replace fields", func(t *testing.T) {
+		title := "Renamed"
+		end := start.Add(2 * time.Hour)
+		patched := EventPatch{Title: &title, EndDate: &end}.Apply(event)
+
+		expected := event
+		expected.Title = title
+		expected.EndDate = end
+		assert.Equal(t, expected, patched)
+	})
+
+	t.Run("zero values reset fields", func(t *testing.T) {
+		var (
+			description string
+			offset      time.Duration
+			rrule       string
+			exDates     ExDates
+		)
+		patched := EventPatch{Description: &description, OffsetTime: &offset, RRule: &rrule, ExDates: &exDates}.Apply(event)
+
+		assert.Empty(t, patched.Description)
+		assert.Zero(t, patched.OffsetTime)
+		assert.Empty(t, patched.RRule)
+		assert.Empty(t, patched.ExDates)
+		assert.Equal(t, event.Title, patched.Title)
+		assert.Equal(t, event.Version, patched.Version)
+	})
+}
+
+func TestReschedules(t *testing.T) {
+	start := time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC)
+	event := Event{
+		Title:     "Meeting",
+		StartDate: start,
+		EndDate:   start.Add(time.Hour),
+		UserID:    "user-1",
+		ExDates:   ExDates{start.AddDate(0, 0, 7)},
+	}
+
+	tests := []struct {
+		name   string
+		change func(e *Event)
+		want   bool
+	}{
+		{name: "title", change: func(e *Event) { e.Title = "Renamed" }, want: false},
+		{name: "same start in other zone", change: func(e *Event) { e.StartDate = start.In(time.FixedZone("MSK", 3*3600)) }, want: false},
+		{name: "start", change: func(e *Event) { e.StartDate = start.Add(time.Minute) }, want: true},
+		{name: "end", change: func(e *Event) { e.EndDate = start.Add(2 * time.Hour) }, want: true},
+		{name: "rrule", change: func(e *Event) { e.RRule = "FREQ=DAILY" }, want: true},
+		{name: "exdates", change: func(e *Event) { e.ExDates = ExDates{start.AddDate(0, 0, 14)} }, want: true},
+		{name: "user", change: func(e *Event) { e.UserID = "user-2" }, want: true},
+		{name: "time zone", change: func(e *Event) { e.TimeZone = "Europe/Berlin" }, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := event
+			changed.ExDates = append(ExDates(nil), event.ExDates...)
+			tt.change(&changed)
+			assert.Equal(t, tt.want, Reschedules(event, changed))
+		})
+	}
+}
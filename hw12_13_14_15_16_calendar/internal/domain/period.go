@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidWeekday = errors.New("invalid weekday")
+
+// Period - полуинтервал [Start, End) в часовом поясе пользователя.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// DayPeriod возвращает сутки, содержащие date, в часовом поясе date.
+func DayPeriod(date time.Time) Period {
+	start := startOfDay(date)
+	return Period{Start: start, End: start.AddDate(0, 0, 1)}
+}
+
+// WeekPeriod возвращает неделю, содержащую date и начинающуюся с weekStart.
+func WeekPeriod(date time.Time, weekStart time.Weekday) Period {
+	shift := (int(date.Weekday()) - int(weekStart) + 7) % 7
+	start := startOfDay(date).AddDate(0, 0, -shift)
+	return Period{Start: start, End: start.AddDate(0, 0, 7)}
+}
+
+// MonthPeriod возвращает календарный месяц, содержащий date.
+func MonthPeriod(date time.Time) Period {
+	start := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	return Period{Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// ParseWeekday разбирает название дня недели на английском, например "monday".
+func ParseWeekday(s string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(s, d.String()) {
+			return d, nil
+		}
+	}
+	return 0, ErrInvalidWeekday
+}
+
+// Полночь считается через time.Date, а не Truncate, чтобы учитывать переходы на летнее время.
+func startOfDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+}
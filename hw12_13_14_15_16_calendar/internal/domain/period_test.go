@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriods(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	// Среда
+	date := time.Date(2026, 2, 11, 15, 30, 0, 0, moscow)
+
+	t.Run("day", func(t *testing.T) {
+		p := DayPeriod(date)
+		assert.Equal(t, time.Date(2026, 2, 11, 0, 0, 0, 0, moscow), p.Start)
+		assert.Equal(t, time.Date(2026, 2, 12, 0, 0, 0, 0, moscow), p.End)
+	})
+
+	t.Run("week starting on monday", func(t *testing.T) {
+		p := WeekPeriod(date, time.Monday)
+		assert.Equal(t, time.Date(2026, 2, 9, 0, 0, 0, 0, moscow), p.Start)
+		assert.Equal(t, time.Date(2026, 2, 16, 0, 0, 0, 0, moscow), p.End)
+	})
+
+	t.Run("week starting on sunday", func(t *testing.T) {
+		p := WeekPeriod(date, time.Sunday)
+		assert.Equal(t, time.Date(2026, 2, 8, 0, 0, 0, 0, moscow), p.Start)
+		assert.Equal(t, time.Date(2026, 2, 15, 0, 0, 0, 0, moscow), p.End)
+	})
+
+	t.Run("week start day belongs to its own week", func(t *testing.T) {
+		monday := time.Date(2026, 2, 9, 0, 0, 0, 0, moscow)
+		p := WeekPeriod(monday, time.Monday)
+		assert.Equal(t, monday, p.Start)
+	})
+
+	t.Run("month", func(t *testing.T) {
+		p := MonthPeriod(date)
+		assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, moscow), p.Start)
+		assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, moscow), p.End)
+	})
+
+	t.Run("day with daylight saving transition lasts 23 hours", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+
+		p := DayPeriod(time.Date(2026, 3, 29, 12, 0, 0, 0, berlin))
+		assert.Equal(t, 23*time.Hour, p.End.Sub(p.Start))
+	})
+}
+
+func TestParseWeekday(t *testing.T) {
+	d, err := ParseWeekday("Monday")
+	require.NoError(t, err)
+	assert.Equal(t, time.Monday, d)
+
+	d, err = ParseWeekday("sunday")
+	require.NoError(t, err)
+	assert.Equal(t, time.Sunday, d)
+
+	_, err = ParseWeekday("funday")
+	require.ErrorIs(t, err, ErrInvalidWeekday)
+}
@@ -0,0 +1,539 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Поддерживается подмножество RFC 5545: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY (без порядковых
+// номеров), COUNT, UNTIL и EXDATE. Правило разворачивается в часовом поясе события (Event.Location),
+// поэтому вхождения сохраняют местное время при переходе на летнее время; неделя начинается с понедельника.
+
+var (
+	ErrInvalidRRule       = errors.New("invalid recurrence rule")
+	ErrTooManyOccurrences = fmt.Errorf("more than %d occurrences in the requested range", maxOccurrences)
+)
+
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+)
+
+const (
+	// RecurrenceHorizon - насколько далеко разворачивается бесконечная серия,
+	// если верхняя граница выборки не задана.
+	RecurrenceHorizon = 2 * 365 * 24 * time.Hour
+
+	// maxNextOccurrenceSpan - самое широкое окно поиска следующего вхождения.
+	maxNextOccurrenceSpan = 100 * 365 * 24 * time.Hour
+
+	// maxOccurrences ограничивает число вхождений одной серии в запрошенном окне и COUNT.
+	maxOccurrences = 10000
+
+	icalDateTime = "20060102T150405Z"
+	icalDate     = "20060102"
+)
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+type RRule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []time.Weekday
+	Count    int
+	Until    *time.Time
+}
+
+// ParseRRule разбирает правило вида "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10",
+// префикс "RRULE:" допускается.
+func ParseRRule(s string) (*RRule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRRule)
+	}
+
+	rule := &RRule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRRule, part)
+		}
+
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.Freq, err = parseFrequency(value)
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(value)
+		case "COUNT":
+			rule.Count, err = parsePositive(value)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseICalTime(value)
+			rule.Until = &until
+		default:
+			err = fmt.Errorf("unsupported part %q", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRRule, err)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRRule)
+	}
+	if rule.Count > maxOccurrences {
+		return nil, fmt.Errorf("%w: COUNT must not exceed %d", ErrInvalidRRule, maxOccurrences)
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRRule)
+	}
+	if rule.Freq == FrequencyDaily && len(rule.ByDay) > 0 {
+		return nil, fmt.Errorf("%w: BYDAY is not supported with FREQ=DAILY", ErrInvalidRRule)
+	}
+	return rule, nil
+}
+
+func (r RRule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, d := range r.ByDay {
+			days = append(days, strings.ToUpper(d.String()[:2]))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(icalDateTime))
+	}
+	return strings.Join(parts, ";")
+}
+
+// ExDates - исключенные из серии даты начала вхождений. В БД хранятся
+// списком через запятую в формате значения EXDATE: 20260210T100000Z.
+type ExDates []time.Time
+
+func (d ExDates) Contains(t time.Time) bool {
+	for _, ex := range d {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d ExDates) Value() (driver.Value, error) {
+	values := make([]string, 0, len(d))
+	for _, t := range d {
+		values = append(values, t.UTC().Format(icalDateTime))
+	}
+	return strings.Join(values, ","), nil
+}
+
+func (d *ExDates) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("unsupported exdates type %T", src)
+	}
+
+	*d = nil
+	if s == "" {
+		return nil
+	}
+	for _, value := range strings.Split(s, ",") {
+		t, err := parseICalTime(value)
+		if err != nil {
+			return err
+		}
+		*d = append(*d, t)
+	}
+	return nil
+}
+
+func (e Event) IsRecurring() bool {
+	return e.RRule != ""
+}
+
+// Occurrences возвращает вхождения события, начинающиеся в [from, to]. Вхождение - копия
+// события с тем же ID и сдвинутыми датами. Для неповторяющегося события - само событие.
+// Если в окне больше maxOccurrences вхождений, возвращается ErrTooManyOccurrences.
+func (e Event) Occurrences(from, to time.Time) ([]Event, error) {
+	if !e.IsRecurring() {
+		if e.StartDate.Before(from) || e.StartDate.After(to) {
+			return nil, nil
+		}
+		return []Event{e}, nil
+	}
+
+	rule, err := ParseRRule(e.RRule)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := e.EndDate.Sub(e.StartDate)
+	var result []Event
+	err = rule.each(e.StartDate.In(e.Location()), from, to, func(start time.Time) {
+		if e.ExDates.Contains(start) {
+			return
+		}
+		occurrence := e
+		occurrence.StartDate = start.In(e.StartDate.Location())
+		occurrence.EndDate = occurrence.StartDate.Add(duration)
+		result = append(result, occurrence)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// NextOccurrence возвращает первое вхождение события, начинающееся не раньше from,
+// nil - если таких вхождений нет. Серия просматривается окнами от from, которые
+// расширяются, пока в них не найдется вхождение.
+func (e Event) NextOccurrence(from time.Time) (*Event, error) {
+	if !e.IsRecurring() {
+		if e.StartDate.Before(from) {
+			return nil, nil
+		}
+		return &e, nil
+	}
+
+	rule, err := ParseRRule(e.RRule)
+	if err != nil {
+		return nil, err
+	}
+	if from.Before(e.StartDate) {
+		from = e.StartDate
+	}
+
+	first := func(to time.Time) (*Event, error) {
+		occurrences, err := e.Occurrences(from, to)
+		if err != nil || len(occurrences) == 0 {
+			return nil, err
+		}
+		return &occurrences[0], nil
+	}
+
+	// Серия с COUNT не длиннее maxOccurrences и просматривается целиком
+	if rule.Count > 0 {
+		return first(time.Unix(1<<40, 0))
+	}
+	for span := 2 * rule.period(); ; span *= 2 {
+		to := from.Add(span)
+		next, err := first(to)
+		if err != nil || next != nil {
+			return next, err
+		}
+		if (rule.Until != nil && to.After(*rule.Until)) || span > maxNextOccurrenceSpan {
+			return nil, nil
+		}
+	}
+}
+
+// OccurrencesMatching разворачивает событие с теми же включающими фильтрами, что и FindEvent:
+// начало в [startFrom, startTo], окончание в [endFrom, endTo]. Без верхней границы
+// серия разворачивается на RecurrenceHorizon.
+func (e Event) OccurrencesMatching(startFrom, startTo, endFrom, endTo *time.Time) ([]Event, error) {
+	return e.OccurrencesWithin(startFrom, startTo, endFrom, endTo, Window{})
+}
+
+// OccurrencesWithin работает как OccurrencesMatching, но разворачивает серию только в окне
+// страницы (см. Page.SeriesWindow). Горизонт бесконечной серии отсчитывается от фильтров,
+// а не от окна, поэтому постраничный обход не продлевает его.
+func (e Event) OccurrencesWithin(startFrom, startTo, endFrom, endTo *time.Time, window Window) ([]Event, error) {
+	duration := e.EndDate.Sub(e.StartDate)
+
+	from := e.StartDate
+	if startFrom != nil && startFrom.After(from) {
+		from = *startFrom
+	}
+	if endFrom != nil && endFrom.Add(-duration).After(from) {
+		from = endFrom.Add(-duration)
+	}
+
+	to := from.Add(RecurrenceHorizon)
+	bounded := false
+	if startTo != nil {
+		to, bounded = *startTo, true
+	}
+	if endTo != nil && (!bounded || endTo.Add(-duration).Before(to)) {
+		to = endTo.Add(-duration)
+	}
+
+	if window.From != nil && window.From.After(from) {
+		from = *window.From
+	}
+	if window.To != nil && window.To.Before(to) {
+		to = *window.To
+	}
+	return e.Occurrences(from, to)
+}
+
+// LastOccurrenceEnd возвращает окончание последнего вхождения серии, nil - для бесконечной серии.
+func (e Event) LastOccurrenceEnd() (*time.Time, error) {
+	if !e.IsRecurring() {
+		end := e.EndDate
+		return &end, nil
+	}
+
+	rule, err := ParseRRule(e.RRule)
+	if err != nil {
+		return nil, err
+	}
+	if rule.Count == 0 && rule.Until == nil {
+		return nil, nil
+	}
+
+	occurrences, err := e.lastOccurrences(rule)
+	if err != nil {
+		return nil, err
+	}
+	if len(occurrences) == 0 {
+		// Все вхождения исключены, серия заканчивается вместе с первым
+		end := e.EndDate
+		return &end, nil
+	}
+	end := occurrences[len(occurrences)-1].EndDate
+	return &end, nil
+}
+
+// lastOccurrences возвращает вхождения конечной серии, последнее из которых - последнее в серии.
+// Серия с COUNT не длиннее maxOccurrences и перебирается целиком, серия с UNTIL - окнами
+// от UNTIL назад, которые расширяются, пока в них не найдется вхождение.
+func (e Event) lastOccurrences(rule *RRule) ([]Event, error) {
+	if rule.Until == nil {
+		return e.Occurrences(e.StartDate, time.Unix(1<<40, 0))
+	}
+
+	span := 2 * rule.period()
+	for {
+		from := rule.Until.Add(-span)
+		if span >= rule.Until.Sub(e.StartDate) {
+			from = e.StartDate
+		}
+		occurrences, err := e.Occurrences(from, *rule.Until)
+		if err != nil || len(occurrences) > 0 || !from.After(e.StartDate) {
+			return occurrences, err
+		}
+		span *= 2
+	}
+}
+
+// Overlaps сообщает, пересекаются ли интервалы двух событий.
+func (e Event) Overlaps(other Event) bool {
+	return e.StartDate.Before(other.EndDate) && other.StartDate.Before(e.EndDate)
+}
+
+// each перебирает по порядку начала вхождений в [from, to] с учетом COUNT и UNTIL. Даты
+// строятся в часовом поясе dtstart. Правило без COUNT перебирается сразу с периода, в котором
+// лежит from, поэтому ограничение maxOccurrences считается от начала окна, а не от dtstart.
+func (r RRule) each(dtstart, from, to time.Time, yield func(time.Time)) error {
+	loc := dtstart.Location()
+	at := func(day time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(),
+			dtstart.Hour(), dtstart.Minute(), dtstart.Second(), dtstart.Nanosecond(), loc)
+	}
+	ended := func(t time.Time) bool {
+		return t.After(to) || (r.Until != nil && t.After(*r.Until))
+	}
+
+	period := 0
+	if r.Count == 0 {
+		period = r.periodsBefore(dtstart, from)
+	}
+
+	generated, yielded := 0, 0
+	for ; ; period++ {
+		step := period * r.Interval
+		var (
+			periodStart time.Time
+			candidates  []time.Time
+		)
+
+		switch r.Freq {
+		case FrequencyDaily:
+			periodStart = at(dtstart.AddDate(0, 0, step))
+			candidates = []time.Time{periodStart}
+		case FrequencyWeekly:
+			monday := dtstart.AddDate(0, 0, -mondayOffset(dtstart.Weekday())+7*step)
+			periodStart = time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, loc)
+			for _, d := range r.weekdays(dtstart) {
+				candidates = append(candidates, at(monday.AddDate(0, 0, mondayOffset(d))))
+			}
+		case FrequencyMonthly:
+			periodStart = time.Date(dtstart.Year(), dtstart.Month()+time.Month(step), 1, 0, 0, 0, 0, loc)
+			if len(r.ByDay) == 0 {
+				// Несуществующие даты (31 февраля) пропускаются, как требует RFC 5545
+				day := periodStart.AddDate(0, 0, dtstart.Day()-1)
+				if day.Month() == periodStart.Month() {
+					candidates = []time.Time{at(day)}
+				}
+			} else {
+				for day := periodStart; day.Month() == periodStart.Month(); day = day.AddDate(0, 0, 1) {
+					if containsWeekday(r.ByDay, day.Weekday()) {
+						candidates = append(candidates, at(day))
+					}
+				}
+			}
+		}
+
+		// Период без подходящих дат (февраль для 31 числа) тоже двигает перебор вперед
+		if ended(periodStart) {
+			return nil
+		}
+		for _, start := range candidates {
+			if start.Before(dtstart) {
+				continue
+			}
+			if ended(start) || (r.Count > 0 && generated >= r.Count) {
+				return nil
+			}
+			generated++
+			if start.Before(from) {
+				continue
+			}
+			if yielded == maxOccurrences {
+				return ErrTooManyOccurrences
+			}
+			yielded++
+			yield(start)
+		}
+	}
+}
+
+// periodsBefore возвращает число периодов правила, которые целиком закончились до from,
+// с запасом в один период.
+func (r RRule) periodsBefore(dtstart, from time.Time) int {
+	if !from.After(dtstart) {
+		return 0
+	}
+	from = from.In(dtstart.Location())
+
+	var periods int
+	switch r.Freq {
+	case FrequencyDaily:
+		periods = daysBetween(dtstart, from) / r.Interval
+	case FrequencyWeekly:
+		monday := dtstart.AddDate(0, 0, -mondayOffset(dtstart.Weekday()))
+		periods = daysBetween(monday, from) / 7 / r.Interval
+	case FrequencyMonthly:
+		months := (from.Year()-dtstart.Year())*12 + int(from.Month()-dtstart.Month())
+		periods = months / r.Interval
+	}
+	return max(periods-1, 0)
+}
+
+// period возвращает наибольшую длину периода правила.
+func (r RRule) period() time.Duration {
+	day := 25 * time.Hour // с запасом на переход на летнее время
+	switch r.Freq {
+	case FrequencyWeekly:
+		return 7 * day * time.Duration(r.Interval)
+	case FrequencyMonthly:
+		return 31 * day * time.Duration(r.Interval)
+	default:
+		return day * time.Duration(r.Interval)
+	}
+}
+
+// daysBetween возвращает число календарных дней от a до b.
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return int(time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC).Sub(time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)) / (24 * time.Hour))
+}
+
+func (r RRule) weekdays(dtstart time.Time) []time.Weekday {
+	days := r.ByDay
+	if len(days) == 0 {
+		days = []time.Weekday{dtstart.Weekday()}
+	}
+	sorted := append([]time.Weekday(nil), days...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return mondayOffset(sorted[i]) < mondayOffset(sorted[j])
+	})
+	return sorted
+}
+
+func mondayOffset(d time.Weekday) int {
+	return (int(d) + 6) % 7
+}
+
+func containsWeekday(days []time.Weekday, d time.Weekday) bool {
+	for _, day := range days {
+		if day == d {
+			return true
+		}
+	}
+	return false
+}
+
+func parseFrequency(value string) (Frequency, error) {
+	switch freq := Frequency(strings.ToUpper(value)); freq {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+		return freq, nil
+	default:
+		return "", fmt.Errorf("unsupported FREQ %q", value)
+	}
+}
+
+func parsePositive(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("expected positive number, got %q", value)
+	}
+	return n, nil
+}
+
+func parseByDay(value string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, code := range strings.Split(value, ",") {
+		d, ok := weekdayCodes[strings.ToUpper(code)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported BYDAY value %q", code)
+		}
+		days = append(days, d)
+	}
+	return days, nil
+}
+
+func parseICalTime(value string) (time.Time, error) {
+	if t, err := time.Parse(icalDateTime, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(icalDate, value); err == nil {
+		// Дата без времени включает весь день
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date-time %q, expected %s", value, icalDateTime)
+}
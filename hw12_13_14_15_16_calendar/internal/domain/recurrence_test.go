@@ -0,0 +1,349 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func starts(t *testing.T, occurrences []Event) []time.Time {
+	t.Helper()
+	result := make([]time.Time, 0, len(occurrences))
+	for _, o := range occurrences {
+		assert.Equal(t, time.Hour, o.EndDate.Sub(o.StartDate))
+		result = append(result, o.StartDate.UTC())
+	}
+	return result
+}
+
+func utc(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestParseRRule(t *testing.T) {
+	t.Run("full rule", func(t *testing.T) {
+		rule, err := ParseRRule("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;UNTIL=20260301T000000Z")
+		require.NoError(t, err)
+		assert.Equal(t, FrequencyWeekly, rule.Freq)
+		assert.Equal(t, 2, rule.Interval)
+		assert.Equal(t, []time.Weekday{time.Monday, time.Wednesday}, rule.ByDay)
+		require.NotNil(t, rule.Until)
+		assert.Equal(t, utc(2026, 3, 1, 0), *rule.Until)
+		assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;UNTIL=20260301T000000Z", rule.String())
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		rule, err := ParseRRule("FREQ=DAILY")
+		require.NoError(t, err)
+		assert.Equal(t, 1, rule.Interval)
+		assert.Zero(t, rule.Count)
+		assert.Nil(t, rule.Until)
+	})
+
+	invalid := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=DAILY;COUNT=2;UNTIL=20260301T000000Z",
+		"FREQ=DAILY;COUNT=10001",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;BYMONTH=1",
+		"FREQ",
+	}
+	for _, s := range invalid {
+		t.Run("invalid "+s, func(t *testing.T) {
+			_, err := ParseRRule(s)
+			require.ErrorIs(t, err, ErrInvalidRRule)
+		})
+	}
+}
+
+func TestEvent_Occurrences(t *testing.T) {
+	// Вторник
+	dtstart := utc(2026, 2, 10, 10)
+	event := Event{ID: "series", StartDate: dtstart, EndDate: dtstart.Add(time.Hour)}
+	yearLater := dtstart.AddDate(1, 0, 0)
+
+	tests := []struct {
+		name    string
+		start   time.Time
+		rrule   string
+		exDates ExDates
+		from    time.Time
+		to      time.Time
+		want    []time.Time
+	}{
+		{
+			name:  "daily with count",
+			rrule: "FREQ=DAILY;COUNT=3",
+			from:  dtstart,
+			to:    yearLater,
+			want:  []time.Time{utc(2026, 2, 10, 10), utc(2026, 2, 11, 10), utc(2026, 2, 12, 10)},
+		},
+		{
+			name:  "daily with interval and inclusive until",
+			rrule: "FREQ=DAILY;INTERVAL=2;UNTIL=20260214T100000Z",
+			from:  dtstart,
+			to:    yearLater,
+			want:  []time.Time{utc(2026, 2, 10, 10), utc(2026, 2, 12, 10), utc(2026, 2, 14, 10)},
+		},
+		{
+			name:  "window in the middle of infinite series",
+			rrule: "FREQ=DAILY",
+			from:  utc(2026, 3, 1, 0),
+			to:    utc(2026, 3, 2, 23),
+			want:  []time.Time{utc(2026, 3, 1, 10), utc(2026, 3, 2, 10)},
+		},
+		{
+			name:  "weekly defaults to start weekday",
+			rrule: "FREQ=WEEKLY;COUNT=2",
+			from:  dtstart,
+			to:    yearLater,
+			want:  []time.Time{utc(2026, 2, 10, 10), utc(2026, 2, 17, 10)},
+		},
+		{
+			name:  "weekly by day skips days before start",
+			rrule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR,MO;COUNT=4",
+			from:  dtstart,
+			to:    yearLater,
+			want: []time.Time{
+				utc(2026, 2, 13, 10), utc(2026, 2, 23, 10), utc(2026, 2, 27, 10), utc(2026, 3, 9, 10),
+			},
+		},
+		{
+			name:  "monthly skips missing days",
+			start: utc(2026, 1, 31, 10),
+			rrule: "FREQ=MONTHLY;COUNT=3",
+			from:  utc(2026, 1, 31, 0),
+			to:    yearLater,
+			want:  []time.Time{utc(2026, 1, 31, 10), utc(2026, 3, 31, 10), utc(2026, 5, 31, 10)},
+		},
+		{
+			name:  "monthly by day",
+			rrule: "FREQ=MONTHLY;BYDAY=TU;UNTIL=20260310T235959Z",
+			from:  dtstart,
+			to:    yearLater,
+			want: []time.Time{
+				utc(2026, 2, 10, 10), utc(2026, 2, 17, 10), utc(2026, 2, 24, 10),
+				utc(2026, 3, 3, 10), utc(2026, 3, 10, 10),
+			},
+		},
+		{
+			name:    "exdate is skipped but counted",
+			rrule:   "FREQ=DAILY;COUNT=3",
+			exDates: ExDates{utc(2026, 2, 11, 10)},
+			from:    dtstart,
+			to:      yearLater,
+			want:    []time.Time{utc(2026, 2, 10, 10), utc(2026, 2, 12, 10)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := event
+			e.RRule = tt.rrule
+			e.ExDates = tt.exDates
+			if !tt.start.IsZero() {
+				e.StartDate = tt.start
+				e.EndDate = tt.start.Add(time.Hour)
+			}
+
+			occurrences, err := e.Occurrences(tt.from, tt.to)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, starts(t, occurrences))
+			for _, o := range occurrences {
+				assert.Equal(t, "series", o.ID)
+			}
+		})
+	}
+
+	t.Run("keeps start location", func(t *testing.T) {
+		moscow, err := time.LoadLocation("Europe/Moscow")
+		require.NoError(t, err)
+		e := event
+		e.StartDate = dtstart.In(moscow)
+		e.EndDate = e.StartDate.Add(time.Hour)
+		e.RRule = "FREQ=DAILY;COUNT=2"
+
+		occurrences, err := e.Occurrences(dtstart, yearLater)
+		require.NoError(t, err)
+		require.Len(t, occurrences, 2)
+		assert.Equal(t, moscow, occurrences[1].StartDate.Location())
+	})
+
+	t.Run("keeps wall clock across daylight saving time", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		// Понедельник и среда в 10:00 по Берлину, 29 марта 2026 - переход на летнее время
+		start := time.Date(2026, 3, 23, 10, 0, 0, 0, berlin)
+		zoned := Event{StartDate: start, EndDate: start.Add(time.Hour), RRule: "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4"}
+		// Так событие читается из БД: даты в UTC, часовой пояс - в TimeZone
+		stored := zoned
+		stored.StartDate, stored.EndDate = start.UTC(), start.Add(time.Hour).UTC()
+		stored.TimeZone = zoned.ZoneName()
+		assert.Equal(t, "Europe/Berlin", stored.TimeZone)
+
+		for _, e := range []Event{zoned, stored} {
+			occurrences, err := e.Occurrences(start, start.AddDate(0, 1, 0))
+			require.NoError(t, err)
+			assert.Equal(t, []time.Time{utc(2026, 3, 23, 9), utc(2026, 3, 25, 9), utc(2026, 3, 30, 8), utc(2026, 4, 1, 8)},
+				starts(t, occurrences))
+		}
+	})
+
+	t.Run("window far from start", func(t *testing.T) {
+		e := event
+		e.StartDate = utc(2000, 1, 1, 10)
+		e.EndDate = e.StartDate.Add(time.Hour)
+		e.RRule = "FREQ=WEEKLY;BYDAY=MO,TH"
+
+		// С начала серии прошло больше maxOccurrences вхождений
+		occurrences, err := e.Occurrences(utc(2200, 1, 1, 0), utc(2200, 1, 7, 23))
+		require.NoError(t, err)
+		assert.Equal(t, []time.Time{utc(2200, 1, 2, 10), utc(2200, 1, 6, 10)}, starts(t, occurrences))
+	})
+
+	t.Run("too many occurrences in window", func(t *testing.T) {
+		e := event
+		e.RRule = "FREQ=DAILY"
+		_, err := e.Occurrences(dtstart, dtstart.AddDate(30, 0, 0))
+		require.ErrorIs(t, err, ErrTooManyOccurrences)
+	})
+
+	t.Run("single event", func(t *testing.T) {
+		occurrences, err := event.Occurrences(dtstart, yearLater)
+		require.NoError(t, err)
+		assert.Equal(t, []Event{event}, occurrences)
+
+		occurrences, err = event.Occurrences(yearLater, yearLater)
+		require.NoError(t, err)
+		assert.Empty(t, occurrences)
+	})
+}
+
+func TestEvent_OccurrencesMatching(t *testing.T) {
+	dtstart := utc(2026, 2, 10, 10)
+	event := Event{StartDate: dtstart, EndDate: dtstart.Add(time.Hour), RRule: "FREQ=DAILY"}
+
+	t.Run("overlap window", func(t *testing.T) {
+		// Вхождения, пересекающиеся с 11 февраля 10:30 - 12 февраля 10:30
+		startTo := utc(2026, 2, 12, 10).Add(30 * time.Minute)
+		endFrom := utc(2026, 2, 11, 10).Add(30 * time.Minute)
+		occurrences, err := event.OccurrencesMatching(nil, &startTo, &endFrom, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []time.Time{utc(2026, 2, 11, 10), utc(2026, 2, 12, 10)}, starts(t, occurrences))
+	})
+
+	t.Run("end bound", func(t *testing.T) {
+		endTo := utc(2026, 2, 11, 11)
+		occurrences, err := event.OccurrencesMatching(nil, nil, nil, &endTo)
+		require.NoError(t, err)
+		assert.Equal(t, []time.Time{utc(2026, 2, 10, 10), utc(2026, 2, 11, 10)}, starts(t, occurrences))
+	})
+
+	t.Run("infinite series is expanded up to horizon", func(t *testing.T) {
+		occurrences, err := event.OccurrencesMatching(nil, nil, nil, nil)
+		require.NoError(t, err)
+		require.NotEmpty(t, occurrences)
+		last := occurrences[len(occurrences)-1].StartDate
+		assert.False(t, last.After(dtstart.Add(RecurrenceHorizon)))
+		assert.True(t, last.After(dtstart.Add(RecurrenceHorizon-48*time.Hour)))
+	})
+}
+
+func TestEvent_LastOccurrenceEnd(t *testing.T) {
+	dtstart := utc(2026, 2, 10, 10)
+	event := Event{StartDate: dtstart, EndDate: dtstart.Add(time.Hour)}
+
+	end, err := event.LastOccurrenceEnd()
+	require.NoError(t, err)
+	assert.Equal(t, dtstart.Add(time.Hour), *end)
+
+	event.RRule = "FREQ=WEEKLY;COUNT=3"
+	end, err = event.LastOccurrenceEnd()
+	require.NoError(t, err)
+	assert.Equal(t, utc(2026, 2, 24, 11), end.UTC())
+
+	event.RRule = "FREQ=DAILY;UNTIL=20260215"
+	end, err = event.LastOccurrenceEnd()
+	require.NoError(t, err)
+	assert.Equal(t, utc(2026, 2, 15, 11), end.UTC())
+
+	event.RRule = "FREQ=DAILY;UNTIL=21000101T000000Z"
+	end, err = event.LastOccurrenceEnd()
+	require.NoError(t, err)
+	assert.Equal(t, utc(2099, 12, 31, 11), end.UTC())
+
+	event.RRule = "FREQ=DAILY;UNTIL=20260220"
+	event.ExDates = ExDates{utc(2026, 2, 19, 10), utc(2026, 2, 20, 10)}
+	end, err = event.LastOccurrenceEnd()
+	require.NoError(t, err)
+	assert.Equal(t, utc(2026, 2, 18, 11), end.UTC())
+	event.ExDates = nil
+
+	event.RRule = "FREQ=DAILY"
+	end, err = event.LastOccurrenceEnd()
+	require.NoError(t, err)
+	assert.Nil(t, end)
+
+	event.RRule = "FREQ=HOURLY"
+	_, err = event.LastOccurrenceEnd()
+	require.ErrorIs(t, err, ErrInvalidRRule)
+}
+
+func TestEvent_NextOccurrence(t *testing.T) {
+	dtstart := utc(2026, 2, 10, 10)
+	event := Event{StartDate: dtstart, EndDate: dtstart.Add(time.Hour)}
+	next := func(from time.Time) *time.Time {
+		t.Helper()
+		occurrence, err := event.NextOccurrence(from)
+		require.NoError(t, err)
+		if occurrence == nil {
+			return nil
+		}
+		start := occurrence.StartDate.UTC()
+		return &start
+	}
+
+	assert.Equal(t, dtstart, *next(dtstart))
+	assert.Nil(t, next(dtstart.Add(time.Nanosecond)))
+
+	event.RRule = "FREQ=DAILY"
+	event.ExDates = ExDates{utc(2026, 2, 11, 10)}
+	assert.Equal(t, dtstart, *next(utc(2026, 1, 1, 0)))
+	assert.Equal(t, utc(2026, 2, 12, 10), *next(dtstart.Add(time.Nanosecond)), "exdate is skipped")
+	assert.Equal(t, utc(2036, 2, 11, 10), *next(utc(2036, 2, 10, 11)))
+	event.ExDates = nil
+
+	event.RRule = "FREQ=MONTHLY;INTERVAL=12;COUNT=2"
+	assert.Equal(t, utc(2027, 2, 10, 10), *next(utc(2026, 3, 1, 0)))
+	assert.Nil(t, next(utc(2027, 3, 1, 0)))
+
+	event.RRule = "FREQ=WEEKLY;UNTIL=20260301"
+	assert.Equal(t, utc(2026, 2, 24, 10), *next(utc(2026, 2, 18, 0)))
+	assert.Nil(t, next(utc(2026, 2, 25, 0)))
+}
+
+func TestExDates_ValueScan(t *testing.T) {
+	dates := ExDates{utc(2026, 2, 11, 10), utc(2026, 2, 12, 10)}
+
+	value, err := dates.Value()
+	require.NoError(t, err)
+	assert.Equal(t, driver.Value("20260211T100000Z,20260212T100000Z"), value)
+
+	var scanned ExDates
+	require.NoError(t, scanned.Scan([]byte(value.(string))))
+	assert.Equal(t, dates, scanned)
+
+	require.NoError(t, scanned.Scan(""))
+	assert.Nil(t, scanned)
+
+	require.Error(t, scanned.Scan("garbage"))
+}
@@ -0,0 +1,371 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+)
+
+var (
+	ErrInvalidCalendar = errors.New("invalid iCalendar data")
+	ErrInvalidEvent    = errors.New("invalid VEVENT")
+)
+
+// Item - результат разбора одного VEVENT. Ошибка в событии не прерывает разбор
+// остального файла и возвращается в Err.
+type Item struct {
+	UID   string
+	Event domain.Event
+	Err   error
+}
+
+// Decode разбирает iCalendar. Поддерживаются DTSTART/DTEND (UTC, TZID с именем IANA,
+// VALUE=DATE), DURATION, SUMMARY, DESCRIPTION, RRULE, EXDATE и TRIGGER первого VALARM.
+// Время без часового пояса считается UTC. UserID событий не заполняется.
+func Decode(r io.Reader) ([]Item, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("%w: BEGIN:VCALENDAR expected", ErrInvalidCalendar)
+	}
+
+	var (
+		items   []Item
+		current *eventBuilder
+		stack   []string
+	)
+	for n, raw := range lines {
+		prop, err := parseProperty(raw)
+		if err != nil {
+			if current != nil {
+				current.fail(err)
+				continue
+			}
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidCalendar, n+1, err)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			component := strings.ToUpper(prop.value)
+			stack = append(stack, component)
+			if component == "VEVENT" && len(stack) == 2 {
+				current = &eventBuilder{}
+			}
+			continue
+		case "END":
+			component := strings.ToUpper(prop.value)
+			if len(stack) == 0 || stack[len(stack)-1] != component {
+				return nil, fmt.Errorf("%w: line %d: unexpected END:%s", ErrInvalidCalendar, n+1, prop.value)
+			}
+			stack = stack[:len(stack)-1]
+			if component == "VEVENT" && current != nil && len(stack) == 1 {
+				items = append(items, current.build())
+				current = nil
+			}
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+		switch stack[len(stack)-1] {
+		case "VEVENT":
+			current.eventProperty(prop)
+		case "VALARM":
+			current.alarmProperty(prop)
+		}
+	}
+
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("%w: END:%s expected", ErrInvalidCalendar, stack[len(stack)-1])
+	}
+	return items, nil
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+type eventBuilder struct {
+	uid      string
+	event    domain.Event
+	start    *time.Time
+	end      *time.Time
+	allDay   bool
+	duration *time.Duration
+	// trigger - смещение первого VALARM относительно начала события
+	trigger    *time.Duration
+	triggerAt  *time.Time
+	alarmCount int
+	err        error
+}
+
+func (b *eventBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func (b *eventBuilder) eventProperty(prop property) {
+	var err error
+	switch prop.name {
+	case "UID":
+		b.uid = prop.value
+	case "SUMMARY":
+		b.event.Title = unescapeText(prop.value)
+	case "DESCRIPTION":
+		b.event.Description = unescapeText(prop.value)
+	case "DTSTART":
+		var t time.Time
+		t, b.allDay, err = parseTime(prop)
+		b.start = &t
+	case "DTEND":
+		var t time.Time
+		t, _, err = parseTime(prop)
+		b.end = &t
+	case "DURATION":
+		var d time.Duration
+		d, err = parseDuration(prop.value)
+		b.duration = &d
+	case "RRULE":
+		b.event.RRule = prop.value
+	case "EXDATE":
+		for _, value := range strings.Split(prop.value, ",") {
+			var t time.Time
+			t, _, err = parseTime(property{name: prop.name, params: prop.params, value: value})
+			if err != nil {
+				break
+			}
+			b.event.ExDates = append(b.event.ExDates, t)
+		}
+	}
+	if err != nil {
+		b.fail(fmt.Errorf("%s: %w", prop.name, err))
+	}
+}
+
+func (b *eventBuilder) alarmProperty(prop property) {
+	if prop.name != "TRIGGER" {
+		return
+	}
+	b.alarmCount++
+	if b.alarmCount > 1 || strings.EqualFold(prop.params["RELATED"], "END") {
+		return
+	}
+
+	if strings.EqualFold(prop.params["VALUE"], "DATE-TIME") {
+		t, _, err := parseTime(property{name: prop.name, value: prop.value})
+		if err != nil {
+			b.fail(fmt.Errorf("TRIGGER: %w", err))
+			return
+		}
+		b.triggerAt = &t
+		return
+	}
+
+	d, err := parseDuration(prop.value)
+	if err != nil {
+		b.fail(fmt.Errorf("TRIGGER: %w", err))
+		return
+	}
+	b.trigger = &d
+}
+
+func (b *eventBuilder) build() Item {
+	item := Item{UID: b.uid, Event: b.event}
+	if b.err != nil {
+		item.Err = fmt.Errorf("%w: %w", ErrInvalidEvent, b.err)
+		return item
+	}
+	if b.start == nil {
+		item.Err = fmt.Errorf("%w: DTSTART is required", ErrInvalidEvent)
+		return item
+	}
+
+	item.Event.StartDate = *b.start
+	switch {
+	case b.end != nil:
+		item.Event.EndDate = *b.end
+	case b.duration != nil:
+		item.Event.EndDate = b.start.Add(*b.duration)
+	case b.allDay:
+		item.Event.EndDate = b.start.AddDate(0, 0, 1)
+	default:
+		// Событие без длительности, его отклонит валидация сервиса
+		item.Event.EndDate = *b.start
+	}
+
+	// Уведомление после начала события не поддерживается
+	switch {
+	case b.trigger != nil && *b.trigger < 0:
+		item.Event.OffsetTime = -*b.trigger
+	case b.triggerAt != nil && b.triggerAt.Before(*b.start):
+		item.Event.OffsetTime = b.start.Sub(*b.triggerAt)
+	}
+	return item
+}
+
+// unfold читает строки и склеивает перенесенные (начинающиеся с пробела или табуляции).
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseProperty разбирает строку вида NAME;PARAM=VALUE;PARAM="QUOTED":VALUE.
+func parseProperty(line string) (property, error) {
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		}
+		if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return property{}, fmt.Errorf("malformed content line %q", line)
+	}
+
+	head := strings.Split(line[:colon], ";")
+	prop := property{
+		name:   strings.ToUpper(head[0]),
+		params: make(map[string]string, len(head)-1),
+		value:  line[colon+1:],
+	}
+	for _, param := range head[1:] {
+		name, value, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+// parseTime разбирает DATE-TIME или DATE, второй результат - признак даты без времени.
+func parseTime(prop property) (time.Time, bool, error) {
+	value := prop.value
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len(dateFormat) {
+		t, err := time.Parse(dateFormat, value)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(dateTimeFormat, value)
+		return t, false, err
+	}
+
+	loc := time.UTC
+	if tzid := prop.params["TZID"]; tzid != "" {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown TZID %q", tzid)
+		}
+	}
+	t, err := time.ParseInLocation(localFormat, value, loc)
+	return t, false, err
+}
+
+// parseDuration разбирает длительность RFC 5545: [+-]P[nW][nD][T[nH][nM][nS]].
+func parseDuration(value string) (time.Duration, error) {
+	s := value
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign = -1
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var total time.Duration
+	inTime := false
+	number := ""
+	for _, r := range s[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			number += string(r)
+			continue
+		case r == 'T' && number == "":
+			inTime = true
+			continue
+		}
+
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		number = ""
+
+		var unit time.Duration
+		switch {
+		case r == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case r == 'H' && inTime:
+			unit = time.Hour
+		case r == 'M' && inTime:
+			unit = time.Minute
+		case r == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		total += time.Duration(n) * unit
+	}
+	if number != "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return sign * total, nil
+}
+
+func unescapeText(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if !escaped {
+			if r == '\\' {
+				escaped = true
+			} else {
+				b.WriteRune(r)
+			}
+			continue
+		}
+		escaped = false
+		if r == 'n' || r == 'N' {
+			b.WriteByte('\n')
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,178 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+)
+
+const (
+	prodID         = "-//avmiki80//calendar//RU"
+	dateTimeFormat = "20060102T150405Z"
+	localFormat    = "20060102T150405"
+	dateFormat     = "20060102"
+
+	// maxLineLength - ограничение длины строки в октетах по RFC 5545, длинные строки переносятся.
+	maxLineLength = 75
+)
+
+// Encode записывает события в формате iCalendar (RFC 5545): VCALENDAR с VEVENT на каждое событие.
+// Серии выгружаются как есть, с RRULE и EXDATE. OffsetTime выгружается в VALARM.
+func Encode(w io.Writer, events []domain.Event) error {
+	bw := bufio.NewWriter(w)
+	enc := &encoder{w: bw}
+
+	enc.line("BEGIN", "VCALENDAR")
+	enc.line("VERSION", "2.0")
+	enc.line("PRODID", prodID)
+	enc.line("CALSCALE", "GREGORIAN")
+	for _, event := range events {
+		enc.event(event)
+	}
+	enc.line("END", "VCALENDAR")
+
+	if enc.err != nil {
+		return enc.err
+	}
+	return bw.Flush()
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) event(event domain.Event) {
+	stamp := event.UpdatedAt
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	e.line("BEGIN", "VEVENT")
+	e.line("UID", event.ID)
+	e.line("DTSTAMP", formatTime(stamp))
+	e.dateTime("DTSTART", event.StartDate, event)
+	e.dateTime("DTEND", event.EndDate, event)
+	e.line("SUMMARY", escapeText(event.Title))
+	if event.Description != "" {
+		e.line("DESCRIPTION", escapeText(event.Description))
+	}
+	if event.IsRecurring() {
+		e.line("RRULE", event.RRule)
+		if len(event.ExDates) > 0 {
+			dates := make([]string, 0, len(event.ExDates))
+			for _, t := range event.ExDates {
+				dates = append(dates, formatTime(t))
+			}
+			e.line("EXDATE", strings.Join(dates, ","))
+		}
+	}
+
+	// Уведомление отправляется по каждому событию, поэтому VALARM есть всегда
+	e.line("BEGIN", "VALARM")
+	e.line("ACTION", "DISPLAY")
+	e.line("DESCRIPTION", escapeText(event.Title))
+	e.line("TRIGGER", formatDuration(-event.OffsetTime))
+	e.line("END", "VALARM")
+
+	e.line("END", "VEVENT")
+}
+
+// dateTime пишет DTSTART/DTEND. Серии с часовым поясом выгружаются с TZID в местном времени,
+// чтобы при импорте повторения сохраняли время на часах при переходе на летнее время.
+func (e *encoder) dateTime(name string, t time.Time, event domain.Event) {
+	if event.IsRecurring() && event.TimeZone != "" {
+		if loc, err := time.LoadLocation(event.TimeZone); err == nil {
+			e.line(name+";TZID="+event.TimeZone, t.In(loc).Format(localFormat))
+			return
+		}
+	}
+	e.line(name, formatTime(t))
+}
+
+// line пишет строку содержимого, перенося ее по maxLineLength октетов без разрыва символов UTF-8.
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+
+	s := name + ":" + value
+	limit := maxLineLength
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		e.write(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// Пробел в начале строки продолжения тоже входит в лимит
+		limit = maxLineLength - 1
+	}
+	e.write(s + "\r\n")
+}
+
+func (e *encoder) write(s string) {
+	if e.err == nil {
+		_, e.err = e.w.WriteString(s)
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// formatDuration форматирует длительность по RFC 5545, например -PT15M или P1DT2H.
+func formatDuration(d time.Duration) string {
+	var b strings.Builder
+	if d < 0 {
+		b.WriteByte('-')
+		d = -d
+	}
+	b.WriteByte('P')
+
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	if days > 0 {
+		b.WriteString(strconv.FormatInt(int64(days), 10) + "D")
+	}
+
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+
+	if hours == 0 && minutes == 0 && seconds == 0 {
+		if days == 0 {
+			b.WriteString("T0S")
+		}
+		return b.String()
+	}
+
+	b.WriteByte('T')
+	if hours > 0 {
+		b.WriteString(strconv.FormatInt(int64(hours), 10) + "H")
+	}
+	if minutes > 0 {
+		b.WriteString(strconv.FormatInt(int64(minutes), 10) + "M")
+	}
+	if seconds > 0 {
+		b.WriteString(strconv.FormatInt(int64(seconds), 10) + "S")
+	}
+	return b.String()
+}
+
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
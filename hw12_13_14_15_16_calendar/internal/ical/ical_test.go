@@ -0,0 +1,224 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	start := time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC)
+	events := []domain.Event{
+		{
+			ID:          "123e4567-e89b-12d3-a456-426614174000",
+			UpdatedAt:   start.Add(-time.Hour),
+			Title:       "Team Meeting; weekly, sync",
+			StartDate:   start,
+			EndDate:     start.Add(time.Hour),
+			Description: "Line 1\nLine 2",
+			OffsetTime:  15 * time.Minute,
+			RRule:       "FREQ=WEEKLY;COUNT=10",
+			ExDates:     domain.ExDates{start.AddDate(0, 0, 7)},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, events))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	for _, line := range []string{
+		"UID:123e4567-e89b-12d3-a456-426614174000",
+		"DTSTAMP:20260210T090000Z",
+		"DTSTART:20260210T100000Z",
+		"DTEND:20260210T110000Z",
+		`SUMMARY:Team Meeting\; weekly\, sync`,
+		`DESCRIPTION:Line 1\nLine 2`,
+		"RRULE:FREQ=WEEKLY;COUNT=10",
+		"EXDATE:20260217T100000Z",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT15M",
+	} {
+		assert.Contains(t, out, line+"\r\n")
+	}
+}
+
+func TestEncode_FoldsLongLines(t *testing.T) {
+	event := domain.Event{
+		Title:     strings.Repeat("Очень длинное название ", 10),
+		StartDate: time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 2, 10, 11, 0, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, []domain.Event{event}))
+
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineLength)
+	}
+
+	items, err := Decode(&buf)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, event.Title, items[0].Event.Title)
+}
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	start := time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC)
+	event := domain.Event{
+		ID:          "event-1",
+		Title:       "Review, part 1",
+		StartDate:   start,
+		EndDate:     start.Add(90 * time.Minute),
+		Description: `C:\path; a\b`,
+		OffsetTime:  time.Hour + 30*time.Minute,
+		RRule:       "FREQ=DAILY;INTERVAL=2",
+		ExDates:     domain.ExDates{start.AddDate(0, 0, 2), start.AddDate(0, 0, 4)},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, []domain.Event{event}))
+
+	items, err := Decode(&buf)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.NoError(t, items[0].Err)
+
+	got := items[0].Event
+	assert.Equal(t, "event-1", items[0].UID)
+	assert.Equal(t, event.Title, got.Title)
+	assert.Equal(t, event.Description, got.Description)
+	assert.True(t, event.StartDate.Equal(got.StartDate))
+	assert.True(t, event.EndDate.Equal(got.EndDate))
+	assert.Equal(t, event.OffsetTime, got.OffsetTime)
+	assert.Equal(t, event.RRule, got.RRule)
+	assert.Equal(t, event.ExDates, got.ExDates)
+}
+
+func TestEncodeDecode_TimeZone(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	start := time.Date(2026, 3, 23, 10, 0, 0, 0, loc)
+	event := domain.Event{
+		ID:        "event-1",
+		Title:     "Standup",
+		StartDate: start,
+		EndDate:   start.Add(15 * time.Minute),
+		RRule:     "FREQ=WEEKLY;BYDAY=MO,WE",
+		TimeZone:  "Europe/Berlin",
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, []domain.Event{event}))
+	assert.Contains(t, buf.String(), "DTSTART;TZID=Europe/Berlin:20260323T100000\r\n")
+
+	items, err := Decode(&buf)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.NoError(t, items[0].Err)
+
+	got := items[0].Event
+	assert.True(t, event.StartDate.Equal(got.StartDate))
+	assert.Equal(t, "Europe/Berlin", got.ZoneName())
+}
+
+func TestDecode(t *testing.T) {
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Other//Tool//EN",
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Moscow",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:zoned",
+		"DTSTART;TZID=Europe/Moscow:20260210T100000",
+		"DURATION:PT45M",
+		"SUMMARY:Zoned",
+		"BEGIN:VALARM",
+		"TRIGGER;VALUE=DATE-TIME:20260210T065000Z",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:all-day",
+		"DTSTART;VALUE=DATE:20260211",
+		"SUMMARY:Holiday",
+		"DESCRIPTION:Long",
+		" er text",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:broken",
+		"SUMMARY:No start",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:bad-zone",
+		"DTSTART;TZID=Mars/Olympus:20260210T100000",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	items, err := Decode(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, items, 4)
+
+	zoned := items[0]
+	require.NoError(t, zoned.Err)
+	assert.Equal(t, time.Date(2026, 2, 10, 7, 0, 0, 0, time.UTC), zoned.Event.StartDate.UTC())
+	assert.Equal(t, 45*time.Minute, zoned.Event.EndDate.Sub(zoned.Event.StartDate))
+	assert.Equal(t, 10*time.Minute, zoned.Event.OffsetTime)
+
+	allDay := items[1]
+	require.NoError(t, allDay.Err)
+	assert.Equal(t, "Longer text", allDay.Event.Description)
+	assert.Equal(t, time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC), allDay.Event.StartDate)
+	assert.Equal(t, time.Date(2026, 2, 12, 0, 0, 0, 0, time.UTC), allDay.Event.EndDate)
+	assert.Zero(t, allDay.Event.OffsetTime)
+
+	assert.Equal(t, "broken", items[2].UID)
+	require.ErrorIs(t, items[2].Err, ErrInvalidEvent)
+	require.ErrorIs(t, items[3].Err, ErrInvalidEvent)
+}
+
+func TestDecode_InvalidCalendar(t *testing.T) {
+	for name, data := range map[string]string{
+		"empty":          "",
+		"not calendar":   "hello",
+		"unclosed":       "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n",
+		"mismatched end": "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Decode(strings.NewReader(data))
+			require.ErrorIs(t, err, ErrInvalidCalendar)
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"PT0S":       0,
+		"-PT15M":     -15 * time.Minute,
+		"P1DT2H":     26 * time.Hour,
+		"-P1DT1M30S": -(24*time.Hour + time.Minute + 30*time.Second),
+	}
+	for s, want := range tests {
+		got, err := parseDuration(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, got, s)
+		assert.Equal(t, s, formatDuration(want))
+	}
+
+	week, err := parseDuration("P1W")
+	require.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, week)
+
+	for _, s := range []string{"", "P", "PT", "15M", "PT1D", "P1H", "PT1"} {
+		_, err := parseDuration(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -0,0 +1,56 @@
+package logger
+
+import "context"
+
+const (
+	// RequestIDKey - поле лога с идентификатором запроса.
+	RequestIDKey = "request_id"
+	// ComponentKey - поле лога с именем компонента, который пишет запись.
+	ComponentKey = "component"
+)
+
+type (
+	requestIDContextKey struct{}
+	loggerContextKey    struct{}
+)
+
+// WithRequestID сохраняет в ctx идентификатор запроса для сквозной корреляции логов.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestID возвращает идентификатор запроса из ctx или пустую строку.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// WithContext добавляет к l идентификатор запроса из ctx, если он есть.
+func WithContext(ctx context.Context, l Logger) Logger {
+	if requestID := RequestID(ctx); requestID != "" {
+		return l.With(RequestIDKey, requestID)
+	}
+	return l
+}
+
+// NewContext сохраняет в ctx логгер запроса. Его используют слои, у которых нет
+// собственного логгера (сервисы, репозитории).
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, l)
+}
+
+// FromContext возвращает логгер запроса из ctx, а если его нет - логгер, который ничего не пишет.
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerContextKey{}).(Logger); ok {
+		return l
+	}
+	return nop{}
+}
+
+type nop struct{}
+
+func (nop) Debug(string, ...any) {}
+func (nop) Info(string, ...any)  {}
+func (nop) Warn(string, ...any)  {}
+func (nop) Error(string, ...any) {}
+func (n nop) With(...any) Logger { return n }
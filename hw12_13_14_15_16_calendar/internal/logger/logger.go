@@ -1,17 +1,31 @@
 package logger
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"log"
+	"log/slog"
+	"strconv"
 	"strings"
+	"sync/atomic"
 )
 
-// Logger интерфейс для логирования в приложении.
+// Logger интерфейс для логирования в приложении. fields - пары ключ/значение,
+// которые выводятся вместе с сообщением: Info("event created", "event_id", id).
 type Logger interface {
-	Debug(msg string)
-	Info(msg string)
-	Warn(msg string)
-	Error(msg string)
+	Debug(msg string, fields ...any)
+	Info(msg string, fields ...any)
+	Warn(msg string, fields ...any)
+	Error(msg string, fields ...any)
+	// With возвращает дочерний логгер, добавляющий fields к каждой записи.
+	With(fields ...any) Logger
+}
+
+// LevelSetter реализуют логгеры, уровень которых можно менять во время работы.
+// Уровень общий для логгера и всех его потомков, созданных через With.
+type LevelSetter interface {
+	SetLevel(level string)
 }
 
 type LogLevel int
@@ -24,15 +38,44 @@ const (
 	Prefix = "calendar: "
 )
 
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+
+	// badKey - ключ для значения без пары, как в log/slog.
+	badKey = "!BADKEY"
+	// callDepth - глубина стека до вызывающего кода: Output <- write <- Info <- caller.
+	callDepth = 3
+)
+
+var levelNames = map[LogLevel]string{
+	LevelDebug: "DEBUG",
+	LevelInfo:  "INFO",
+	LevelWarn:  "WARN",
+	LevelError: "ERROR",
+}
+
 type logger struct {
-	level  LogLevel
+	level  *atomic.Int64
 	logger *log.Logger
+	fields []any
 }
 
+// New создает логгер в текстовом формате.
 func New(level string, writer io.Writer) Logger {
+	return NewWithFormat(level, FormatText, writer)
+}
+
+// NewWithFormat создает логгер в формате text или json, неизвестный формат заменяется на text.
+func NewWithFormat(level, format string, writer io.Writer) Logger {
 	logLevel := parseLevel(level)
+	if strings.EqualFold(format, FormatJSON) {
+		return newJSONLogger(logLevel, writer)
+	}
+	current := new(atomic.Int64)
+	current.Store(int64(logLevel))
 	return &logger{
-		level:  logLevel,
+		level:  current,
 		logger: log.New(writer, Prefix, log.Ldate|log.Ltime|log.Lshortfile),
 	}
 }
@@ -52,26 +95,111 @@ func parseLevel(level string) LogLevel {
 	}
 }
 
-func (l *logger) Debug(msg string) {
-	if l.level <= LevelDebug {
-		l.logger.Printf("[DEBUG] %s", msg)
+// SetLevel меняет уровень логирования, неизвестный уровень заменяется на INFO.
+func (l *logger) SetLevel(level string) {
+	l.level.Store(int64(parseLevel(level)))
+}
+
+func (l *logger) Debug(msg string, fields ...any) {
+	l.write(LevelDebug, msg, fields)
+}
+
+func (l *logger) Info(msg string, fields ...any) {
+	l.write(LevelInfo, msg, fields)
+}
+
+func (l *logger) Warn(msg string, fields ...any) {
+	l.write(LevelWarn, msg, fields)
+}
+
+func (l *logger) Error(msg string, fields ...any) {
+	l.write(LevelError, msg, fields)
+}
+
+func (l *logger) With(fields ...any) Logger {
+	return &logger{
+		level:  l.level,
+		logger: l.logger,
+		fields: append(l.fields[:len(l.fields):len(l.fields)], fields...),
+	}
+}
+
+func (l *logger) write(level LogLevel, msg string, fields []any) {
+	if LogLevel(l.level.Load()) > level {
+		return
 	}
+
+	var line strings.Builder
+	line.WriteString("[" + levelNames[level] + "] " + msg)
+	writeFields(&line, l.fields)
+	writeFields(&line, fields)
+	_ = l.logger.Output(callDepth, line.String())
 }
 
-func (l *logger) Info(msg string) {
-	if l.level <= LevelInfo {
-		l.logger.Printf("[INFO] %s", msg)
+// writeFields дописывает пары в виде " key=value", значения с пробелами и кавычками экранируются.
+func writeFields(line *strings.Builder, fields []any) {
+	for i := 0; i < len(fields); i += 2 {
+		key, value := fieldPair(fields, i)
+		text := fmt.Sprint(value)
+		if err, ok := value.(error); ok {
+			text = err.Error()
+		}
+		if text == "" || strings.ContainsAny(text, " \t\n\"=") {
+			text = strconv.Quote(text)
+		}
+		line.WriteString(" " + key + "=" + text)
 	}
 }
 
-func (l *logger) Warn(msg string) {
-	if l.level <= LevelWarn {
-		l.logger.Printf("[WARN] %s", msg)
+func fieldPair(fields []any, i int) (string, any) {
+	key, ok := fields[i].(string)
+	if !ok || i+1 >= len(fields) {
+		return badKey, fields[i]
 	}
+	return key, fields[i+1]
 }
 
-func (l *logger) Error(msg string) {
-	if l.level <= LevelError {
-		l.logger.Printf("[ERROR] %s", msg)
+var slogLevels = map[LogLevel]slog.Level{
+	LevelDebug: slog.LevelDebug,
+	LevelInfo:  slog.LevelInfo,
+	LevelWarn:  slog.LevelWarn,
+	LevelError: slog.LevelError,
+}
+
+type jsonLogger struct {
+	level  *slog.LevelVar
+	logger *slog.Logger
+}
+
+func newJSONLogger(level LogLevel, writer io.Writer) Logger {
+	levelVar := new(slog.LevelVar)
+	levelVar.Set(slogLevels[level])
+	return &jsonLogger{
+		level:  levelVar,
+		logger: slog.New(slog.NewJSONHandler(writer, &slog.HandlerOptions{Level: levelVar})),
 	}
 }
+
+func (l *jsonLogger) SetLevel(level string) {
+	l.level.Set(slogLevels[parseLevel(level)])
+}
+
+func (l *jsonLogger) Debug(msg string, fields ...any) {
+	l.logger.Log(context.Background(), slog.LevelDebug, msg, fields...)
+}
+
+func (l *jsonLogger) Info(msg string, fields ...any) {
+	l.logger.Log(context.Background(), slog.LevelInfo, msg, fields...)
+}
+
+func (l *jsonLogger) Warn(msg string, fields ...any) {
+	l.logger.Log(context.Background(), slog.LevelWarn, msg, fields...)
+}
+
+func (l *jsonLogger) Error(msg string, fields ...any) {
+	l.logger.Log(context.Background(), slog.LevelError, msg, fields...)
+}
+
+func (l *jsonLogger) With(fields ...any) Logger {
+	return &jsonLogger{level: l.level, logger: l.logger.With(fields...)}
+}
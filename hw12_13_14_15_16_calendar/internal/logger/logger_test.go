@@ -2,6 +2,9 @@ package logger
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 
@@ -155,3 +158,104 @@ func TestLogger_MessageFormatting(t *testing.T) {
 		require.Contains(t, output, longMsg)
 	})
 }
+
+func TestLogger_Fields(t *testing.T) {
+	t.Run("text format", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := New("INFO", &buf).With("request_id", "req-1")
+
+		logger.Info("event created", "event_id", 42, "title", "Team meeting", "error", errors.New("boom"), "orphan")
+
+		output := buf.String()
+		require.Contains(t, output, `[INFO] event created request_id=req-1 event_id=42 title="Team meeting" error=boom !BADKEY=orphan`)
+		// В строке указан вызывающий файл, а не logger.go
+		require.Contains(t, output, "logger_test.go:")
+	})
+
+	t.Run("json format", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewWithFormat("DEBUG", "json", &buf).With("request_id", "req-1")
+
+		logger.Debug("event created", "event_id", "id-1", "error", errors.New("boom"))
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		require.Equal(t, "DEBUG", record["level"])
+		require.Equal(t, "event created", record["msg"])
+		require.Equal(t, "req-1", record["request_id"])
+		require.Equal(t, "id-1", record["event_id"])
+		require.Equal(t, "boom", record["error"])
+		require.Contains(t, record, "time")
+	})
+
+	t.Run("json format respects level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewWithFormat("WARN", "JSON", &buf)
+
+		logger.Info("info msg")
+		require.Empty(t, buf.String())
+
+		logger.Error("error msg")
+		require.Contains(t, buf.String(), `"level":"ERROR"`)
+	})
+
+	t.Run("child logger does not change parent", func(t *testing.T) {
+		var buf bytes.Buffer
+		parent := New("INFO", &buf).With("a", 1)
+		_ = parent.With("b", 2)
+		child := parent.With("c", 3)
+
+		parent.Info("parent")
+		child.Info("child")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		require.True(t, strings.HasSuffix(lines[0], "[INFO] parent a=1"))
+		require.True(t, strings.HasSuffix(lines[1], "[INFO] child a=1 c=3"))
+	})
+}
+
+func TestContext(t *testing.T) {
+	var buf bytes.Buffer
+	base := New("INFO", &buf)
+	ctx := context.Background()
+
+	require.Empty(t, RequestID(ctx))
+	FromContext(ctx).Info("dropped")
+	WithContext(ctx, base).Info("without request")
+
+	ctx = WithRequestID(ctx, "req-7")
+	ctx = NewContext(ctx, base.With(RequestIDKey, RequestID(ctx)))
+	require.Equal(t, "req-7", RequestID(ctx))
+
+	FromContext(ctx).Info("from context")
+	WithContext(ctx, base).Info("with context")
+
+	output := buf.String()
+	require.NotContains(t, output, "dropped")
+	require.Contains(t, output, "[INFO] without request\n")
+	require.Contains(t, output, "[INFO] from context request_id=req-7")
+	require.Contains(t, output, "[INFO] with context request_id=req-7")
+}
+
+func TestLogger_SetLevel(t *testing.T) {
+	for _, format := range []string{FormatText, FormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			parent := NewWithFormat("INFO", format, &buf)
+			child := parent.With("a", 1)
+
+			child.Debug("hidden")
+			setter, ok := parent.(LevelSetter)
+			require.True(t, ok)
+			setter.SetLevel("DEBUG")
+			child.Debug("visible")
+			setter.SetLevel("ERROR")
+			parent.Warn("hidden warn")
+
+			output := buf.String()
+			require.NotContains(t, output, "hidden")
+			require.Contains(t, output, "visible")
+		})
+	}
+}
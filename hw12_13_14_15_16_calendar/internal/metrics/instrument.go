@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/services"
+)
+
+type eventService struct {
+	services.EventService
+	metrics *Metrics
+}
+
+// InstrumentEventService считает созданные события и отказы из-за занятого времени.
+func InstrumentEventService(service services.EventService, m *Metrics) services.EventService {
+	return &eventService{EventService: service, metrics: m}
+}
+
+func (s *eventService) CreateEvent(ctx context.Context, event domain.Event) (*domain.Event, error) {
+	created, err := s.EventService.CreateEvent(ctx, event)
+	s.observe(err)
+	if err == nil {
+		s.metrics.EventCreated()
+	}
+	return created, err
+}
+
+func (s *eventService) UpdateEvent(ctx context.Context, id string, event domain.Event) (*domain.Event, error) {
+	updated, err := s.EventService.UpdateEvent(ctx, id, event)
+	s.observe(err)
+	return updated, err
+}
+
+func (s *eventService) PatchEvent(ctx context.Context, id string, patch domain.EventPatch, version int64) (*domain.Event, error) {
+	patched, err := s.EventService.PatchEvent(ctx, id, patch, version)
+	s.observe(err)
+	return patched, err
+}
+
+func (s *eventService) RestoreEvent(ctx context.Context, id string) (*domain.Event, error) {
+	restored, err := s.EventService.RestoreEvent(ctx, id)
+	s.observe(err)
+	return restored, err
+}
+
+func (s *eventService) observe(err error) {
+	if errors.Is(err, services.ErrDateBusy) {
+		s.metrics.EventConflict()
+	}
+}
+
+type producer struct {
+	queue.Producer
+	metrics *Metrics
+}
+
+// InstrumentProducer считает успешно опубликованные уведомления.
+func InstrumentProducer(p queue.Producer, m *Metrics) queue.Producer {
+	return &producer{Producer: p, metrics: m}
+}
+
+func (p *producer) Publish(ctx context.Context, msg queue.Message) error {
+	if err := p.Producer.Publish(ctx, msg); err != nil {
+		return err
+	}
+	p.metrics.NotificationSent()
+	return nil
+}
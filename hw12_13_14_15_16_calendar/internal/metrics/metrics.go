@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "calendar"
+
+	TransportHTTP = "http"
+	TransportGRPC = "grpc"
+
+	resultCommit   = "commit"
+	resultRollback = "rollback"
+)
+
+// Metrics - метрики процесса. Каждый экземпляр использует собственный реестр,
+// поэтому в тестах и разных процессах метрики не пересекаются.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests          *prometheus.CounterVec
+	requestDuration   *prometheus.HistogramVec
+	transactions      *prometheus.CounterVec
+	txRetries         prometheus.Counter
+	eventsCreated     prometheus.Counter
+	eventConflicts    prometheus.Counter
+	notificationsSent prometheus.Counter
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Number of handled API requests by transport, operation and response code.",
+		}, []string{"transport", "operation", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "API request latency by transport and operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"transport", "operation"}),
+		transactions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_transactions_total",
+			Help:      "Number of finished database transactions by result (commit, rollback).",
+		}, []string{"result"}),
+		txRetries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_transaction_retries_total",
+			Help:      "Number of database transactions retried after a serialization failure or deadlock.",
+		}),
+		eventsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "events_created_total",
+			Help:      "Number of created events.",
+		}),
+		eventConflicts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "event_conflicts_total",
+			Help:      "Number of rejected event writes because the date is busy.",
+		}),
+		notificationsSent: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifications_sent_total",
+			Help:      "Number of notifications published to the queue.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.transactions,
+		m.txRetries,
+		m.eventsCreated,
+		m.eventConflicts,
+		m.notificationsSent,
+	)
+	return m
+}
+
+// RegisterDB добавляет статистику пула соединений (sql.DB.Stats) в метрики go_sql_*.
+func (m *Metrics) RegisterDB(db *sql.DB) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// ObserveRequest учитывает обработанный запрос. operation - operationId для HTTP
+// или имя метода для gRPC, code - HTTP-статус или код gRPC.
+func (m *Metrics) ObserveRequest(transport, operation, code string, duration time.Duration) {
+	m.requests.WithLabelValues(transport, operation, code).Inc()
+	m.requestDuration.WithLabelValues(transport, operation).Observe(duration.Seconds())
+}
+
+func (m *Metrics) EventCreated() {
+	m.eventsCreated.Inc()
+}
+
+func (m *Metrics) EventConflict() {
+	m.eventConflicts.Inc()
+}
+
+func (m *Metrics) NotificationSent() {
+	m.notificationsSent.Inc()
+}
+
+var _ database.TxObserver = (*Metrics)(nil)
+
+// TransactionFinished учитывает попытку транзакции: commit - fn и фиксация прошли успешно,
+// rollback - в остальных случаях. Передается менеджеру транзакций как database.TxObserver.
+func (m *Metrics) TransactionFinished(committed bool) {
+	if committed {
+		m.transactions.WithLabelValues(resultCommit).Inc()
+	} else {
+		m.transactions.WithLabelValues(resultRollback).Inc()
+	}
+}
+
+func (m *Metrics) TransactionRetried() {
+	m.txRetries.Inc()
+}
+
+// Handler отдает метрики в формате Prometheus.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
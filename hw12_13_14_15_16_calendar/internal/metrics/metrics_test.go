@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+	memoryqueue "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue/memory"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories/memory"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/services"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_Transactions(t *testing.T) {
+	m := New()
+	ctx := context.Background()
+
+	tm := memory.NewTxManagerWithObserver(memory.NewEventCrudRepository(), m)
+	require.NoError(t, tm.WithTransaction(ctx, nil, func(ctx context.Context, _ *sqlx.Tx) error {
+		// Вложенный вызов - точка сохранения, а не отдельная транзакция
+		return tm.WithTransaction(ctx, nil, func(context.Context, *sqlx.Tx) error { return nil })
+	}))
+	require.Error(t, tm.WithTransaction(ctx, nil, func(context.Context, *sqlx.Tx) error { return errors.New("failed") }))
+	require.NoError(t, tm.WithTransaction(ctx, &sql.TxOptions{ReadOnly: true}, func(context.Context, *sqlx.Tx) error { return nil }))
+	m.TransactionRetried()
+
+	assert.InDelta(t, 2, testutil.ToFloat64(m.transactions.WithLabelValues(resultCommit)), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.transactions.WithLabelValues(resultRollback)), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.txRetries), 0)
+}
+
+func TestInstrumentEventService(t *testing.T) {
+	m := New()
+	ctx := context.Background()
+
+	repo, err := memory.NewEventRepository(memory.NewEventCrudRepository())
+	require.NoError(t, err)
+	service := InstrumentEventService(services.NewEventService(repo, nil), m)
+
+	start := time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC)
+	event := domain.Event{Title: "Meeting", StartDate: start, EndDate: start.Add(time.Hour), UserID: "user-1"}
+
+	created, err := service.CreateEvent(ctx, event)
+	require.NoError(t, err)
+	_, err = service.CreateEvent(ctx, event)
+	require.ErrorIs(t, err, services.ErrDateBusy)
+	_, err = service.CreateEvent(ctx, domain.Event{})
+	require.Error(t, err)
+
+	other := event
+	other.StartDate, other.EndDate = start.Add(2*time.Hour), start.Add(3*time.Hour)
+	otherCreated, err := service.CreateEvent(ctx, other)
+	require.NoError(t, err)
+	_, err = service.UpdateEvent(ctx, otherCreated.ID, event)
+	require.ErrorIs(t, err, services.ErrDateBusy)
+
+	_, err = service.GetEventByID(ctx, created.ID)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 2, testutil.ToFloat64(m.eventsCreated), 0)
+	assert.InDelta(t, 2, testutil.ToFloat64(m.eventConflicts), 0)
+}
+
+func TestInstrumentProducer(t *testing.T) {
+	m := New()
+	ctx := context.Background()
+
+	producer := InstrumentProducer(memoryqueue.NewBroker(), m)
+	require.NoError(t, producer.Publish(ctx, queue.Message{Topic: "notifications", Value: []byte("{}")}))
+	require.NoError(t, producer.Close())
+	require.Error(t, producer.Publish(ctx, queue.Message{Topic: "notifications", Value: []byte("{}")}))
+
+	assert.InDelta(t, 1, testutil.ToFloat64(m.notificationsSent), 0)
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	m := New()
+	m.ObserveRequest(TransportHTTP, "GetEvent", "200", 10*time.Millisecond)
+	m.ObserveRequest(TransportGRPC, "GetEvent", "NotFound", time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `calendar_requests_total{code="200",operation="GetEvent",transport="http"} 1`)
+	assert.Contains(t, body, `calendar_requests_total{code="NotFound",operation="GetEvent",transport="grpc"} 1`)
+	assert.Contains(t, body, `calendar_request_duration_seconds_count{operation="GetEvent",transport="http"} 1`)
+	assert.Contains(t, body, "go_goroutines")
+}
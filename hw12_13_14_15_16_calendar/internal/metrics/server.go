@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+)
+
+const (
+	Path = "/metrics"
+
+	defaultReadTimeout     = 5 * time.Second
+	defaultShutdownTimeout = 5 * time.Second
+)
+
+// Server отдает метрики на отдельном порту, чтобы они не были доступны через публичный API.
+type Server struct {
+	server *http.Server
+	logger logger.Logger
+}
+
+func NewServer(log logger.Logger, m *Metrics, addr string) *Server {
+	mux := http.NewServeMux()
+	mux.Handle(Path, m.Handler())
+
+	return &Server{
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: defaultReadTimeout,
+		},
+		logger: log,
+	}
+}
+
+// Start обслуживает запросы до отмены ctx или вызова Stop.
+func (s *Server) Start(ctx context.Context) error {
+	s.logger.Info("starting metrics server", "addr", s.server.Addr)
+
+	errChan := make(chan error, 1)
+	go func() {
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errChan <- err
+		}
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), defaultShutdownTimeout)
+		defer cancel()
+
+		return s.Stop(shutdownCtx)
+	}
+}
+
+func (s *Server) Stop(ctx context.Context) error {
+	s.logger.Info("stopping metrics server")
+	return s.server.Shutdown(ctx)
+}
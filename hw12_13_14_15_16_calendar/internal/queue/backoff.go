@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Backoff описывает паузы между повторными попытками: пауза растет от InitialInterval
+// в Multiplier раз до MaxInterval. MaxAttempts=0 означает бесконечные попытки.
+type Backoff struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	MaxAttempts     int
+}
+
+func DefaultBackoff() Backoff {
+	return Backoff{
+		InitialInterval: 500 * time.Millisecond,
+		MaxInterval:     30 * time.Second,
+		Multiplier:      2,
+		MaxAttempts:     0,
+	}
+}
+
+// Duration возвращает паузу перед попыткой с номером attempt+1.
+func (b Backoff) Duration(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	d := float64(b.InitialInterval)
+	for i := 1; i < attempt; i++ {
+		d *= multiplier
+		if b.MaxInterval > 0 && d >= float64(b.MaxInterval) {
+			return b.MaxInterval
+		}
+	}
+	if b.MaxInterval > 0 && time.Duration(d) > b.MaxInterval {
+		return b.MaxInterval
+	}
+	return time.Duration(d)
+}
+
+// Connect вызывает connect до первого успеха, делая паузы между попытками.
+// Используется при запуске процессов, когда брокер может быть еще недоступен.
+func Connect[T any](ctx context.Context, backoff Backoff, connect func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	for attempt := 1; ; attempt++ {
+		conn, err := connect(ctx)
+		if err == nil {
+			return conn, nil
+		}
+
+		if backoff.MaxAttempts > 0 && attempt >= backoff.MaxAttempts {
+			return zero, fmt.Errorf("%w: %d: %w", ErrConnectAttempts, attempt, err)
+		}
+		if !sleep(ctx, backoff.Duration(attempt)) {
+			return zero, fmt.Errorf("connect canceled: %w", err)
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
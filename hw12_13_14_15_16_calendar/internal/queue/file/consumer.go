@@ -0,0 +1,214 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+)
+
+const readChunkSize = 4096
+
+type pending struct {
+	msg    queue.Message
+	offset int64
+	next   int64
+}
+
+// Consumer читает журнал топика с сохраненного смещения группы.
+// Смещение сохраняется на диск при подтверждении сообщения, поэтому после перезапуска
+// неподтвержденные сообщения будут доставлены повторно.
+type Consumer struct {
+	config     Config
+	topic      string
+	file       *os.File
+	offsetPath string
+	offset     int64
+	pending    *pending
+	attempt    int
+	closed     bool
+	mu         sync.Mutex
+}
+
+func NewConsumer(config Config, topic, group string) (*Consumer, error) {
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultPollInterval
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create queue directory: %w", err)
+	}
+
+	path, err := logPath(config.Dir, topic)
+	if err != nil {
+		return nil, err
+	}
+	offPath, err := offsetPath(config.Dir, topic, group)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, err := readOffset(offPath)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open queue file: %w", err)
+	}
+
+	return &Consumer{
+		config:     config,
+		topic:      topic,
+		file:       f,
+		offsetPath: offPath,
+		offset:     offset,
+	}, nil
+}
+
+func (c *Consumer) Fetch(ctx context.Context) (*queue.Delivery, error) {
+	ticker := time.NewTicker(c.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		delivery, err := c.tryFetch()
+		if err != nil || delivery != nil {
+			return delivery, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Consumer) tryFetch() (*queue.Delivery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, queue.ErrClosed
+	}
+
+	for c.pending == nil {
+		line, next, err := c.readLine(c.offset)
+		if err != nil {
+			return nil, err
+		}
+		if line == nil {
+			return nil, nil
+		}
+
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			// Поврежденную запись доставить невозможно - пропускаем ее
+			if err := c.commit(next); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		c.pending = &pending{
+			msg: queue.Message{
+				Topic:     c.topic,
+				Key:       rec.Key,
+				Value:     rec.Value,
+				Headers:   rec.Headers,
+				Timestamp: rec.Timestamp,
+			},
+			offset: c.offset,
+			next:   next,
+		}
+	}
+
+	c.attempt++
+	p := c.pending
+	return queue.NewDelivery(p.msg, c.attempt, func(ack, requeue bool) error {
+		return c.settle(p, ack, requeue)
+	}), nil
+}
+
+// readLine читает одну полную строку журнала начиная с offset.
+// Если строка еще не дописана до конца, возвращается nil.
+func (c *Consumer) readLine(offset int64) ([]byte, int64, error) {
+	var line []byte
+	buf := make([]byte, readChunkSize)
+	pos := offset
+	for {
+		n, err := c.file.ReadAt(buf, pos)
+		if idx := bytes.IndexByte(buf[:n], '\n'); idx >= 0 {
+			line = append(line, buf[:idx]...)
+			return line, pos + int64(idx) + 1, nil
+		}
+		line = append(line, buf[:n]...)
+		pos += int64(n)
+
+		if errors.Is(err, io.EOF) {
+			return nil, offset, nil
+		}
+		if err != nil {
+			return nil, offset, fmt.Errorf("failed to read queue file: %w", err)
+		}
+	}
+}
+
+func (c *Consumer) settle(p *pending, ack, requeue bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending != p {
+		return queue.ErrAlreadySettled
+	}
+	if !ack && requeue {
+		return nil
+	}
+	return c.commit(p.next)
+}
+
+func (c *Consumer) commit(offset int64) error {
+	tmp := c.offsetPath + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(offset, 10)), 0o644); err != nil {
+		return fmt.Errorf("failed to write queue offset: %w", err)
+	}
+	if err := os.Rename(tmp, c.offsetPath); err != nil {
+		return fmt.Errorf("failed to commit queue offset: %w", err)
+	}
+	c.offset = offset
+	c.pending = nil
+	c.attempt = 0
+	return nil
+}
+
+func (c *Consumer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.file.Close()
+}
+
+func readOffset(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read queue offset: %w", err)
+	}
+	offset, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse queue offset: %w", err)
+	}
+	return offset, nil
+}
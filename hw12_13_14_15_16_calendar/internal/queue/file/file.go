@@ -0,0 +1,61 @@
+// Package file реализует брокер сообщений поверх файлов: каждый топик - append-only журнал
+// в формате JSON Lines, смещение каждой группы подписчиков хранится в отдельном файле.
+// Позволяет связать планировщик и хранитель без Kafka в рамках одной машины.
+package file
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var ErrInvalidTopic = errors.New("invalid topic name")
+
+const (
+	logExt    = ".log"
+	offsetExt = ".offset"
+
+	defaultPollInterval = 200 * time.Millisecond
+)
+
+type Config struct {
+	// Dir - каталог с журналами топиков.
+	Dir string
+	// Sync - вызывать fsync после каждой записи.
+	Sync bool
+	// PollInterval - как часто подписчик проверяет появление новых сообщений.
+	PollInterval time.Duration
+}
+
+type record struct {
+	Key       []byte            `json:"key,omitempty"`
+	Value     []byte            `json:"value"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+func logPath(dir, topic string) (string, error) {
+	if err := validateName(topic); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, topic+logExt), nil
+}
+
+func offsetPath(dir, topic, group string) (string, error) {
+	if err := validateName(topic); err != nil {
+		return "", err
+	}
+	if err := validateName(group); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, topic+"."+group+offsetExt), nil
+}
+
+func validateName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("%w: %q", ErrInvalidTopic, name)
+	}
+	return nil
+}
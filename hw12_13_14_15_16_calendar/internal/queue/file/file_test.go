@@ -0,0 +1,167 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConfig(t *testing.T) Config {
+	t.Helper()
+	return Config{
+		Dir:          t.TempDir(),
+		Sync:         true,
+		PollInterval: 5 * time.Millisecond,
+	}
+}
+
+func TestFileQueue_PublishFetch(t *testing.T) {
+	ctx := context.Background()
+	config := newTestConfig(t)
+
+	producer, err := NewProducer(config)
+	require.NoError(t, err)
+	defer producer.Close()
+
+	consumer, err := NewConsumer(config, "notifications", "storer")
+	require.NoError(t, err)
+	defer consumer.Close()
+
+	require.NoError(t, producer.Publish(ctx, queue.Message{
+		Topic:   "notifications",
+		Key:     []byte("key-1"),
+		Value:   []byte(`{"id":"1"}`),
+		Headers: map[string]string{"content-type": "application/json"},
+	}))
+	require.NoError(t, producer.Publish(ctx, queue.Message{Topic: "notifications", Value: []byte("second")}))
+
+	t.Run("fetch returns message with key and headers", func(t *testing.T) {
+		d, err := consumer.Fetch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "notifications", d.Topic)
+		assert.Equal(t, []byte("key-1"), d.Key)
+		assert.Equal(t, []byte(`{"id":"1"}`), d.Value)
+		assert.Equal(t, "application/json", d.Headers["content-type"])
+		assert.False(t, d.Timestamp.IsZero())
+		assert.Equal(t, 1, d.Attempt)
+
+		require.NoError(t, d.Nack(true))
+	})
+
+	t.Run("nacked message is redelivered", func(t *testing.T) {
+		d, err := consumer.Fetch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("key-1"), d.Key)
+		assert.Equal(t, 2, d.Attempt)
+
+		require.NoError(t, d.Ack())
+	})
+
+	t.Run("acked message is not redelivered", func(t *testing.T) {
+		d, err := consumer.Fetch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("second"), d.Value)
+		assert.Equal(t, 1, d.Attempt)
+	})
+
+	t.Run("fetch waits for new messages", func(t *testing.T) {
+		timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+
+		// Сообщение "second" еще не подтверждено, поэтому вернется оно же
+		d, err := consumer.Fetch(timeoutCtx)
+		require.NoError(t, err)
+		require.NoError(t, d.Nack(false))
+
+		_, err = consumer.Fetch(timeoutCtx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestFileQueue_OffsetSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	config := newTestConfig(t)
+
+	producer, err := NewProducer(config)
+	require.NoError(t, err)
+	defer producer.Close()
+
+	for _, value := range []string{"one", "two", "three"} {
+		require.NoError(t, producer.Publish(ctx, queue.Message{Topic: "topic", Value: []byte(value)}))
+	}
+
+	consumer, err := NewConsumer(config, "topic", "group")
+	require.NoError(t, err)
+	d, err := consumer.Fetch(ctx)
+	require.NoError(t, err)
+	require.NoError(t, d.Ack())
+
+	// Второе сообщение получено, но не подтверждено
+	_, err = consumer.Fetch(ctx)
+	require.NoError(t, err)
+	require.NoError(t, consumer.Close())
+
+	consumer, err = NewConsumer(config, "topic", "group")
+	require.NoError(t, err)
+	defer consumer.Close()
+
+	d, err = consumer.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("two"), d.Value)
+
+	t.Run("other group reads from the beginning", func(t *testing.T) {
+		other, err := NewConsumer(config, "topic", "other")
+		require.NoError(t, err)
+		defer other.Close()
+
+		d, err := other.Fetch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("one"), d.Value)
+	})
+}
+
+func TestFileQueue_PartialAndCorruptedRecords(t *testing.T) {
+	ctx := context.Background()
+	config := newTestConfig(t)
+
+	path := filepath.Join(config.Dir, "topic"+logExt)
+	require.NoError(t, os.WriteFile(path, []byte("not json\n{\"value\":\"b2s=\""), 0o644))
+
+	consumer, err := NewConsumer(config, "topic", "group")
+	require.NoError(t, err)
+	defer consumer.Close()
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = consumer.Fetch(timeoutCtx)
+	require.ErrorIs(t, err, context.DeadlineExceeded, "incomplete record must not be delivered")
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString("}\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	d, err := consumer.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ok"), d.Value)
+}
+
+func TestFileQueue_InvalidTopic(t *testing.T) {
+	config := newTestConfig(t)
+
+	_, err := NewConsumer(config, "../escape", "group")
+	require.ErrorIs(t, err, ErrInvalidTopic)
+
+	producer, err := NewProducer(config)
+	require.NoError(t, err)
+	defer producer.Close()
+	err = producer.Publish(context.Background(), queue.Message{Topic: "a/b"})
+	require.ErrorIs(t, err, ErrInvalidTopic)
+}
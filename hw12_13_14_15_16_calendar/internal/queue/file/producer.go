@@ -0,0 +1,104 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+)
+
+type Producer struct {
+	config Config
+	files  map[string]*os.File
+	closed bool
+	mu     sync.Mutex
+}
+
+// NewProducer создает каталог журналов, если его еще нет.
+func NewProducer(config Config) (*Producer, error) {
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create queue directory: %w", err)
+	}
+	return &Producer{
+		config: config,
+		files:  make(map[string]*os.File),
+	}, nil
+}
+
+func (p *Producer) Publish(ctx context.Context, msg queue.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+	line, err := json.Marshal(record{
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Headers:   msg.Headers,
+		Timestamp: msg.Timestamp,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	line = append(line, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return queue.ErrClosed
+	}
+
+	f, err := p.topicFile(msg.Topic)
+	if err != nil {
+		return err
+	}
+	// Запись одним вызовом, чтобы подписчик не увидел половину строки как целую
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("failed to append message: %w", err)
+	}
+	if p.config.Sync {
+		if err := f.Sync(); err != nil {
+			return fmt.Errorf("failed to sync queue file: %w", err)
+		}
+	}
+	return nil
+}
+
+func (p *Producer) topicFile(topic string) (*os.File, error) {
+	if f, ok := p.files[topic]; ok {
+		return f, nil
+	}
+	path, err := logPath(p.config.Dir, topic)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open queue file: %w", err)
+	}
+	p.files[topic] = f
+	return f, nil
+}
+
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+
+	var closeErr error
+	for topic, f := range p.files {
+		if err := f.Close(); err != nil && closeErr == nil {
+			closeErr = fmt.Errorf("failed to close queue file %s: %w", topic, err)
+		}
+	}
+	return closeErr
+}
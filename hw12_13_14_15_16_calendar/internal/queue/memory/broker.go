@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+)
+
+// Broker - брокер сообщений внутри процесса для тестов планировщика и хранителя.
+// Сообщения, подтвержденные всеми подписчиками топика, удаляются; пока у топика нет
+// подписчиков, сообщения хранятся, чтобы их получил подписчик, подключившийся позже.
+type Broker struct {
+	topics    map[string]*topic
+	published chan struct{}
+	closed    bool
+	mu        sync.RWMutex
+}
+
+// topic - хранимые сообщения топика и подтвержденные смещения его подписчиков.
+type topic struct {
+	messages []queue.Message
+	// base - смещение первого хранимого сообщения от начала топика.
+	base      int
+	committed map[*Consumer]int
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		topics:    make(map[string]*topic),
+		published: make(chan struct{}),
+	}
+}
+
+func (b *Broker) Publish(ctx context.Context, msg queue.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return queue.ErrClosed
+	}
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+	t := b.topic(msg.Topic)
+	t.messages = append(t.messages, msg)
+
+	// Будим всех ожидающих подписчиков
+	close(b.published)
+	b.published = make(chan struct{})
+	return nil
+}
+
+// Messages возвращает копию хранимых сообщений топика.
+func (b *Broker) Messages(name string) []queue.Message {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	t, ok := b.topics[name]
+	if !ok {
+		return []queue.Message{}
+	}
+	result := make([]queue.Message, len(t.messages))
+	copy(result, t.messages)
+	return result
+}
+
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.closed {
+		b.closed = true
+		close(b.published)
+	}
+	return nil
+}
+
+// topic возвращает топик, создавая его при первом обращении. Вызывается под блокировкой.
+func (b *Broker) topic(name string) *topic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &topic{committed: make(map[*Consumer]int)}
+		b.topics[name] = t
+	}
+	return t
+}
+
+// subscribe регистрирует подписчика и возвращает смещение, с которого он начнет чтение.
+func (b *Broker) subscribe(name string, c *Consumer) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t := b.topic(name)
+	t.committed[c] = t.base
+	return t.base
+}
+
+// commit запоминает подтвержденное смещение подписчика и удаляет сообщения,
+// которые подтвердили все подписчики.
+func (b *Broker) commit(name string, c *Consumer, offset int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t := b.topic(name)
+	if _, ok := t.committed[c]; !ok {
+		return
+	}
+	t.committed[c] = offset
+	t.trim()
+}
+
+// unsubscribe удаляет подписчика, чтобы он больше не удерживал сообщения топика.
+func (b *Broker) unsubscribe(name string, c *Consumer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t := b.topic(name)
+	delete(t.committed, c)
+	t.trim()
+}
+
+func (t *topic) trim() {
+	if len(t.committed) == 0 {
+		return
+	}
+	lowest := -1
+	for _, offset := range t.committed {
+		if lowest < 0 || offset < lowest {
+			lowest = offset
+		}
+	}
+	n := min(lowest-t.base, len(t.messages))
+	if n <= 0 {
+		return
+	}
+	// Обнуляем удаленные сообщения, чтобы их содержимое не удерживалось в массиве
+	clear(t.messages[:n])
+	t.messages = t.messages[n:]
+	t.base += n
+}
+
+// next возвращает сообщение топика по смещению. Если сообщения еще нет,
+// возвращается канал, который закроется при публикации следующего сообщения.
+func (b *Broker) next(name string, offset int) (queue.Message, bool, <-chan struct{}, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if t, ok := b.topics[name]; ok && offset-t.base < len(t.messages) {
+		return t.messages[offset-t.base], true, nil, nil
+	}
+	if b.closed {
+		return queue.Message{}, false, nil, queue.ErrClosed
+	}
+	return queue.Message{}, false, b.published, nil
+}
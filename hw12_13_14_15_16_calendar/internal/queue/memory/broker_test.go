@@ -0,0 +1,185 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroker_PublishConsume(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	broker := NewBroker()
+	consumer := NewConsumer(broker, "topic")
+	backoff := queue.Backoff{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond}
+
+	var (
+		mu       sync.Mutex
+		received []string
+		failed   bool
+	)
+	done := make(chan error, 1)
+	go func() {
+		done <- queue.Consume(ctx, consumer, queue.ConsumeOptions{Backoff: backoff}, func(_ context.Context, msg queue.Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			// Первая попытка обработать второе сообщение завершается ошибкой
+			if string(msg.Value) == "second" && !failed {
+				failed = true
+				return errors.New("temporary error")
+			}
+			received = append(received, string(msg.Value))
+			return nil
+		})
+	}()
+
+	require.NoError(t, broker.Publish(ctx, queue.Message{Topic: "topic", Value: []byte("first")}))
+	require.NoError(t, broker.Publish(ctx, queue.Message{Topic: "other", Value: []byte("ignored")}))
+	require.NoError(t, broker.Publish(ctx, queue.Message{Topic: "topic", Value: []byte("second")}))
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == 2
+	}, time.Second, time.Millisecond)
+
+	mu.Lock()
+	assert.Equal(t, []string{"first", "second"}, received)
+	mu.Unlock()
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestBroker_TrimAcknowledged(t *testing.T) {
+	ctx := context.Background()
+	broker := NewBroker()
+	fast := NewConsumer(broker, "topic")
+	slow := NewConsumer(broker, "topic")
+
+	for _, value := range []string{"first", "second", "third"} {
+		require.NoError(t, broker.Publish(ctx, queue.Message{Topic: "topic", Value: []byte(value)}))
+	}
+
+	consume := func(c *Consumer, n int) {
+		for range n {
+			delivery, err := c.Fetch(ctx)
+			require.NoError(t, err)
+			require.NoError(t, delivery.Ack())
+		}
+	}
+
+	consume(fast, 3)
+	assert.Len(t, broker.Messages("topic"), 3, "slow consumer holds all messages")
+
+	consume(slow, 2)
+	messages := broker.Messages("topic")
+	require.Len(t, messages, 1)
+	assert.Equal(t, "third", string(messages[0].Value))
+
+	// Подписчик, подключившийся позже, читает с первого хранимого сообщения
+	late := NewConsumer(broker, "topic")
+	delivery, err := late.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "third", string(delivery.Value))
+	require.NoError(t, late.Close())
+
+	require.NoError(t, slow.Close())
+	assert.Empty(t, broker.Messages("topic"), "closed consumer releases messages")
+
+	require.NoError(t, broker.Publish(ctx, queue.Message{Topic: "topic", Value: []byte("fourth")}))
+	consume(fast, 1)
+	assert.Empty(t, broker.Messages("topic"))
+}
+
+func TestConsume_DeadLetter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	broker := NewBroker()
+	consumer := NewConsumer(broker, "topic")
+	opts := queue.ConsumeOptions{
+		Backoff:     queue.Backoff{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond},
+		MaxAttempts: 3,
+		DeadLetter:  broker,
+	}
+
+	var (
+		mu       sync.Mutex
+		attempts = map[string]int{}
+		received []string
+	)
+	done := make(chan error, 1)
+	go func() {
+		done <- queue.Consume(ctx, consumer, opts, func(_ context.Context, msg queue.Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			value := string(msg.Value)
+			attempts[value]++
+			switch value {
+			case "poison":
+				return fmt.Errorf("bad payload: %w", queue.ErrUnprocessable)
+			case "failing":
+				return errors.New("storage is down")
+			}
+			received = append(received, value)
+			return nil
+		})
+	}()
+
+	for _, value := range []string{"poison", "failing", "ok"} {
+		require.NoError(t, broker.Publish(ctx, queue.Message{
+			Topic: "topic", Value: []byte(value), Headers: map[string]string{"trace": value},
+		}))
+	}
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == 1
+	}, time.Second, time.Millisecond)
+
+	mu.Lock()
+	assert.Equal(t, []string{"ok"}, received)
+	assert.Equal(t, 1, attempts["poison"], "unprocessable message is not requeued")
+	assert.Equal(t, 3, attempts["failing"])
+	mu.Unlock()
+
+	dead := broker.Messages("topic.dlq")
+	require.Len(t, dead, 2)
+	assert.Equal(t, "poison", string(dead[0].Value))
+	assert.Equal(t, "topic", dead[0].Headers[queue.HeaderOriginalTopic])
+	assert.Equal(t, "1", dead[0].Headers[queue.HeaderAttempts])
+	assert.Equal(t, "poison", dead[0].Headers["trace"])
+	assert.Equal(t, "failing", string(dead[1].Value))
+	assert.Equal(t, "3", dead[1].Headers[queue.HeaderAttempts])
+	assert.Equal(t, "storage is down", dead[1].Headers[queue.HeaderError])
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestBroker_Close(t *testing.T) {
+	ctx := context.Background()
+	broker := NewBroker()
+	consumer := NewConsumer(broker, "topic")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- queue.Consume(ctx, consumer, queue.ConsumeOptions{Backoff: queue.DefaultBackoff()}, func(context.Context, queue.Message) error {
+			return nil
+		})
+	}()
+
+	require.NoError(t, broker.Close())
+	require.NoError(t, <-done)
+	assert.ErrorIs(t, broker.Publish(ctx, queue.Message{Topic: "topic"}), queue.ErrClosed)
+}
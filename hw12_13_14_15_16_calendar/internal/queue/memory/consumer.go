@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+)
+
+// Consumer читает топик брокера с первого хранимого сообщения, каждый подписчик хранит
+// собственное смещение. Закрытый подписчик перестает удерживать сообщения в брокере.
+type Consumer struct {
+	broker  *Broker
+	topic   string
+	offset  int
+	attempt int
+	mu      sync.Mutex
+}
+
+func NewConsumer(broker *Broker, topic string) *Consumer {
+	c := &Consumer{
+		broker: broker,
+		topic:  topic,
+	}
+	c.offset = broker.subscribe(topic, c)
+	return c
+}
+
+func (c *Consumer) Fetch(ctx context.Context) (*queue.Delivery, error) {
+	c.mu.Lock()
+	offset := c.offset
+	c.mu.Unlock()
+
+	for {
+		msg, ok, published, err := c.broker.next(c.topic, offset)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.attempt++
+			return queue.NewDelivery(msg, c.attempt, func(ack, requeue bool) error {
+				return c.settle(offset, ack, requeue)
+			}), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-published:
+		}
+	}
+}
+
+func (c *Consumer) settle(offset int, ack, requeue bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.offset != offset {
+		return queue.ErrAlreadySettled
+	}
+	if !ack && requeue {
+		return nil
+	}
+	c.offset++
+	c.attempt = 0
+	c.broker.commit(c.topic, c, c.offset)
+	return nil
+}
+
+func (c *Consumer) Close() error {
+	c.broker.unsubscribe(c.topic, c)
+	return nil
+}
@@ -0,0 +1,175 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrClosed          = errors.New("queue is closed")
+	ErrAlreadySettled  = errors.New("delivery is already acknowledged")
+	ErrConnectAttempts = errors.New("connect attempts exceeded")
+	// ErrUnprocessable возвращается обработчиком для сообщений, повторная доставка которых
+	// не поможет (например, не разбирается JSON). Такие сообщения сразу отклоняются.
+	ErrUnprocessable = errors.New("message cannot be processed")
+)
+
+// Заголовки, которые Consume добавляет к сообщению при отправке в топик недоставленных.
+const (
+	HeaderOriginalTopic = "x-original-topic"
+	HeaderError         = "x-error"
+	HeaderAttempts      = "x-attempts"
+
+	deadLetterSuffix = ".dlq"
+)
+
+// Message - сообщение, передаваемое через брокер.
+type Message struct {
+	Topic     string
+	Key       []byte
+	Value     []byte
+	Headers   map[string]string
+	Timestamp time.Time
+}
+
+// Producer публикует сообщения в брокер. Процессы работают только с этим интерфейсом
+// и не зависят от конкретной реализации брокера.
+type Producer interface {
+	Publish(ctx context.Context, msg Message) error
+	Close() error
+}
+
+// Consumer читает сообщения из брокера.
+type Consumer interface {
+	// Fetch блокируется до появления сообщения, отмены контекста или закрытия брокера.
+	// Пока полученное сообщение не подтверждено, Fetch возвращает его же.
+	Fetch(ctx context.Context) (*Delivery, error)
+	Close() error
+}
+
+// Delivery - полученное сообщение, которое нужно подтвердить (Ack) или отклонить (Nack).
+type Delivery struct {
+	Message
+	// Attempt - номер попытки доставки, начиная с 1.
+	Attempt int
+
+	settle  func(ack, requeue bool) error
+	settled bool
+}
+
+// NewDelivery используется реализациями брокера. Функция settle вызывается один раз:
+// ack=true - сообщение обработано, ack=false - отклонено, requeue - доставить повторно.
+func NewDelivery(msg Message, attempt int, settle func(ack, requeue bool) error) *Delivery {
+	return &Delivery{
+		Message: msg,
+		Attempt: attempt,
+		settle:  settle,
+	}
+}
+
+func (d *Delivery) Ack() error {
+	return d.finish(true, false)
+}
+
+// Nack отклоняет сообщение. При requeue=true сообщение будет доставлено повторно,
+// иначе оно пропускается.
+func (d *Delivery) Nack(requeue bool) error {
+	return d.finish(false, requeue)
+}
+
+func (d *Delivery) finish(ack, requeue bool) error {
+	if d.settled {
+		return ErrAlreadySettled
+	}
+	d.settled = true
+	return d.settle(ack, requeue)
+}
+
+// Handler обрабатывает полученное сообщение.
+type Handler func(ctx context.Context, msg Message) error
+
+// ConsumeOptions - параметры обработки сообщений в Consume.
+type ConsumeOptions struct {
+	// Backoff - паузы перед повторной доставкой сообщения, которое не удалось обработать.
+	Backoff Backoff
+	// MaxAttempts - сколько раз доставлять сообщение, прежде чем отклонить его; 0 - без ограничения.
+	MaxAttempts int
+	// DeadLetter - куда отправлять отклоненные сообщения; nil - отклоненные сообщения отбрасываются.
+	DeadLetter Producer
+	// DeadLetterTopic - топик отклоненных сообщений, по умолчанию <топик сообщения>.dlq.
+	DeadLetterTopic string
+	// OnReject вызывается для каждого отклоненного сообщения, например для записи в лог.
+	OnReject func(msg Message, attempt int, err error)
+}
+
+// Consume читает сообщения до отмены контекста или закрытия брокера. Успешно обработанные
+// сообщения подтверждаются, при ошибке обработчика сообщение возвращается в очередь
+// и доставляется повторно с паузой, растущей по opts.Backoff. Сообщение отклоняется
+// и отправляется в opts.DeadLetter, если обработчик вернул ErrUnprocessable
+// или исчерпано opts.MaxAttempts попыток, чтобы одно сообщение не блокировало очередь.
+func Consume(ctx context.Context, consumer Consumer, opts ConsumeOptions, handler Handler) error {
+	for {
+		delivery, err := consumer.Fetch(ctx)
+		if err != nil {
+			if errors.Is(err, ErrClosed) || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		err = handler(ctx, delivery.Message)
+		if err == nil {
+			if err := delivery.Ack(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if errors.Is(err, ErrUnprocessable) || opts.MaxAttempts > 0 && delivery.Attempt >= opts.MaxAttempts {
+			rejectErr := opts.reject(ctx, delivery, err)
+			if rejectErr == nil {
+				if err := delivery.Nack(false); err != nil {
+					return err
+				}
+				continue
+			}
+			// Сообщение не удалось сохранить в топике недоставленных - оставляем его в очереди
+			err = rejectErr
+		}
+
+		if !sleep(ctx, opts.Backoff.Duration(delivery.Attempt)) {
+			return nil
+		}
+		if err := delivery.Nack(true); err != nil {
+			return err
+		}
+	}
+}
+
+// reject отправляет сообщение в топик недоставленных с причиной отказа в заголовках.
+func (opts ConsumeOptions) reject(ctx context.Context, delivery *Delivery, cause error) error {
+	if opts.DeadLetter != nil {
+		msg := delivery.Message
+		msg.Topic = opts.DeadLetterTopic
+		if msg.Topic == "" {
+			msg.Topic = delivery.Topic + deadLetterSuffix
+		}
+		msg.Headers = make(map[string]string, len(delivery.Headers)+3)
+		for k, v := range delivery.Headers {
+			msg.Headers[k] = v
+		}
+		msg.Headers[HeaderOriginalTopic] = delivery.Topic
+		msg.Headers[HeaderError] = cause.Error()
+		msg.Headers[HeaderAttempts] = strconv.Itoa(delivery.Attempt)
+		if err := opts.DeadLetter.Publish(ctx, msg); err != nil {
+			return fmt.Errorf("failed to publish dead letter: %w", err)
+		}
+	}
+	if opts.OnReject != nil {
+		opts.OnReject(delivery.Message, delivery.Attempt, cause)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff_Duration(t *testing.T) {
+	b := Backoff{
+		InitialInterval: 100 * time.Millisecond,
+		MaxInterval:     time.Second,
+		Multiplier:      2,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, b.Duration(0))
+	assert.Equal(t, 100*time.Millisecond, b.Duration(1))
+	assert.Equal(t, 200*time.Millisecond, b.Duration(2))
+	assert.Equal(t, 400*time.Millisecond, b.Duration(3))
+	assert.Equal(t, 800*time.Millisecond, b.Duration(4))
+	assert.Equal(t, time.Second, b.Duration(5))
+	assert.Equal(t, time.Second, b.Duration(100))
+}
+
+func TestConnect(t *testing.T) {
+	backoff := Backoff{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond}
+
+	t.Run("retries until success", func(t *testing.T) {
+		attempts := 0
+		conn, err := Connect(context.Background(), backoff, func(context.Context) (string, error) {
+			attempts++
+			if attempts < 3 {
+				return "", errors.New("unavailable")
+			}
+			return "connected", nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "connected", conn)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("stops after max attempts", func(t *testing.T) {
+		limited := backoff
+		limited.MaxAttempts = 2
+		attempts := 0
+		_, err := Connect(context.Background(), limited, func(context.Context) (string, error) {
+			attempts++
+			return "", errors.New("unavailable")
+		})
+		require.ErrorIs(t, err, ErrConnectAttempts)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("stops on context cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := Connect(ctx, backoff, func(context.Context) (string, error) {
+			return "", errors.New("unavailable")
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestDelivery_SettleOnce(t *testing.T) {
+	calls := 0
+	d := NewDelivery(Message{Topic: "topic"}, 1, func(bool, bool) error {
+		calls++
+		return nil
+	})
+
+	require.NoError(t, d.Ack())
+	assert.ErrorIs(t, d.Nack(true), ErrAlreadySettled)
+	assert.Equal(t, 1, calls)
+}
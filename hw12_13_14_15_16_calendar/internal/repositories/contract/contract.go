@@ -0,0 +1,47 @@
+// Package contract содержит общие тесты репозиториев: каждое хранилище (memory, PostgreSQL, SQLite)
+// прогоняет один и тот же набор проверок, поэтому их поведение не расходится.
+package contract
+
+import (
+	"testing"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
+	"github.com/jmoiron/sqlx"
+)
+
+// Backend - хранилище, на котором проверяется контракт.
+type Backend struct {
+	Events        repositories.CompositeEventRepository
+	Notifications repositories.NotificationRepository
+	// Exec - исполнитель запросов вне транзакции, передается в методы репозиториев.
+	Exec sqlx.ExtContext
+	// TxManager - транзакции хранилища; методы репозиториев получают ctx и tx из WithTransaction.
+	TxManager database.TxManager
+}
+
+// NewBackend возвращает пустое хранилище; вызывается перед каждым тестом контракта.
+type NewBackend func(t *testing.T) Backend
+
+// Run прогоняет все тесты контракта.
+func Run(t *testing.T, newBackend NewBackend) {
+	t.Helper()
+
+	t.Run("EventCrudRepository", func(t *testing.T) { testEventCrud(t, newBackend) })
+	t.Run("Transaction", func(t *testing.T) { testTransaction(t, newBackend(t)) })
+	t.Run("LockUser", func(t *testing.T) { testLockUser(t, newBackend(t)) })
+	t.Run("ConcurrentCreate", func(t *testing.T) { testConcurrentCreate(t, newBackend(t)) })
+	t.Run("FindEvent", func(t *testing.T) { testFindEvent(t, newBackend(t)) })
+	t.Run("CRUD", func(t *testing.T) { testCRUD(t, newBackend(t)) })
+	t.Run("BoundaryConditions", func(t *testing.T) { testBoundaryConditions(t, newBackend) })
+	t.Run("Notifications", func(t *testing.T) { testNotifications(t, newBackend(t)) })
+	t.Run("DeleteOlderThan", func(t *testing.T) { testDeleteOlderThan(t, newBackend(t)) })
+	t.Run("Trash", func(t *testing.T) { testTrash(t, newBackend(t)) })
+	t.Run("Recurring", func(t *testing.T) { testRecurring(t, newBackend(t)) })
+	t.Run("RecurringWeekly", func(t *testing.T) { testRecurringWeekly(t, newBackend(t)) })
+	t.Run("FindEventPage", func(t *testing.T) { testFindEventPage(t, newBackend(t)) })
+	t.Run("FindEventPageWindow", func(t *testing.T) { testFindEventPageWindow(t, newBackend(t)) })
+	t.Run("FindEventPageManySeries", func(t *testing.T) { testFindEventPageManySeries(t, newBackend(t)) })
+	t.Run("Ordering", func(t *testing.T) { testOrdering(t, newBackend(t)) })
+	t.Run("NotificationRepository", func(t *testing.T) { testNotificationRepository(t, newBackend(t)) })
+}
@@ -0,0 +1,468 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const missingID = "00000000-0000-0000-0000-000000000000"
+
+func testEventCrud(t *testing.T, newBackend NewBackend) {
+	t.Helper()
+	ctx := context.Background()
+
+	t.Run("Create", func(t *testing.T) {
+		b := newBackend(t)
+		repo := b.Events
+
+		event := domain.Event{
+			Title:       "Test Event",
+			StartDate:   time.Now().Truncate(time.Second),
+			EndDate:     time.Now().Add(time.Hour).Truncate(time.Second),
+			Description: "Test Description",
+			UserID:      "550e8400-e29b-41d4-a716-446655440001",
+			OffsetTime:  0,
+		}
+
+		t.Run("successful create", func(t *testing.T) {
+			createdEvent, err := repo.Create(ctx, b.Exec, event)
+			require.NoError(t, err)
+			require.NotNil(t, createdEvent)
+			assert.NotEmpty(t, createdEvent.ID, "ID should be auto-generated")
+
+			// Verify event was created
+			retrieved, err := repo.GetByID(ctx, b.Exec, createdEvent.ID)
+			require.NoError(t, err)
+			assert.Equal(t, createdEvent.ID, retrieved.ID)
+			assert.Equal(t, event.Title, retrieved.Title)
+			assert.Equal(t, event.UserID, retrieved.UserID)
+		})
+
+		t.Run("multiple creates generate different IDs", func(t *testing.T) {
+			event1, err := repo.Create(ctx, b.Exec, event)
+			require.NoError(t, err)
+			event2, err := repo.Create(ctx, b.Exec, event)
+			require.NoError(t, err)
+			assert.NotEqual(t, event1.ID, event2.ID, "Each create should generate unique ID")
+		})
+	})
+
+	t.Run("GetByID", func(t *testing.T) {
+		b := newBackend(t)
+		repo := b.Events
+
+		event := domain.Event{
+			Title:       "Test Event GetByID",
+			StartDate:   time.Now().Truncate(time.Second),
+			EndDate:     time.Now().Add(time.Hour).Truncate(time.Second),
+			Description: "Test Description",
+			UserID:      "550e8400-e29b-41d4-a716-446655440002",
+			OffsetTime:  0,
+		}
+
+		t.Run("get existing event", func(t *testing.T) {
+			createdEvent, err := repo.Create(ctx, b.Exec, event)
+			require.NoError(t, err)
+
+			retrieved, err := repo.GetByID(ctx, b.Exec, createdEvent.ID)
+			require.NoError(t, err)
+			assert.NotNil(t, retrieved)
+			assert.Equal(t, createdEvent.ID, retrieved.ID)
+			assert.Equal(t, event.Title, retrieved.Title)
+		})
+
+		t.Run("get non-existing event", func(t *testing.T) {
+			retrieved, err := repo.GetByID(ctx, b.Exec, missingID)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, repositories.ErrEntityNotFound)
+			assert.Nil(t, retrieved)
+		})
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		b := newBackend(t)
+		repo := b.Events
+
+		event := domain.Event{
+			Title:       "Original Title",
+			StartDate:   time.Now().Truncate(time.Second),
+			EndDate:     time.Now().Add(time.Hour).Truncate(time.Second),
+			Description: "Original Description",
+			UserID:      "550e8400-e29b-41d4-a716-446655440003",
+			OffsetTime:  0,
+		}
+
+		t.Run("update existing event", func(t *testing.T) {
+			createdEvent, err := repo.Create(ctx, b.Exec, event)
+			require.NoError(t, err)
+
+			updatedEvent := event
+			updatedEvent.Title = "Updated Title"
+			updatedEvent.Description = "Updated Description"
+
+			result, err := repo.Update(ctx, b.Exec, createdEvent.ID, updatedEvent)
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			assert.Equal(t, createdEvent.ID, result.ID, "ID is taken from the argument")
+
+			retrieved, err := repo.GetByID(ctx, b.Exec, createdEvent.ID)
+			require.NoError(t, err)
+			assert.Equal(t, createdEvent.ID, retrieved.ID)
+			assert.Equal(t, "Updated Title", retrieved.Title)
+			assert.Equal(t, "Updated Description", retrieved.Description)
+		})
+
+		t.Run("update non-existing event", func(t *testing.T) {
+			result, err := repo.Update(ctx, b.Exec, missingID, event)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, repositories.ErrEntityNotFound)
+			assert.Nil(t, result)
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		b := newBackend(t)
+		repo := b.Events
+
+		event := domain.Event{
+			Title:       "Event to Delete",
+			StartDate:   time.Now().Truncate(time.Second),
+			EndDate:     time.Now().Add(time.Hour).Truncate(time.Second),
+			Description: "Will be deleted",
+			UserID:      "550e8400-e29b-41d4-a716-446655440004",
+			OffsetTime:  0,
+		}
+
+		t.Run("delete existing event", func(t *testing.T) {
+			createdEvent, err := repo.Create(ctx, b.Exec, event)
+			require.NoError(t, err)
+
+			err = repo.Delete(ctx, b.Exec, createdEvent.ID, 0)
+			require.NoError(t, err)
+
+			// Verify event was deleted
+			retrieved, err := repo.GetByID(ctx, b.Exec, createdEvent.ID)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, repositories.ErrEntityNotFound)
+			assert.Nil(t, retrieved)
+		})
+
+		t.Run("delete non-existing event", func(t *testing.T) {
+			err := repo.Delete(ctx, b.Exec, missingID, 0)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, repositories.ErrEntityNotFound)
+		})
+	})
+
+	t.Run("Timestamps", func(t *testing.T) {
+		b := newBackend(t)
+		repo := b.Events
+
+		start := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+		created, err := repo.Create(ctx, b.Exec, domain.Event{
+			Title:     "Timestamps",
+			StartDate: start,
+			EndDate:   start.Add(time.Hour),
+			UserID:    "550e8400-e29b-41d4-a716-446655440005",
+		})
+		require.NoError(t, err)
+		require.False(t, created.CreatedAt.IsZero(), "created_at is set by storage")
+		assert.False(t, created.UpdatedAt.Before(created.CreatedAt))
+
+		// Время создания из аргумента игнорируется
+		event := *created
+		event.CreatedAt = time.Time{}
+		event.Title = "Timestamps updated"
+		updated, err := repo.Update(ctx, b.Exec, created.ID, event)
+		require.NoError(t, err)
+		assert.True(t, created.CreatedAt.Equal(updated.CreatedAt))
+		assert.False(t, updated.UpdatedAt.Before(created.UpdatedAt))
+
+		retrieved, err := repo.GetByID(ctx, b.Exec, created.ID)
+		require.NoError(t, err)
+		assert.True(t, created.CreatedAt.Equal(retrieved.CreatedAt))
+		assert.True(t, updated.UpdatedAt.Equal(retrieved.UpdatedAt))
+	})
+
+	t.Run("Version", func(t *testing.T) {
+		b := newBackend(t)
+		repo := b.Events
+		userID := "550e8400-e29b-41d4-a716-446655440006"
+
+		start := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+		created, err := repo.Create(ctx, b.Exec, domain.Event{
+			Title:     "Version 1",
+			StartDate: start,
+			EndDate:   start.Add(time.Hour),
+			UserID:    userID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), created.Version)
+
+		event := *created
+		event.Title = "Version 2"
+		updated, err := repo.Update(ctx, b.Exec, created.ID, event)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), updated.Version)
+
+		retrieved, err := repo.GetByID(ctx, b.Exec, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), retrieved.Version)
+
+		found, err := repo.FindEvent(ctx, b.Exec, userID, nil, nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, int64(2), found[0].Version)
+
+		// Изменение и удаление по устаревшей версии отклоняются
+		event.Title = "Stale"
+		_, err = repo.Update(ctx, b.Exec, created.ID, event)
+		require.ErrorIs(t, err, repositories.ErrVersionMismatch)
+		require.ErrorIs(t, repo.Delete(ctx, b.Exec, created.ID, created.Version), repositories.ErrVersionMismatch)
+
+		retrieved, err = repo.GetByID(ctx, b.Exec, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Version 2", retrieved.Title)
+		assert.Equal(t, int64(2), retrieved.Version)
+
+		// Версия 0 не проверяется
+		event.Version = 0
+		event.Title = "Version 3"
+		updated, err = repo.Update(ctx, b.Exec, created.ID, event)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), updated.Version)
+
+		// Для отсутствующего события ошибка версии не возвращается
+		event.Version = 1
+		_, err = repo.Update(ctx, b.Exec, missingID, event)
+		require.ErrorIs(t, err, repositories.ErrEntityNotFound)
+		require.ErrorIs(t, repo.Delete(ctx, b.Exec, missingID, 1), repositories.ErrEntityNotFound)
+
+		require.NoError(t, repo.Delete(ctx, b.Exec, created.ID, updated.Version))
+		_, err = repo.GetByID(ctx, b.Exec, created.ID)
+		require.ErrorIs(t, err, repositories.ErrEntityNotFound)
+	})
+}
+
+// testTransaction проверяет, что изменения в транзакции сохраняются только после фиксации,
+// ошибка или паника откатывают их, а ошибка вложенной транзакции - только ее изменения.
+func testTransaction(t *testing.T, b Backend) {
+	t.Helper()
+	ctx := context.Background()
+	repo := b.Events
+	userID := "550e8400-e29b-41d4-a716-446655440099"
+
+	event1 := domain.Event{
+		Title:       "Transaction Event 1",
+		StartDate:   time.Now().Truncate(time.Second),
+		EndDate:     time.Now().Add(time.Hour).Truncate(time.Second),
+		Description: "First event in transaction",
+		UserID:      userID,
+		OffsetTime:  0,
+	}
+
+	event2 := domain.Event{
+		Title:       "Transaction Event 2",
+		StartDate:   time.Now().Truncate(time.Second),
+		EndDate:     time.Now().Add(2 * time.Hour).Truncate(time.Second),
+		Description: "Second event in transaction",
+		UserID:      userID,
+		OffsetTime:  0,
+	}
+
+	count := func() int {
+		found, err := repo.FindEvent(ctx, b.Exec, userID, nil, nil, nil, nil)
+		require.NoError(t, err)
+		return len(found)
+	}
+
+	var committed *domain.Event
+	t.Run("commit transaction", func(t *testing.T) {
+		err := b.TxManager.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+			var err error
+			if committed, err = repo.Create(ctx, tx, event1); err != nil {
+				return err
+			}
+			_, err = repo.Create(ctx, tx, event2)
+			return err
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, count())
+	})
+
+	t.Run("rollback transaction", func(t *testing.T) {
+		require.NotNil(t, committed)
+		errRollback := errors.New("rollback")
+
+		err := b.TxManager.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+			if _, err := repo.Create(ctx, tx, event1); err != nil {
+				return err
+			}
+			updated := *committed
+			updated.Title = "Updated in rolled back transaction"
+			if _, err := repo.Update(ctx, tx, committed.ID, updated); err != nil {
+				return err
+			}
+			return errRollback
+		})
+		require.ErrorIs(t, err, errRollback)
+
+		assert.Equal(t, 2, count())
+		retrieved, err := repo.GetByID(ctx, b.Exec, committed.ID)
+		require.NoError(t, err)
+		assert.Equal(t, event1.Title, retrieved.Title)
+	})
+
+	t.Run("rollback on panic", func(t *testing.T) {
+		require.NotNil(t, committed)
+
+		assert.Panics(t, func() {
+			_ = b.TxManager.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+				if err := repo.Delete(ctx, tx, committed.ID, 0); err != nil {
+					return err
+				}
+				panic("transaction panic")
+			})
+		})
+
+		assert.Equal(t, 2, count())
+		_, err := repo.GetByID(ctx, b.Exec, committed.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("nested transaction", func(t *testing.T) {
+		errNested := errors.New("nested")
+
+		var outer, nested *domain.Event
+		err := b.TxManager.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+			var err error
+			if outer, err = repo.Create(ctx, tx, event1); err != nil {
+				return err
+			}
+			err = b.TxManager.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+				var err error
+				if nested, err = repo.Create(ctx, tx, event2); err != nil {
+					return err
+				}
+				return errNested
+			})
+			require.ErrorIs(t, err, errNested)
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, count(), "only the nested scope is rolled back")
+		_, err = repo.GetByID(ctx, b.Exec, outer.ID)
+		require.NoError(t, err)
+		_, err = repo.GetByID(ctx, b.Exec, nested.ID)
+		require.ErrorIs(t, err, repositories.ErrEntityNotFound)
+	})
+}
+
+// testLockUser проверяет, что транзакция не получает блокировку событий пользователя,
+// пока ее держит другая транзакция.
+func testLockUser(t *testing.T, b Backend) {
+	t.Helper()
+	ctx := context.Background()
+	userID := "550e8400-e29b-41d4-a716-446655440098"
+
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	first := make(chan error, 1)
+	go func() {
+		first <- b.TxManager.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+			if err := b.Events.LockUser(ctx, tx, userID); err != nil {
+				return err
+			}
+			close(locked)
+			<-release
+			return nil
+		})
+	}()
+	select {
+	case <-locked:
+	case err := <-first:
+		t.Fatalf("first transaction failed: %v", err)
+	}
+
+	second := make(chan error, 1)
+	go func() {
+		second <- b.TxManager.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+			return b.Events.LockUser(ctx, tx, userID)
+		})
+	}()
+	blocked := true
+	select {
+	case err := <-second:
+		blocked = false
+		t.Errorf("second transaction was not blocked, error: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-first)
+	if blocked {
+		require.NoError(t, <-second)
+	}
+}
+
+// testConcurrentCreate проверяет, что транзакции одного пользователя, проверяющие пересечения
+// под LockUser, выполняются по очереди: из пересекающихся событий создается одно.
+func testConcurrentCreate(t *testing.T, b Backend) {
+	t.Helper()
+	ctx := context.Background()
+	userID := "550e8400-e29b-41d4-a716-446655440090"
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	const workers = 10
+
+	var wg sync.WaitGroup
+	ready := make(chan struct{})
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-ready
+			// Каждое событие пересекается со всеми остальными
+			offset := time.Duration(i) * time.Minute
+			event := domain.Event{Title: "Meeting", StartDate: start.Add(offset), EndDate: start.Add(offset + time.Hour), UserID: userID}
+			errs <- b.TxManager.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+				if err := b.Events.LockUser(ctx, tx, userID); err != nil {
+					return err
+				}
+				startTo, endFrom := event.EndDate.Add(-time.Nanosecond), event.StartDate.Add(time.Nanosecond)
+				found, err := b.Events.FindEvent(ctx, tx, userID, nil, &startTo, &endFrom, nil)
+				if err != nil {
+					return err
+				}
+				// Пауза между проверкой и записью, чтобы транзакции гарантированно пересеклись
+				time.Sleep(10 * time.Millisecond)
+				if len(found) > 0 {
+					return nil
+				}
+				_, err = b.Events.Create(ctx, tx, event)
+				return err
+			})
+		}(i)
+	}
+	close(ready)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	found, err := b.Events.FindEvent(ctx, b.Exec, userID, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, found, 1, "only one of the overlapping events is created")
+}
@@ -0,0 +1,1218 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFindEvent(t *testing.T, b Backend) {
+	t.Helper()
+	ctx := context.Background()
+	repo := b.Events
+
+	// Event 1: Jan 1, 2024 10:00 - 11:00
+	event1 := domain.Event{
+		Title:       "Event 1",
+		StartDate:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+		Description: "First event",
+		UserID:      "550e8400-e29b-41d4-a716-446655440001",
+		OffsetTime:  0,
+	}
+
+	// Event 2: Jan 2, 2024 14:00 - 15:00
+	event2 := domain.Event{
+		Title:       "Event 2",
+		StartDate:   time.Date(2024, 1, 2, 14, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC),
+		Description: "Second event",
+		UserID:      "550e8400-e29b-41d4-a716-446655440001",
+		OffsetTime:  0,
+	}
+
+	// Event 3: Jan 5, 2024 09:00 - 10:00
+	event3 := domain.Event{
+		Title:       "Event 3",
+		StartDate:   time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC),
+		Description: "Third event",
+		UserID:      "550e8400-e29b-41d4-a716-446655440002",
+		OffsetTime:  0,
+	}
+
+	// Event 4: Long event spanning multiple days (Jan 3 - Jan 6)
+	event4 := domain.Event{
+		Title:       "Long Event",
+		StartDate:   time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2024, 1, 6, 23, 59, 59, 0, time.UTC),
+		Description: "Multi-day event",
+		UserID:      "550e8400-e29b-41d4-a716-446655440001",
+		OffsetTime:  0,
+	}
+
+	// Create all events
+	_, err := repo.Create(ctx, b.Exec, event1)
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, b.Exec, event2)
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, b.Exec, event3)
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, b.Exec, event4)
+	require.NoError(t, err)
+
+	t.Run("find events in date range", func(t *testing.T) {
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 2, 23, 59, 59, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", &from, &to, nil, nil)
+		require.NoError(t, err)
+
+		// Should find Event 1 (Jan 1) and Event 2 (Jan 2) by start_date
+		assert.Len(t, events, 2)
+
+		titles := make(map[string]bool)
+		for _, e := range events {
+			titles[e.Title] = true
+		}
+		assert.True(t, titles["Event 1"])
+		assert.True(t, titles["Event 2"])
+	})
+
+	t.Run("find events by start_date range", func(t *testing.T) {
+		from := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 5, 23, 59, 59, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", &from, &to, nil, nil)
+		require.NoError(t, err)
+
+		// Should find Event 3 (Jan 5) and Long Event (Jan 3) by start_date
+		assert.Len(t, events, 2)
+
+		titles := make(map[string]bool)
+		for _, e := range events {
+			titles[e.Title] = true
+		}
+		assert.True(t, titles["Event 3"])
+		assert.True(t, titles["Long Event"])
+	})
+
+	t.Run("find no events outside range", func(t *testing.T) {
+		from := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 2, 28, 23, 59, 59, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", &from, &to, nil, nil)
+		require.NoError(t, err)
+		assert.Len(t, events, 0)
+	})
+
+	t.Run("find all events in wide range", func(t *testing.T) {
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", &from, &to, nil, nil)
+		require.NoError(t, err)
+		assert.Len(t, events, 4)
+	})
+
+	t.Run("events are ordered by start_date", func(t *testing.T) {
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", &from, &to, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, events, 4)
+
+		for i := 1; i < len(events); i++ {
+			assert.True(t, events[i-1].StartDate.Before(events[i].StartDate) ||
+				events[i-1].StartDate.Equal(events[i].StartDate),
+				"Events should be ordered by start_date")
+		}
+	})
+
+	t.Run("filter by userId", func(t *testing.T) {
+		// Search for user-1 events in January
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)
+		userID := "550e8400-e29b-41d4-a716-446655440001"
+
+		events, err := repo.FindEvent(ctx, b.Exec, userID, &from, &to, nil, nil)
+		require.NoError(t, err)
+
+		// Should find Event 1, Event 2, Long Event (all belong to user-1)
+		assert.Len(t, events, 3)
+		for _, e := range events {
+			assert.Equal(t, userID, e.UserID)
+		}
+	})
+
+	t.Run("filter by userId - user-2", func(t *testing.T) {
+		// Search for user-2 events in January
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)
+		userID := "550e8400-e29b-41d4-a716-446655440002"
+
+		events, err := repo.FindEvent(ctx, b.Exec, userID, &from, &to, nil, nil)
+		require.NoError(t, err)
+
+		// Should find only Event 3 (belongs to user-2)
+		assert.Len(t, events, 1)
+		assert.Equal(t, "Event 3", events[0].Title)
+		assert.Equal(t, userID, events[0].UserID)
+	})
+
+	t.Run("nil from and to parameters", func(t *testing.T) {
+		// Search without date range (all events)
+		events, err := repo.FindEvent(ctx, b.Exec, "", nil, nil, nil, nil)
+		require.NoError(t, err)
+
+		// Should find all 4 events
+		assert.Len(t, events, 4)
+	})
+
+	t.Run("filter by end_date range", func(t *testing.T) {
+		// Search for events ending between Jan 1 11:00 and Jan 2 15:00
+		endFrom := time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)
+		endTo := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", nil, nil, &endFrom, &endTo)
+		require.NoError(t, err)
+
+		// Should find Event 1 (ends Jan 1 11:00) and Event 2 (ends Jan 2 15:00)
+		assert.Len(t, events, 2)
+
+		titles := make(map[string]bool)
+		for _, e := range events {
+			titles[e.Title] = true
+		}
+		assert.True(t, titles["Event 1"])
+		assert.True(t, titles["Event 2"])
+	})
+
+	t.Run("filter by both start_date and end_date", func(t *testing.T) {
+		// Search for events: start_date >= Jan 1 AND end_date <= Jan 5 12:00
+		startFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		endTo := time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", &startFrom, nil, nil, &endTo)
+		require.NoError(t, err)
+
+		// Should find Event 1, Event 2, Event 3 (all end before Jan 5 12:00)
+		// Long Event ends Jan 6, so it should NOT be included
+		assert.Len(t, events, 3)
+
+		titles := make(map[string]bool)
+		for _, e := range events {
+			titles[e.Title] = true
+		}
+		assert.True(t, titles["Event 1"])
+		assert.True(t, titles["Event 2"])
+		assert.True(t, titles["Event 3"])
+		assert.False(t, titles["Long Event"])
+	})
+
+	t.Run("filter by start and end date range - find long event", func(t *testing.T) {
+		// Search for events: start_date <= Jan 3 AND end_date >= Jan 6
+		startTo := time.Date(2024, 1, 3, 23, 59, 59, 0, time.UTC)
+		endFrom := time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", nil, &startTo, &endFrom, nil)
+		require.NoError(t, err)
+
+		// Should find only Long Event (starts Jan 3, ends Jan 6)
+		assert.Len(t, events, 1)
+		assert.Equal(t, "Long Event", events[0].Title)
+	})
+
+	t.Run("filter events ending on specific day", func(t *testing.T) {
+		// Search for events ending on Jan 5
+		endFrom := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+		endTo := time.Date(2024, 1, 5, 23, 59, 59, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", nil, nil, &endFrom, &endTo)
+		require.NoError(t, err)
+
+		// Should find only Event 3 (ends Jan 5 10:00)
+		assert.Len(t, events, 1)
+		assert.Equal(t, "Event 3", events[0].Title)
+	})
+
+	t.Run("complex filter - start and end date ranges", func(t *testing.T) {
+		// Search for events: start_date between Jan 1-3 AND end_date between Jan 1-3
+		startFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		startTo := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+		endFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		endTo := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", &startFrom, &startTo, &endFrom, &endTo)
+		require.NoError(t, err)
+
+		// Should find Event 1 (Jan 1 10:00-11:00) and Event 2 (Jan 2 14:00-15:00)
+		// Event 3 starts Jan 5 - excluded
+		// Long Event ends Jan 6 - excluded
+		assert.Len(t, events, 2)
+
+		titles := make(map[string]bool)
+		for _, e := range events {
+			titles[e.Title] = true
+		}
+		assert.True(t, titles["Event 1"])
+		assert.True(t, titles["Event 2"])
+	})
+
+	t.Run("find events within date range - overlapping events", func(t *testing.T) {
+		// Ищем события, которые пересекаются с диапазоном Jan 2 - Jan 4
+		// Логика: start_date <= Jan 4 AND end_date >= Jan 2
+		startTo := time.Date(2024, 1, 4, 23, 59, 59, 0, time.UTC)
+		endFrom := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", nil, &startTo, &endFrom, nil)
+		require.NoError(t, err)
+
+		// Должны найти:
+		// - Event 2 (Jan 2 14:00-15:00) - полностью в диапазоне
+		// - Long Event (Jan 3 - Jan 6) - начинается в диапазоне
+		assert.GreaterOrEqual(t, len(events), 2)
+
+		titles := make(map[string]bool)
+		for _, e := range events {
+			titles[e.Title] = true
+		}
+		assert.True(t, titles["Event 2"])
+		assert.True(t, titles["Long Event"])
+	})
+
+	t.Run("find events fully contained in date range", func(t *testing.T) {
+		// Ищем события, которые полностью находятся внутри Jan 1 - Jan 3
+		// Логика: start_date >= Jan 1 AND end_date <= Jan 3
+		startFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		endTo := time.Date(2024, 1, 3, 23, 59, 59, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", &startFrom, nil, nil, &endTo)
+		require.NoError(t, err)
+
+		// Должны найти:
+		// - Event 1 (Jan 1 10:00-11:00) - полностью внутри
+		// - Event 2 (Jan 2 14:00-15:00) - полностью внутри
+		// Event 3 начинается Jan 5 - исключен
+		// Long Event заканчивается Jan 6 - исключен
+		assert.Len(t, events, 2)
+
+		titles := make(map[string]bool)
+		for _, e := range events {
+			titles[e.Title] = true
+		}
+		assert.True(t, titles["Event 1"])
+		assert.True(t, titles["Event 2"])
+	})
+
+	t.Run("find events that span across date range", func(t *testing.T) {
+		// Ищем события, которые охватывают диапазон Jan 4 - Jan 5
+		// Логика: start_date <= Jan 4 AND end_date >= Jan 5
+		startTo := time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)
+		endFrom := time.Date(2024, 1, 5, 23, 59, 59, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", nil, &startTo, &endFrom, nil)
+		require.NoError(t, err)
+
+		// Должны найти только Long Event (Jan 3 - Jan 6)
+		// Он начинается до Jan 4 и заканчивается после Jan 5
+		assert.Len(t, events, 1)
+		assert.Equal(t, "Long Event", events[0].Title)
+	})
+
+	t.Run("find events starting in range", func(t *testing.T) {
+		// Ищем события, которые начинаются в диапазоне Jan 1 - Jan 3
+		startFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		startTo := time.Date(2024, 1, 3, 23, 59, 59, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", &startFrom, &startTo, nil, nil)
+		require.NoError(t, err)
+
+		// Должны найти:
+		// - Event 1 (starts Jan 1)
+		// - Event 2 (starts Jan 2)
+		// - Long Event (starts Jan 3)
+		assert.Len(t, events, 3)
+
+		titles := make(map[string]bool)
+		for _, e := range events {
+			titles[e.Title] = true
+		}
+		assert.True(t, titles["Event 1"])
+		assert.True(t, titles["Event 2"])
+		assert.True(t, titles["Long Event"])
+	})
+
+	t.Run("find events ending in range", func(t *testing.T) {
+		// Ищем события, которые заканчиваются в диапазоне Jan 5 - Jan 7
+		endFrom := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+		endTo := time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", nil, nil, &endFrom, &endTo)
+		require.NoError(t, err)
+
+		// Должны найти:
+		// - Event 3 (ends Jan 5 10:00)
+		// - Long Event (ends Jan 6 23:59:59)
+		assert.Len(t, events, 2)
+
+		titles := make(map[string]bool)
+		for _, e := range events {
+			titles[e.Title] = true
+		}
+		assert.True(t, titles["Event 3"])
+		assert.True(t, titles["Long Event"])
+	})
+
+	t.Run("find events with exact time overlap", func(t *testing.T) {
+		// Ищем события, которые пересекаются с точным временем Jan 2 14:30
+		// Логика: start_date <= Jan 2 14:30 AND end_date >= Jan 2 14:30
+		targetTime := time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", nil, &targetTime, &targetTime, nil)
+		require.NoError(t, err)
+
+		// Должны найти Event 2 (Jan 2 14:00-15:00)
+		// 14:30 находится внутри этого события
+		assert.GreaterOrEqual(t, len(events), 1)
+
+		found := false
+		for _, e := range events {
+			if e.Title == "Event 2" {
+				found = true
+				break
+			}
+		}
+		assert.True(t, found, "Event 2 should be found as it overlaps with target time")
+	})
+}
+
+func testCRUD(t *testing.T, b Backend) {
+	t.Helper()
+	ctx := context.Background()
+	repo := b.Events
+
+	event := domain.Event{
+		Title:       "Test Event CRUD",
+		StartDate:   time.Now().Truncate(time.Second),
+		EndDate:     time.Now().Add(time.Hour).Truncate(time.Second),
+		Description: "Test Description",
+		UserID:      "550e8400-e29b-41d4-a716-446655440099",
+		OffsetTime:  0,
+	}
+
+	var createdEventID string
+
+	t.Run("create and retrieve", func(t *testing.T) {
+		createdEvent, err := repo.Create(ctx, b.Exec, event)
+		require.NoError(t, err)
+		require.NotNil(t, createdEvent)
+		assert.NotEmpty(t, createdEvent.ID, "ID should be auto-generated")
+
+		createdEventID = createdEvent.ID
+
+		retrieved, err := repo.GetByID(ctx, b.Exec, createdEvent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, createdEvent.ID, retrieved.ID)
+		assert.Equal(t, event.Title, retrieved.Title)
+	})
+
+	t.Run("update event", func(t *testing.T) {
+		event.Title = "Updated Title CRUD"
+		updatedEvent, err := repo.Update(ctx, b.Exec, createdEventID, event)
+		require.NoError(t, err)
+		require.NotNil(t, updatedEvent)
+
+		retrieved, err := repo.GetByID(ctx, b.Exec, createdEventID)
+		require.NoError(t, err)
+		assert.Equal(t, "Updated Title CRUD", retrieved.Title)
+	})
+
+	t.Run("delete event", func(t *testing.T) {
+		err := repo.Delete(ctx, b.Exec, createdEventID, 0)
+		require.NoError(t, err)
+
+		_, err = repo.GetByID(ctx, b.Exec, createdEventID)
+		require.Error(t, err)
+	})
+}
+
+func testBoundaryConditions(t *testing.T, newBackend NewBackend) {
+	t.Helper()
+	ctx := context.Background()
+
+	t.Run("event starting exactly at 'from' boundary", func(t *testing.T) {
+		b := newBackend(t)
+		repo := b.Events
+
+		event := domain.Event{
+			Title:       "Boundary Event Start",
+			StartDate:   time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+			EndDate:     time.Date(2024, 1, 10, 13, 0, 0, 0, time.UTC),
+			Description: "Starts at boundary",
+			UserID:      "550e8400-e29b-41d4-a716-446655440088",
+			OffsetTime:  0,
+		}
+
+		_, err := repo.Create(ctx, b.Exec, event)
+		require.NoError(t, err)
+
+		from := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 10, 14, 0, 0, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", &from, &to, nil, nil)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, len(events), 1)
+
+		found := false
+		for _, e := range events {
+			if e.Title == "Boundary Event Start" {
+				found = true
+				break
+			}
+		}
+		assert.True(t, found, "Event starting at 'from' boundary should be found")
+	})
+
+	t.Run("event ending exactly at 'to' boundary", func(t *testing.T) {
+		b := newBackend(t)
+		repo := b.Events
+
+		event := domain.Event{
+			Title:       "Boundary Event End",
+			StartDate:   time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+			EndDate:     time.Date(2024, 1, 10, 14, 0, 0, 0, time.UTC),
+			Description: "Ends at boundary",
+			UserID:      "550e8400-e29b-41d4-a716-446655440088",
+			OffsetTime:  0,
+		}
+
+		_, err := repo.Create(ctx, b.Exec, event)
+		require.NoError(t, err)
+
+		from := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 10, 14, 0, 0, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, "", &from, &to, nil, nil)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, len(events), 1)
+
+		found := false
+		for _, e := range events {
+			if e.Title == "Boundary Event End" {
+				found = true
+				break
+			}
+		}
+		assert.True(t, found, "Event ending at 'to' boundary should be found")
+	})
+}
+
+func testNotifications(t *testing.T, b Backend) {
+	t.Helper()
+	ctx := context.Background()
+	repo := b.Events
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	userID := "550e8400-e29b-41d4-a716-446655440077"
+
+	due, err := repo.Create(ctx, b.Exec, domain.Event{
+		Title:      "Due event",
+		StartDate:  now.Add(30 * time.Minute),
+		EndDate:    now.Add(time.Hour),
+		UserID:     userID,
+		OffsetTime: time.Hour,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, due.NextNotifyAt)
+	assert.True(t, now.Add(-30*time.Minute).Equal(*due.NextNotifyAt))
+
+	_, err = repo.Create(ctx, b.Exec, domain.Event{
+		Title:      "Future event",
+		StartDate:  now.Add(2 * time.Hour),
+		EndDate:    now.Add(3 * time.Hour),
+		UserID:     userID,
+		OffsetTime: time.Hour,
+	})
+	require.NoError(t, err)
+
+	t.Run("find events to notify", func(t *testing.T) {
+		events, err := repo.FindEventsToNotify(ctx, b.Exec, now, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, due.ID, events[0].ID)
+	})
+
+	t.Run("mark notified", func(t *testing.T) {
+		require.NoError(t, repo.MarkNotified(ctx, b.Exec, due.ID, &now, nil))
+
+		events, err := repo.FindEventsToNotify(ctx, b.Exec, now, 10)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+
+		stored, err := repo.GetByID(ctx, b.Exec, due.ID)
+		require.NoError(t, err)
+		require.NotNil(t, stored.NotifiedAt)
+		assert.True(t, now.Equal(*stored.NotifiedAt))
+		assert.Nil(t, stored.NextNotifyAt)
+	})
+
+	t.Run("update keeps notified mark while start and offset are unchanged", func(t *testing.T) {
+		event := *due
+		event.Title = "Renamed event"
+		_, err := repo.Update(ctx, b.Exec, due.ID, event)
+		require.NoError(t, err)
+
+		stored, err := repo.GetByID(ctx, b.Exec, due.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, stored.NotifiedAt)
+		assert.Nil(t, stored.NextNotifyAt)
+	})
+
+	t.Run("update resets notified mark when event is moved", func(t *testing.T) {
+		stored, err := repo.GetByID(ctx, b.Exec, due.ID)
+		require.NoError(t, err)
+		stored.StartDate = now.Add(40 * time.Minute)
+		_, err = repo.Update(ctx, b.Exec, due.ID, *stored)
+		require.NoError(t, err)
+
+		events, err := repo.FindEventsToNotify(ctx, b.Exec, now, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, due.ID, events[0].ID)
+	})
+
+	t.Run("update resets notified mark when offset changes", func(t *testing.T) {
+		require.NoError(t, repo.MarkNotified(ctx, b.Exec, due.ID, &now, nil))
+
+		stored, err := repo.GetByID(ctx, b.Exec, due.ID)
+		require.NoError(t, err)
+		stored.OffsetTime = 2 * time.Hour
+		updated, err := repo.Update(ctx, b.Exec, due.ID, *stored)
+		require.NoError(t, err)
+		require.NotNil(t, updated.NextNotifyAt)
+		assert.True(t, now.Add(-80*time.Minute).Equal(*updated.NextNotifyAt))
+
+		stored, err = repo.GetByID(ctx, b.Exec, due.ID)
+		require.NoError(t, err)
+		assert.Nil(t, stored.NotifiedAt)
+		require.NotNil(t, stored.NextNotifyAt)
+	})
+
+	t.Run("mark next occurrence of series", func(t *testing.T) {
+		series, err := repo.Create(ctx, b.Exec, domain.Event{
+			Title:     "Daily",
+			StartDate: now.Add(-time.Hour),
+			EndDate:   now.Add(-30 * time.Minute),
+			UserID:    "550e8400-e29b-41d4-a716-446655440078",
+			RRule:     "FREQ=DAILY",
+		})
+		require.NoError(t, err)
+
+		next := now.Add(23 * time.Hour)
+		require.NoError(t, repo.MarkNotified(ctx, b.Exec, series.ID, nil, &next))
+
+		stored, err := repo.GetByID(ctx, b.Exec, series.ID)
+		require.NoError(t, err)
+		assert.Nil(t, stored.NotifiedAt, "skipped occurrences are not notified")
+		require.NotNil(t, stored.NextNotifyAt)
+		assert.True(t, next.Equal(*stored.NextNotifyAt))
+
+		events, err := repo.FindEventsToNotify(ctx, b.Exec, next, 10)
+		require.NoError(t, err)
+		ids := make([]string, 0, len(events))
+		for _, e := range events {
+			ids = append(ids, e.ID)
+		}
+		assert.Contains(t, ids, series.ID)
+
+		// Изменение названия не сбрасывает следующее уведомление серии
+		stored.Title = "Daily standup"
+		updated, err := repo.Update(ctx, b.Exec, series.ID, *stored)
+		require.NoError(t, err)
+		require.NotNil(t, updated.NextNotifyAt)
+		assert.True(t, next.Equal(*updated.NextNotifyAt))
+	})
+
+	t.Run("mark notified unknown event", func(t *testing.T) {
+		err := repo.MarkNotified(ctx, b.Exec, missingID, &now, nil)
+		assert.ErrorIs(t, err, repositories.ErrEntityNotFound)
+	})
+}
+
+func testDeleteOlderThan(t *testing.T, b Backend) {
+	t.Helper()
+	ctx := context.Background()
+	repo := b.Events
+
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	userID := "550e8400-e29b-41d4-a716-446655440088"
+
+	// Создаются в обратном порядке, чтобы порядок вставки не совпадал с порядком окончания
+	old := make([]*domain.Event, 3)
+	for i := 2; i >= 0; i-- {
+		created, err := repo.Create(ctx, b.Exec, domain.Event{
+			Title:     "Old event",
+			StartDate: cutoff.AddDate(0, 0, -10+i),
+			EndDate:   cutoff.AddDate(0, 0, -9+i),
+			UserID:    userID,
+		})
+		require.NoError(t, err)
+		old[i] = created
+	}
+
+	recent, err := repo.Create(ctx, b.Exec, domain.Event{
+		Title:     "Recent event",
+		StartDate: cutoff.Add(-time.Hour),
+		EndDate:   cutoff.Add(time.Hour),
+		UserID:    userID,
+	})
+	require.NoError(t, err)
+
+	t.Run("deletes in batches", func(t *testing.T) {
+		deleted, err := repo.DeleteOlderThan(ctx, b.Exec, cutoff, 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		// Первой порцией удаляются события, закончившиеся раньше всех
+		_, err = repo.GetByID(ctx, b.Exec, old[2].ID)
+		require.NoError(t, err)
+
+		deleted, err = repo.DeleteOlderThan(ctx, b.Exec, cutoff, 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		deleted, err = repo.DeleteOlderThan(ctx, b.Exec, cutoff, 2)
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+	})
+
+	t.Run("keeps events that ended after cutoff", func(t *testing.T) {
+		events, err := repo.FindEvent(ctx, b.Exec, userID, nil, nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, recent.ID, events[0].ID)
+	})
+
+	t.Run("requires positive limit", func(t *testing.T) {
+		_, err := repo.DeleteOlderThan(ctx, b.Exec, cutoff.Add(2*time.Hour), 0)
+		require.ErrorIs(t, err, repositories.ErrInvalidLimit)
+		_, err = repo.PurgeTrash(ctx, b.Exec, cutoff.Add(2*time.Hour), 0)
+		require.ErrorIs(t, err, repositories.ErrInvalidLimit)
+
+		_, err = repo.GetByID(ctx, b.Exec, recent.ID)
+		require.NoError(t, err)
+	})
+}
+
+// testTrash проверяет корзину: удаленное событие скрыто от остальных методов,
+// его можно восстановить, а PurgeTrash удаляет его окончательно.
+func testTrash(t *testing.T, b Backend) {
+	t.Helper()
+	ctx := context.Background()
+	repo := b.Events
+
+	now := time.Now().UTC().Truncate(time.Second)
+	userID := "550e8400-e29b-41d4-a716-446655440066"
+	otherUserID := "550e8400-e29b-41d4-a716-446655440067"
+
+	create := func(title, userID string, start time.Time) *domain.Event {
+		created, err := repo.Create(ctx, b.Exec, domain.Event{
+			Title:      title,
+			StartDate:  start,
+			EndDate:    start.Add(time.Hour),
+			UserID:     userID,
+			OffsetTime: 2 * time.Hour,
+		})
+		require.NoError(t, err)
+		return created
+	}
+
+	first := create("First", userID, now.Add(time.Hour))
+	second := create("Second", userID, now.Add(3*time.Hour))
+	other := create("Other", otherUserID, now.Add(time.Hour))
+
+	t.Run("delete moves event to trash", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, b.Exec, first.ID, first.Version))
+
+		_, err := repo.GetByID(ctx, b.Exec, first.ID)
+		require.ErrorIs(t, err, repositories.ErrEntityNotFound)
+
+		found, err := repo.FindEvent(ctx, b.Exec, userID, nil, nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, second.ID, found[0].ID)
+
+		toNotify, err := repo.FindEventsToNotify(ctx, b.Exec, now, 0)
+		require.NoError(t, err)
+		for _, event := range toNotify {
+			assert.NotEqual(t, first.ID, event.ID)
+		}
+
+		trashed, err := repo.GetTrashedByID(ctx, b.Exec, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, first.Title, trashed.Title)
+		require.NotNil(t, trashed.DeletedAt)
+		assert.Equal(t, first.Version+1, trashed.Version)
+
+		_, err = repo.GetTrashedByID(ctx, b.Exec, second.ID)
+		require.ErrorIs(t, err, repositories.ErrEntityNotFound)
+	})
+
+	t.Run("trashed event cannot be changed", func(t *testing.T) {
+		event := *first
+		event.Version = 0
+		_, err := repo.Update(ctx, b.Exec, first.ID, event)
+		require.ErrorIs(t, err, repositories.ErrEntityNotFound)
+		require.ErrorIs(t, repo.Delete(ctx, b.Exec, first.ID, 0), repositories.ErrEntityNotFound)
+	})
+
+	t.Run("find trash", func(t *testing.T) {
+		// Время удаления различается, чтобы проверить порядок
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, repo.Delete(ctx, b.Exec, other.ID, 0))
+
+		trash, err := repo.FindTrash(ctx, b.Exec, userID)
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		assert.Equal(t, first.ID, trash[0].ID)
+
+		// Последние удаленные - первыми
+		trash, err = repo.FindTrash(ctx, b.Exec, "")
+		require.NoError(t, err)
+		require.Len(t, trash, 2)
+		assert.Equal(t, other.ID, trash[0].ID)
+		assert.Equal(t, first.ID, trash[1].ID)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		trashed, err := repo.GetTrashedByID(ctx, b.Exec, first.ID)
+		require.NoError(t, err)
+
+		restored, err := repo.Restore(ctx, b.Exec, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, restored.ID)
+		assert.Equal(t, first.Title, restored.Title)
+		assert.Nil(t, restored.DeletedAt)
+		assert.Equal(t, trashed.Version+1, restored.Version)
+
+		retrieved, err := repo.GetByID(ctx, b.Exec, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, restored.Version, retrieved.Version)
+
+		_, err = repo.Restore(ctx, b.Exec, first.ID)
+		require.ErrorIs(t, err, repositories.ErrEntityNotFound)
+		_, err = repo.Restore(ctx, b.Exec, missingID)
+		require.ErrorIs(t, err, repositories.ErrEntityNotFound)
+	})
+
+	t.Run("purge trash", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, b.Exec, second.ID, 0))
+
+		purged, err := repo.PurgeTrash(ctx, b.Exec, now.Add(-time.Hour), 10)
+		require.NoError(t, err)
+		assert.Zero(t, purged, "events trashed after cutoff are kept")
+
+		// Первой порцией удаляется событие, удаленное раньше всех
+		purged, err = repo.PurgeTrash(ctx, b.Exec, now.Add(time.Hour), 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+		_, err = repo.GetTrashedByID(ctx, b.Exec, other.ID)
+		require.ErrorIs(t, err, repositories.ErrEntityNotFound)
+
+		purged, err = repo.PurgeTrash(ctx, b.Exec, now.Add(time.Hour), 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		trash, err := repo.FindTrash(ctx, b.Exec, "")
+		require.NoError(t, err)
+		assert.Empty(t, trash)
+
+		// Событие не в корзине не удаляется
+		_, err = repo.GetByID(ctx, b.Exec, first.ID)
+		require.NoError(t, err)
+	})
+}
+
+func testRecurring(t *testing.T, b Backend) {
+	t.Helper()
+	ctx := context.Background()
+	repo := b.Events
+
+	userID := "550e8400-e29b-41d4-a716-446655440099"
+	dtstart := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	series, err := repo.Create(ctx, b.Exec, domain.Event{
+		Title:     "Standup",
+		StartDate: dtstart,
+		EndDate:   dtstart.Add(30 * time.Minute),
+		UserID:    userID,
+		RRule:     "FREQ=DAILY;COUNT=5",
+		ExDates:   domain.ExDates{dtstart.AddDate(0, 0, 1)},
+	})
+	require.NoError(t, err)
+
+	infinite, err := repo.Create(ctx, b.Exec, domain.Event{
+		Title:     "Weekly sync",
+		StartDate: dtstart.Add(4 * time.Hour),
+		EndDate:   dtstart.Add(5 * time.Hour),
+		UserID:    userID,
+		RRule:     "FREQ=WEEKLY",
+	})
+	require.NoError(t, err)
+
+	t.Run("stores rule and exdates", func(t *testing.T) {
+		found, err := repo.GetByID(ctx, b.Exec, series.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "FREQ=DAILY;COUNT=5", found.RRule)
+		require.Len(t, found.ExDates, 1)
+		assert.True(t, found.ExDates[0].Equal(dtstart.AddDate(0, 0, 1)))
+		require.NotNil(t, found.SeriesEnd)
+		assert.True(t, found.SeriesEnd.Equal(dtstart.AddDate(0, 0, 4).Add(30*time.Minute)))
+	})
+
+	t.Run("expands series in window", func(t *testing.T) {
+		from := dtstart
+		to := dtstart.AddDate(0, 0, 7).Add(-time.Second)
+
+		events, err := repo.FindEvent(ctx, b.Exec, userID, &from, &to, nil, nil)
+		require.NoError(t, err)
+		// 4 вхождения ежедневной серии и одно еженедельной
+		require.Len(t, events, 5)
+		for i := 1; i < len(events); i++ {
+			assert.False(t, events[i].StartDate.Before(events[i-1].StartDate))
+		}
+	})
+
+	t.Run("finished series is not found later", func(t *testing.T) {
+		from := dtstart.AddDate(0, 1, 0)
+		to := from.AddDate(0, 0, 7).Add(-time.Second)
+
+		events, err := repo.FindEvent(ctx, b.Exec, userID, &from, &to, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, infinite.ID, events[0].ID)
+	})
+
+	t.Run("retention keeps infinite series", func(t *testing.T) {
+		deleted, err := repo.DeleteOlderThan(ctx, b.Exec, dtstart.AddDate(1, 0, 0), 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		_, err = repo.GetByID(ctx, b.Exec, infinite.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("expands series in event time zone", func(t *testing.T) {
+		loc, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		// 29 марта в Берлине переход на летнее время
+		start := time.Date(2026, 3, 23, 10, 0, 0, 0, loc)
+		berlinUserID := "550e8400-e29b-41d4-a716-446655440091"
+		created, err := repo.Create(ctx, b.Exec, domain.Event{
+			Title: "Standup", StartDate: start, EndDate: start.Add(15 * time.Minute), UserID: berlinUserID, RRule: "FREQ=DAILY",
+		})
+		require.NoError(t, err)
+
+		stored, err := repo.GetByID(ctx, b.Exec, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Europe/Berlin", stored.TimeZone)
+
+		from, to := start, start.AddDate(0, 0, 9)
+		events, err := repo.FindEvent(ctx, b.Exec, berlinUserID, &from, &to, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, events, 10)
+		for _, occurrence := range events {
+			local := occurrence.StartDate.In(loc)
+			assert.Equal(t, 10, local.Hour(), local)
+		}
+	})
+}
+
+func testFindEventPage(t *testing.T, b Backend) {
+	t.Helper()
+	ctx := context.Background()
+	repo := b.Events
+
+	userID := "550e8400-e29b-41d4-a716-446655440098"
+	dtstart := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	_, err := repo.Create(ctx, b.Exec, domain.Event{
+		Title:     "Daily",
+		StartDate: dtstart,
+		EndDate:   dtstart.Add(30 * time.Minute),
+		UserID:    userID,
+		RRule:     "FREQ=DAILY;COUNT=3",
+	})
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		start := dtstart.AddDate(0, 0, i).Add(2 * time.Hour)
+		_, err = repo.Create(ctx, b.Exec, domain.Event{
+			Title:     "Single",
+			StartDate: start,
+			EndDate:   start.Add(time.Hour),
+			UserID:    userID,
+		})
+		require.NoError(t, err)
+	}
+
+	for _, descending := range []bool{false, true} {
+		var starts []time.Time
+		page := domain.Page{Limit: 4, Descending: descending}
+		for {
+			found, next, err := repo.FindEventPage(ctx, b.Exec, userID, nil, nil, nil, nil, page)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(found), 4)
+			for _, e := range found {
+				starts = append(starts, e.StartDate)
+			}
+			if next == nil {
+				break
+			}
+			page.After = next
+		}
+
+		require.Len(t, starts, 6)
+		for i := 1; i < len(starts); i++ {
+			if descending {
+				assert.True(t, starts[i].Before(starts[i-1]))
+			} else {
+				assert.True(t, starts[i].After(starts[i-1]))
+			}
+		}
+	}
+}
+
+// testFindEventPageManySeries проверяет, что страницы совпадают с полной выборкой, когда серий
+// больше, чем событий на странице, и серии выбираются порциями.
+func testFindEventPageManySeries(t *testing.T, b Backend) {
+	t.Helper()
+	ctx := context.Background()
+	repo := b.Events
+
+	userID := "550e8400-e29b-41d4-a716-446655440093"
+	dtstart := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	rules := []string{"FREQ=DAILY;COUNT=2", "FREQ=WEEKLY;COUNT=3", "FREQ=DAILY;INTERVAL=3;COUNT=2", "FREQ=DAILY"}
+	for i := 0; i < 7; i++ {
+		start := dtstart.AddDate(0, 0, 6-i).Add(time.Duration(i) * time.Minute)
+		_, err := repo.Create(ctx, b.Exec, domain.Event{
+			Title:     "Series",
+			StartDate: start,
+			EndDate:   start.Add(30 * time.Minute),
+			UserID:    userID,
+			RRule:     rules[i%len(rules)],
+		})
+		require.NoError(t, err)
+	}
+	single := dtstart.AddDate(0, 0, 2).Add(2 * time.Hour)
+	_, err := repo.Create(ctx, b.Exec, domain.Event{
+		Title:     "Single",
+		StartDate: single,
+		EndDate:   single.Add(time.Hour),
+		UserID:    userID,
+	})
+	require.NoError(t, err)
+
+	from, to := dtstart, dtstart.AddDate(0, 0, 21)
+	for _, descending := range []bool{false, true} {
+		all, _, err := repo.FindEventPage(ctx, b.Exec, userID, &from, &to, nil, nil, domain.Page{Descending: descending})
+		require.NoError(t, err)
+
+		var paged []domain.Cursor
+		page := domain.Page{Limit: 2, Descending: descending}
+		for {
+			found, next, err := repo.FindEventPage(ctx, b.Exec, userID, &from, &to, nil, nil, page)
+			require.NoError(t, err)
+			for _, e := range found {
+				paged = append(paged, domain.CursorOf(e))
+			}
+			if next == nil {
+				break
+			}
+			page.After = next
+		}
+
+		expected := make([]domain.Cursor, 0, len(all))
+		for _, e := range all {
+			expected = append(expected, domain.CursorOf(e))
+		}
+		require.Len(t, paged, len(expected))
+		for i := range expected {
+			assert.True(t, expected[i].StartDate.Equal(paged[i].StartDate), "descending=%v, index %d", descending, i)
+			assert.Equal(t, expected[i].ID, paged[i].ID, "descending=%v, index %d", descending, i)
+		}
+	}
+}
+
+// testFindEventPageWindow проверяет, что серия разворачивается только в окне страницы:
+// окно поиска на 30 лет целиком превышает ограничение числа вхождений, а страница - нет.
+func testFindEventPageWindow(t *testing.T, b Backend) {
+	t.Helper()
+	ctx := context.Background()
+	repo := b.Events
+
+	userID := "550e8400-e29b-41d4-a716-446655440094"
+	dtstart := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	series, err := repo.Create(ctx, b.Exec, domain.Event{
+		Title:     "Daily",
+		StartDate: dtstart,
+		EndDate:   dtstart.Add(30 * time.Minute),
+		UserID:    userID,
+		RRule:     "FREQ=DAILY",
+	})
+	require.NoError(t, err)
+	var singles []string
+	for i := 0; i < 6; i++ {
+		start := dtstart.AddDate(0, 0, i).Add(2 * time.Hour)
+		single, err := repo.Create(ctx, b.Exec, domain.Event{
+			Title:     "Single",
+			StartDate: start,
+			EndDate:   start.Add(time.Hour),
+			UserID:    userID,
+		})
+		require.NoError(t, err)
+		singles = append(singles, single.ID)
+	}
+
+	from, to := dtstart, dtstart.AddDate(30, 0, 0)
+	_, err = repo.FindEvent(ctx, b.Exec, userID, &from, &to, nil, nil)
+	require.ErrorIs(t, err, domain.ErrTooManyOccurrences)
+
+	page := domain.Page{Limit: 3}
+	found, next, err := repo.FindEventPage(ctx, b.Exec, userID, &from, &to, nil, nil, page)
+	require.NoError(t, err)
+	require.Len(t, found, 3)
+	assert.Equal(t, []string{series.ID, singles[0], series.ID}, []string{found[0].ID, found[1].ID, found[2].ID})
+	require.NotNil(t, next)
+
+	page.After = next
+	found, next, err = repo.FindEventPage(ctx, b.Exec, userID, &from, &to, nil, nil, page)
+	require.NoError(t, err)
+	require.Len(t, found, 3)
+	assert.Equal(t, []string{singles[1], series.ID, singles[2]}, []string{found[0].ID, found[1].ID, found[2].ID})
+	assert.True(t, dtstart.AddDate(0, 0, 2).Equal(found[1].StartDate))
+	require.NotNil(t, next)
+}
+
+func testRecurringWeekly(t *testing.T, b Backend) {
+	t.Helper()
+	ctx := context.Background()
+	repo := b.Events
+	userID := "550e8400-e29b-41d4-a716-446655440097"
+
+	// Понедельник, 1 января 2024: серия по понедельникам и средам
+	dtstart := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	series, err := repo.Create(ctx, b.Exec, domain.Event{
+		Title:     "Standup",
+		StartDate: dtstart,
+		EndDate:   dtstart.Add(30 * time.Minute),
+		UserID:    userID,
+		RRule:     "FREQ=WEEKLY;BYDAY=MO,WE",
+		ExDates:   domain.ExDates{time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC)},
+	})
+	require.NoError(t, err)
+
+	single, err := repo.Create(ctx, b.Exec, domain.Event{
+		Title:     "Review",
+		StartDate: time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 1, 2, 13, 0, 0, 0, time.UTC),
+		UserID:    userID,
+	})
+	require.NoError(t, err)
+
+	t.Run("expands series in window", func(t *testing.T) {
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 10, 23, 59, 59, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, userID, &from, &to, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, events, 4)
+
+		assert.Equal(t, series.ID, events[0].ID)
+		assert.True(t, dtstart.Equal(events[0].StartDate))
+		assert.Equal(t, single.ID, events[1].ID)
+		// 3 января исключено
+		assert.True(t, time.Date(2024, 1, 8, 10, 0, 0, 0, time.UTC).Equal(events[2].StartDate))
+		assert.True(t, time.Date(2024, 1, 10, 10, 30, 0, 0, time.UTC).Equal(events[3].EndDate))
+	})
+
+	t.Run("window far in the future", func(t *testing.T) {
+		from := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2025, 6, 2, 23, 59, 59, 0, time.UTC)
+
+		events, err := repo.FindEvent(ctx, b.Exec, userID, &from, &to, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "Standup", events[0].Title)
+	})
+
+	t.Run("keeps wall clock in event time zone", func(t *testing.T) {
+		loc, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		// 31 марта 2024 в Берлине переход на летнее время
+		start := time.Date(2024, 3, 25, 9, 0, 0, 0, loc)
+		zoned, err := repo.Create(ctx, b.Exec, domain.Event{
+			Title:     "Planning",
+			StartDate: start,
+			EndDate:   start.Add(30 * time.Minute),
+			UserID:    "550e8400-e29b-41d4-a716-446655440095",
+			RRule:     "FREQ=WEEKLY;BYDAY=MO",
+		})
+		require.NoError(t, err)
+
+		stored, err := repo.GetByID(ctx, b.Exec, zoned.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Europe/Berlin", stored.TimeZone)
+
+		from := time.Date(2024, 4, 1, 0, 0, 0, 0, loc)
+		to := time.Date(2024, 4, 1, 23, 59, 59, 0, loc)
+		events, err := repo.FindEvent(ctx, b.Exec, zoned.UserID, &from, &to, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, zoned.ID, events[0].ID)
+		assert.True(t, time.Date(2024, 4, 1, 9, 0, 0, 0, loc).Equal(events[0].StartDate), events[0].StartDate)
+	})
+}
+
+// testOrdering проверяет порядок (start_date, id): события с одинаковым началом
+// упорядочены по идентификатору во всех хранилищах.
+func testOrdering(t *testing.T, b Backend) {
+	t.Helper()
+	ctx := context.Background()
+	repo := b.Events
+	userID := "550e8400-e29b-41d4-a716-446655440096"
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		_, err := repo.Create(ctx, b.Exec, domain.Event{
+			Title:     "Same start",
+			StartDate: start.Add(time.Duration(i%2) * time.Hour),
+			EndDate:   start.Add(3 * time.Hour),
+			UserID:    userID,
+		})
+		require.NoError(t, err)
+	}
+
+	events, err := repo.FindEvent(ctx, b.Exec, userID, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, events, 5)
+	for i := 1; i < len(events); i++ {
+		assert.True(t, domain.CursorOf(events[i-1]).Less(domain.CursorOf(events[i])),
+			"events must be ordered by (start_date, id)")
+	}
+
+	descending, _, err := repo.FindEventPage(ctx, b.Exec, userID, nil, nil, nil, nil, domain.Page{Descending: true})
+	require.NoError(t, err)
+	require.Len(t, descending, 5)
+	for i := range events {
+		assert.Equal(t, events[i].ID, descending[len(descending)-1-i].ID)
+	}
+}
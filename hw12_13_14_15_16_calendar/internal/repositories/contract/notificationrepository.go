@@ -0,0 +1,58 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testNotificationRepository(t *testing.T, b Backend) {
+	t.Helper()
+	ctx := context.Background()
+	repo := b.Notifications
+
+	userID := "550e8400-e29b-41d4-a716-446655440066"
+	notifyAt := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	notification := domain.Notification{
+		ID:       "123e4567-e89b-12d3-a456-426614174001",
+		Title:    "Standup",
+		Date:     notifyAt.Add(time.Hour),
+		UserID:   userID,
+		NotifyAt: notifyAt,
+	}
+
+	t.Run("save notification", func(t *testing.T) {
+		require.NoError(t, repo.Save(ctx, b.Exec, notification))
+	})
+
+	t.Run("save duplicate notification", func(t *testing.T) {
+		err := repo.Save(ctx, b.Exec, notification)
+		assert.ErrorIs(t, err, repositories.ErrEntityAlreadyExists)
+	})
+
+	t.Run("save same event with another notify time", func(t *testing.T) {
+		moved := notification
+		moved.NotifyAt = notifyAt.Add(-time.Hour)
+		require.NoError(t, repo.Save(ctx, b.Exec, moved))
+	})
+
+	t.Run("find by user ID", func(t *testing.T) {
+		notifications, err := repo.FindByUserID(ctx, b.Exec, userID)
+		require.NoError(t, err)
+		require.Len(t, notifications, 2)
+		assert.True(t, notifyAt.Add(-time.Hour).Equal(notifications[0].NotifyAt))
+		assert.Equal(t, notification.ID, notifications[0].ID)
+		assert.Equal(t, "Standup", notifications[0].Title)
+	})
+
+	t.Run("find by unknown user ID", func(t *testing.T) {
+		notifications, err := repo.FindByUserID(ctx, b.Exec, "550e8400-e29b-41d4-a716-446655440000")
+		require.NoError(t, err)
+		assert.Empty(t, notifications)
+	})
+}
@@ -10,11 +10,25 @@ import (
 var (
 	ErrEntityAlreadyExists = errors.New("entity already exists")
 	ErrEntityNotFound      = errors.New("entity not found")
+	ErrVersionMismatch     = errors.New("entity version mismatch")
 )
 
+// CrudRepository - базовые операции хранилища. Поведение всех реализаций проверяется
+// общим набором тестов из пакета contract.
 type CrudRepository[T any] interface {
+	// Create сохраняет сущность; идентификатор, время создания и первую версию задает хранилище.
 	Create(ctx context.Context, exec sqlx.ExtContext, entity T) (*T, error)
+	// Update заменяет сущность с идентификатором id и возвращает ее с этим идентификатором;
+	// время создания сохраняется, версия увеличивается. Если версия в entity не 0 и не совпадает
+	// с сохраненной, возвращается ErrVersionMismatch.
 	Update(ctx context.Context, exec sqlx.ExtContext, id string, entity T) (*T, error)
-	Delete(ctx context.Context, exec sqlx.ExtContext, id string) error
+	// Delete переносит сущность в корзину, где ее не видят остальные методы; version проверяется, как в Update.
+	Delete(ctx context.Context, exec sqlx.ExtContext, id string, version int64) error
+	// GetByID возвращает сущность, не перенесенную в корзину.
 	GetByID(ctx context.Context, exec sqlx.ExtContext, id string) (*T, error)
 }
+
+// Connector - хранилище, доступность которого можно проверить.
+type Connector interface {
+	Connect(ctx context.Context) error
+}
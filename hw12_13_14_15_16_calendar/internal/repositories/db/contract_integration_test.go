@@ -0,0 +1,27 @@
+//go:build integration
+// +build integration
+
+package db
+
+import (
+	"testing"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories/contract"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositories_Contract_WithTestcontainers(t *testing.T) {
+	_, db := SetupPostgresContainer(t)
+
+	contract.Run(t, func(t *testing.T) contract.Backend {
+		t.Helper()
+		cleanupTestData(t, db)
+
+		repo, err := NewEventRepository(NewEventCrudRepository(db, Postgres))
+		require.NoError(t, err)
+		return contract.Backend{
+			Events: repo, Notifications: NewNotificationRepository(db, Postgres), Exec: db, TxManager: database.NewTxManager(db),
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories/contract"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestDB создает файл базы SQLite во временном каталоге теста и применяет миграции.
+func openTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+	db, err := database.NewSQLiteConnection(database.DefaultConnectionConfig(filepath.Join(t.TempDir(), "calendar.db")))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	require.NoError(t, database.Migrate(context.Background(), db.DB, database.DialectSQLite, database.MigrateUp, io.Discard))
+	return db
+}
+
+func TestRepositories_Contract_SQLite(t *testing.T) {
+	contract.Run(t, func(t *testing.T) contract.Backend {
+		t.Helper()
+		db := openTestDB(t)
+
+		repo, err := NewEventRepository(NewEventCrudRepository(db, SQLite))
+		require.NoError(t, err)
+		return contract.Backend{Events: repo, Notifications: NewNotificationRepository(db, SQLite), Exec: db, TxManager: database.NewTxManager(db)}
+	})
+}
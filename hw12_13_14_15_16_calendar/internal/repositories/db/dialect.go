@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+)
+
+// Dialect - различия SQL-хранилищ, с которыми работают репозитории пакета.
+type Dialect struct {
+	// Name - имя хранилища в логах.
+	Name string
+	// LockUserQuery - рекомендательная блокировка событий пользователя, пусто - LockUser ничего не делает.
+	LockUserQuery string
+	// SkipLocked - окончание выборки, пропускающей строки, заблокированные другими транзакциями.
+	SkipLocked string
+	// TextTime - время хранится текстом в формате timestampLayout, а не TIMESTAMPTZ.
+	TextTime bool
+}
+
+var (
+	Postgres = Dialect{
+		Name: "db",
+		// Рекомендательная блокировка снимается при завершении транзакции
+		LockUserQuery: "SELECT pg_advisory_xact_lock(hashtextextended(:user_id, 0))",
+		SkipLocked:    " FOR UPDATE SKIP LOCKED",
+	}
+	// SQLite без блокировок строк: пишущие транзакции (_txlock=immediate) и так идут по очереди.
+	SQLite = Dialect{
+		Name:     "sqlite",
+		TextTime: true,
+	}
+)
+
+// DialectOf возвращает диалект хранилища database.type: "db" (PostgreSQL) или "sqlite".
+func DialectOf(dbType string) (Dialect, error) {
+	switch dbType {
+	case Postgres.Name:
+		return Postgres, nil
+	case SQLite.Name:
+		return SQLite, nil
+	default:
+		return Dialect{}, fmt.Errorf("unknown database type: %s", dbType)
+	}
+}
+
+// contextLogger возвращает логгер запроса из ctx с именем хранилища.
+func (d Dialect) contextLogger(ctx context.Context) logger.Logger {
+	return logger.FromContext(ctx).With(logger.ComponentKey, d.Name)
+}
+
+// time возвращает значение параметра запроса для момента t.
+func (d Dialect) time(t time.Time) any {
+	if d.TextTime {
+		return timestamp(t)
+	}
+	return t
+}
+
+// timePtr возвращает значение параметра запроса для t, nil - NULL.
+func (d Dialect) timePtr(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return d.time(*t)
+}
@@ -5,43 +5,70 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	events "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
 const (
+	eventColumns = `id, title, description, start_date, end_date, user_id, offset_time, notified_at, created_at, updated_at,
+		       rrule, exdates, series_end, version, deleted_at, time_zone, next_notify_at`
+
 	CreateQuery = `
-        INSERT INTO events (title, description, start_date, end_date, user_id, offset_time)
-        VALUES (:title, :description, :start_date, :end_date, :user_id, :offset_time)
-        RETURNING id, created_at, updated_at
-    `
+		INSERT INTO events (id, title, description, start_date, end_date, user_id, offset_time, next_notify_at,
+		                    rrule, exdates, series_end, time_zone, created_at, updated_at, version)
+		VALUES (:id, :title, :description, :start_date, :end_date, :user_id, :offset_time, :next_notify_at,
+		        :rrule, :exdates, :series_end, :time_zone, :created_at, :updated_at, :version)
+	`
+	// Условие :version IN (0, version) проверяет версию, если она передана, 0 - без проверки.
+	// Следующее уведомление пересчитывается, только если изменилось расписание вхождений.
 	UpdateQuery = `
-		UPDATE events 
-		SET title = :title, 
-		    description = :description, 
-		    start_date = :start_date, 
-		    end_date = :end_date, 
-		    user_id = :user_id, 
+		UPDATE events
+		SET title = :title,
+		    description = :description,
+		    start_date = :start_date,
+		    end_date = :end_date,
+		    user_id = :user_id,
 		    offset_time = :offset_time,
-		    updated_at = CURRENT_TIMESTAMP
-		WHERE id = :id
+		    rrule = :rrule,
+		    exdates = :exdates,
+		    series_end = :series_end,
+		    time_zone = :time_zone,
+		    notified_at = CASE
+		        WHEN start_date = :start_date AND offset_time = :offset_time THEN notified_at
+		    END,
+		    next_notify_at = CASE
+		        WHEN start_date = :start_date AND offset_time = :offset_time
+		             AND rrule = :rrule AND time_zone = :time_zone THEN next_notify_at
+		        ELSE :next_notify_at
+		    END,
+		    updated_at = :updated_at,
+		    version = version + 1
+		WHERE id = :id AND deleted_at IS NULL AND :version IN (0, version)
+		RETURNING created_at, updated_at, version, next_notify_at
 	`
-	DeleteQuery  = "DELETE FROM events WHERE id = :id"
-	GetByIDQuery = `
-		SELECT id, title, description, start_date, end_date, user_id, offset_time, created_at, updated_at 
-		FROM events 
-		WHERE id = :id
+	// Удаленное событие остается в корзине до очистки (PurgeTrash).
+	DeleteQuery = `
+		UPDATE events
+		SET deleted_at = :deleted_at,
+		    updated_at = :deleted_at,
+		    version = version + 1
+		WHERE id = :id AND deleted_at IS NULL AND :version IN (0, version)
 	`
+	GetByIDQuery = "SELECT " + eventColumns + " FROM events WHERE id = :id AND deleted_at IS NULL"
+	ExistsQuery  = "SELECT EXISTS (SELECT 1 FROM events WHERE id = :id AND deleted_at IS NULL)"
 )
 
 type EventCrudRepository struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	dialect Dialect
 }
 
-func NewEventCrudRepository(db *sqlx.DB) *EventCrudRepository {
-	return &EventCrudRepository{db: db}
+func NewEventCrudRepository(db *sqlx.DB, dialect Dialect) *EventCrudRepository {
+	return &EventCrudRepository{db: db, dialect: dialect}
 }
 
 func (r *EventCrudRepository) GetDB() *sqlx.DB {
@@ -56,57 +83,88 @@ func (r *EventCrudRepository) Close(_ context.Context) error {
 	return r.db.Close()
 }
 
+// now возвращает текущее время с точностью TIMESTAMPTZ, чтобы сохраненное время не отличалось
+// от возвращенного.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// Create сохраняет событие. Идентификатор и время создания задаются приложением.
 func (r *EventCrudRepository) Create(ctx context.Context, exec sqlx.ExtContext, event events.Event) (*events.Event, error) {
-	var createdEvent struct {
-		ID        string `db:"id"`
-		CreatedAt string `db:"created_at"`
-		UpdatedAt string `db:"updated_at"`
+	event.TimeZone = event.ZoneName()
+	seriesEnd, err := event.LastOccurrenceEnd()
+	if err != nil {
+		return nil, err
 	}
-
-	query, args, err := sqlx.Named(CreateQuery, event)
+	event.ID = uuid.New().String()
+	event.CreatedAt = now()
+	event.UpdatedAt = event.CreatedAt
+	event.Version = 1
+	event.NotifiedAt = nil
+	event.SeriesEnd = seriesEnd
+	notifyAt := event.NotifyAt()
+	event.NextNotifyAt = &notifyAt
+
+	query, args, err := sqlx.Named(CreateQuery, r.dialect.eventParams(event))
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare named query: %w", err)
 	}
 
 	query = r.db.Rebind(query)
 
-	err = sqlx.GetContext(ctx, exec, &createdEvent, query, args...)
-	if err != nil {
+	if _, err := exec.ExecContext(ctx, query, args...); err != nil {
 		return nil, fmt.Errorf("failed to create event: %w", err)
 	}
-	event.ID = createdEvent.ID
 	return &event, nil
 }
 
 func (r *EventCrudRepository) Update(ctx context.Context, exec sqlx.ExtContext, id string, event events.Event) (*events.Event, error) {
 	event.ID = id
+	event.UpdatedAt = now()
+
+	event.TimeZone = event.ZoneName()
+	seriesEnd, err := event.LastOccurrenceEnd()
+	if err != nil {
+		return nil, err
+	}
+	event.SeriesEnd = seriesEnd
+	notifyAt := event.NotifyAt()
+	event.NextNotifyAt = &notifyAt
 
-	query, args, err := sqlx.Named(UpdateQuery, event)
+	query, args, err := sqlx.Named(UpdateQuery, r.dialect.eventParams(event))
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare named query: %w", err)
 	}
 
 	query = r.db.Rebind(query)
 
-	result, err := exec.ExecContext(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to update event: %w", err)
+	var updated struct {
+		CreatedAt    timestamp  `db:"created_at"`
+		UpdatedAt    timestamp  `db:"updated_at"`
+		Version      int64      `db:"version"`
+		NextNotifyAt *timestamp `db:"next_notify_at"`
 	}
-
-	rowsAffected, err := result.RowsAffected()
+	err = sqlx.GetContext(ctx, exec, &updated, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return nil, repositories.ErrEntityNotFound
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, r.missingOrStale(ctx, exec, id)
+		}
+		return nil, fmt.Errorf("failed to update event: %w", err)
 	}
+	event.CreatedAt = time.Time(updated.CreatedAt)
+	event.UpdatedAt = time.Time(updated.UpdatedAt)
+	event.Version = updated.Version
+	event.NextNotifyAt = timePtr(updated.NextNotifyAt)
 
 	return &event, nil
 }
 
-func (r *EventCrudRepository) Delete(ctx context.Context, exec sqlx.ExtContext, id string) error {
-	query, args, err := sqlx.Named(DeleteQuery, map[string]any{"id": id})
+func (r *EventCrudRepository) Delete(ctx context.Context, exec sqlx.ExtContext, id string, version int64) error {
+	query, args, err := sqlx.Named(DeleteQuery, map[string]any{
+		"id":         id,
+		"version":    version,
+		"deleted_at": r.dialect.time(now()),
+	})
 	if err != nil {
 		return fmt.Errorf("failed to prepare named query: %w", err)
 	}
@@ -115,7 +173,7 @@ func (r *EventCrudRepository) Delete(ctx context.Context, exec sqlx.ExtContext,
 
 	result, err := exec.ExecContext(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to delete event: %w", err)
+		return fmt.Errorf("failed to move event to trash: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -124,14 +182,32 @@ func (r *EventCrudRepository) Delete(ctx context.Context, exec sqlx.ExtContext,
 	}
 
 	if rowsAffected == 0 {
-		return repositories.ErrEntityNotFound
+		return r.missingOrStale(ctx, exec, id)
 	}
 
 	return nil
 }
 
+// missingOrStale объясняет, почему запрос с проверкой версии не затронул событие:
+// ErrEntityNotFound, если события нет, иначе ErrVersionMismatch.
+func (r *EventCrudRepository) missingOrStale(ctx context.Context, exec sqlx.ExtContext, id string) error {
+	query, args, err := sqlx.Named(ExistsQuery, map[string]any{"id": id})
+	if err != nil {
+		return fmt.Errorf("failed to prepare named query: %w", err)
+	}
+
+	var exists bool
+	if err := sqlx.GetContext(ctx, exec, &exists, r.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to check event existence: %w", err)
+	}
+	if !exists {
+		return repositories.ErrEntityNotFound
+	}
+	return repositories.ErrVersionMismatch
+}
+
 func (r *EventCrudRepository) GetByID(ctx context.Context, exec sqlx.ExtContext, id string) (*events.Event, error) {
-	var event events.Event
+	var row eventRow
 
 	query, args, err := sqlx.Named(GetByIDQuery, map[string]any{"id": id})
 	if err != nil {
@@ -140,7 +216,7 @@ func (r *EventCrudRepository) GetByID(ctx context.Context, exec sqlx.ExtContext,
 
 	query = r.db.Rebind(query)
 
-	err = sqlx.GetContext(ctx, exec, &event, query, args...)
+	err = sqlx.GetContext(ctx, exec, &row, query, args...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, repositories.ErrEntityNotFound
@@ -148,5 +224,6 @@ func (r *EventCrudRepository) GetByID(ctx context.Context, exec sqlx.ExtContext,
 		return nil, fmt.Errorf("failed to get event by id: %w", err)
 	}
 
+	event := row.toEvent()
 	return &event, nil
 }
@@ -2,28 +2,73 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	events "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
 	"github.com/jmoiron/sqlx"
 )
 
 const (
-	FindEventsQueryBase = `
-		SELECT id, title, description, start_date, end_date, user_id, offset_time, created_at, updated_at 
-		FROM events
+	FindEventsQueryBase     = "SELECT " + eventColumns + " FROM events"
+	FindEventsToNotifyQuery = FindEventsQueryBase + `
+		WHERE next_notify_at <= :now
+		  AND deleted_at IS NULL
+		ORDER BY next_notify_at
+	`
+	FindTrashQuery      = FindEventsQueryBase + " WHERE deleted_at IS NOT NULL"
+	GetTrashedByIDQuery = FindEventsQueryBase + " WHERE id = :id AND deleted_at IS NOT NULL"
+	RestoreQuery        = `
+		UPDATE events
+		SET deleted_at = NULL,
+		    updated_at = :updated_at,
+		    version = version + 1
+		WHERE id = :id AND deleted_at IS NOT NULL
+		RETURNING ` + eventColumns + `
+	`
+	// Удаление идет порциями, чтобы не держать блокировки на большом числе строк.
+	// Пакетные удаления дополняются Dialect.SkipLocked.
+	PurgeTrashBatchQuery = `
+		DELETE FROM events
+		WHERE id IN (
+			SELECT id FROM events
+			WHERE deleted_at < :cutoff
+			ORDER BY deleted_at
+			LIMIT :limit%s
+		)
+	`
+	MarkNotifiedQuery = `
+		UPDATE events
+		SET notified_at = COALESCE(:notified_at, notified_at),
+		    next_notify_at = :next_notify_at
+		WHERE id = :id
+	`
+	DeleteOlderThanBatchQuery = `
+		DELETE FROM events
+		WHERE id IN (
+			SELECT id FROM events
+			WHERE series_end < :cutoff
+			ORDER BY series_end
+			LIMIT :limit%s
+		)
 	`
 )
 
 type EventRepository struct {
 	crudRepo *EventCrudRepository
+	dialect  Dialect
 }
 
 func NewEventRepository(crudRepo *EventCrudRepository) (*EventRepository, error) {
 	return &EventRepository{
 		crudRepo: crudRepo,
+		dialect:  crudRepo.dialect,
 	}, nil
 }
 
@@ -32,15 +77,29 @@ func (r *EventRepository) GetDB() *sqlx.DB {
 }
 
 func (r *EventRepository) Create(ctx context.Context, exec sqlx.ExtContext, event events.Event) (*events.Event, error) {
-	return r.crudRepo.Create(ctx, exec, event)
+	created, err := r.crudRepo.Create(ctx, exec, event)
+	if err != nil {
+		return nil, err
+	}
+	r.contextLogger(ctx).Debug("event created", "event_id", created.ID)
+	return created, nil
 }
 
 func (r *EventRepository) Update(ctx context.Context, exec sqlx.ExtContext, id string, event events.Event) (*events.Event, error) {
-	return r.crudRepo.Update(ctx, exec, id, event)
+	updated, err := r.crudRepo.Update(ctx, exec, id, event)
+	if err != nil {
+		return nil, err
+	}
+	r.contextLogger(ctx).Debug("event updated", "event_id", id)
+	return updated, nil
 }
 
-func (r *EventRepository) Delete(ctx context.Context, exec sqlx.ExtContext, id string) error {
-	return r.crudRepo.Delete(ctx, exec, id)
+func (r *EventRepository) Delete(ctx context.Context, exec sqlx.ExtContext, id string, version int64) error {
+	if err := r.crudRepo.Delete(ctx, exec, id, version); err != nil {
+		return err
+	}
+	r.contextLogger(ctx).Debug("event moved to trash", "event_id", id)
+	return nil
 }
 
 func (r *EventRepository) GetByID(ctx context.Context, exec sqlx.ExtContext, id string) (*events.Event, error) {
@@ -48,9 +107,24 @@ func (r *EventRepository) GetByID(ctx context.Context, exec sqlx.ExtContext, id
 }
 
 func (r *EventRepository) FindEvent(ctx context.Context, exec sqlx.ExtContext, userID string, startFrom, startTo, endFrom, endTo *time.Time) ([]events.Event, error) {
-	var eventsList []events.Event
+	result, _, err := r.FindEventPage(ctx, exec, userID, startFrom, startTo, endFrom, endTo, events.Page{})
+	return result, err
+}
 
-	whereClauses := []string{"1=1"}
+// FindEventPage ищет разовые события по точным условиям, а серии - по грубым границам
+// (первое вхождение и series_end), после чего разворачивает их во вхождения.
+// Страница разовых событий выбирается в БД по ключу (start_date, id), затем
+// объединяется с вхождениями серий. Серии выбираются и разворачиваются только в окне
+// страницы: после курсора и не дальше последнего выбранного разового события, порциями
+// по Limit+1, пока следующие серии еще могут попасть на страницу.
+func (r *EventRepository) FindEventPage(
+	ctx context.Context, exec sqlx.ExtContext, userID string, startFrom, startTo, endFrom, endTo *time.Time, page events.Page,
+) ([]events.Event, *events.Cursor, error) {
+	start := time.Now()
+
+	whereClauses := []string{"deleted_at IS NULL"}
+	singleClauses := []string{"rrule = ''"}
+	seriesClauses := []string{"rrule <> ''"}
 	params := make(map[string]any)
 
 	if userID != "" {
@@ -59,40 +133,283 @@ func (r *EventRepository) FindEvent(ctx context.Context, exec sqlx.ExtContext, u
 	}
 
 	if startFrom != nil {
-		whereClauses = append(whereClauses, "start_date >= :startFrom")
-		params["startFrom"] = *startFrom
+		singleClauses = append(singleClauses, "start_date >= :startFrom")
+		seriesClauses = append(seriesClauses, "(series_end IS NULL OR series_end >= :startFrom)")
+		params["startFrom"] = r.dialect.time(*startFrom)
 	}
 
 	if startTo != nil {
-		whereClauses = append(whereClauses, "start_date <= :startTo")
-		params["startTo"] = *startTo
+		singleClauses = append(singleClauses, "start_date <= :startTo")
+		seriesClauses = append(seriesClauses, "start_date <= :startTo")
+		params["startTo"] = r.dialect.time(*startTo)
 	}
 
 	if endFrom != nil {
-		whereClauses = append(whereClauses, "end_date >= :endFrom")
-		params["endFrom"] = *endFrom
+		singleClauses = append(singleClauses, "end_date >= :endFrom")
+		seriesClauses = append(seriesClauses, "(series_end IS NULL OR series_end >= :endFrom)")
+		params["endFrom"] = r.dialect.time(*endFrom)
 	}
 
 	if endTo != nil {
-		whereClauses = append(whereClauses, "end_date <= :endTo")
-		params["endTo"] = *endTo
+		singleClauses = append(singleClauses, "end_date <= :endTo")
+		seriesClauses = append(seriesClauses, "end_date <= :endTo")
+		params["endTo"] = r.dialect.time(*endTo)
+	}
+
+	direction := "ASC"
+	if page.Descending {
+		direction = "DESC"
 	}
 
-	query := fmt.Sprintf("%s WHERE %s ORDER BY start_date",
+	if page.After != nil {
+		if page.Descending {
+			singleClauses = append(singleClauses, "(start_date, id) < (:afterStart, :afterID)")
+		} else {
+			singleClauses = append(singleClauses, "(start_date, id) > (:afterStart, :afterID)")
+		}
+		params["afterStart"] = r.dialect.time(page.After.StartDate)
+		params["afterID"] = page.After.ID
+	}
+
+	singleQuery := fmt.Sprintf("%s WHERE %s AND %s ORDER BY start_date %s, id %s",
 		FindEventsQueryBase,
-		strings.Join(whereClauses, " AND "))
+		strings.Join(whereClauses, " AND "),
+		strings.Join(singleClauses, " AND "),
+		direction, direction)
+	if page.Limit > 0 {
+		// Лишняя строка показывает, есть ли следующая страница
+		singleQuery += " LIMIT " + strconv.Itoa(page.Limit+1)
+	}
+
+	var singleRows []eventRow
+	if err := r.selectNamed(ctx, exec, &singleRows, singleQuery, params); err != nil {
+		return nil, nil, err
+	}
+	singles := toEvents(singleRows)
+
+	// Серии выбираются только в окне, которое еще может попасть на страницу
+	result := singles
+	window := page.SeriesWindow(singles)
+	if !window.Empty() {
+		if window.From != nil {
+			seriesClauses = append(seriesClauses, "(series_end IS NULL OR series_end >= :windowFrom)")
+			params["windowFrom"] = r.dialect.time(*window.From)
+		}
+		if window.To != nil {
+			seriesClauses = append(seriesClauses, "start_date <= :windowTo")
+			params["windowTo"] = r.dialect.time(*window.To)
+		}
+
+		seriesQuery := fmt.Sprintf("%s WHERE %s AND %s ORDER BY %s",
+			FindEventsQueryBase,
+			strings.Join(whereClauses, " AND "),
+			strings.Join(seriesClauses, " AND "),
+			page.SeriesOrder())
+
+		// Серии выбираются порциями, пока оставшиеся еще могут попасть на страницу
+		batch := page.Limit + 1
+		for offset := 0; ; offset += batch {
+			query := seriesQuery
+			if page.Limit > 0 {
+				query += fmt.Sprintf(" LIMIT %d OFFSET %d", batch, offset)
+			}
+
+			var seriesRows []eventRow
+			if err := r.selectNamed(ctx, exec, &seriesRows, query, params); err != nil {
+				return nil, nil, err
+			}
+			series := toEvents(seriesRows)
+
+			for _, event := range series {
+				occurrences, err := event.OccurrencesWithin(startFrom, startTo, endFrom, endTo, window)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to expand recurring event %s: %w", event.ID, err)
+				}
+				result = append(result, occurrences...)
+			}
+			result = page.Head(result)
+
+			if page.Limit <= 0 || len(series) < batch || page.Settled(result, series[len(series)-1]) {
+				break
+			}
+		}
+	}
 
+	result, next := events.Paginate(result, page)
+	r.contextLogger(ctx).Debug("events found", "count", len(result), "duration", time.Since(start))
+	return result, next, nil
+}
+
+// bindNamed подставляет именованные параметры в запрос с плейсхолдерами драйвера.
+func (r *EventRepository) bindNamed(query string, params map[string]any) (string, []any, error) {
 	namedQuery, args, err := sqlx.Named(query, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to prepare named query: %w", err)
+		return "", nil, fmt.Errorf("failed to prepare named query: %w", err)
+	}
+	return r.crudRepo.GetDB().Rebind(namedQuery), args, nil
+}
+
+// selectNamed выполняет запрос с именованными параметрами.
+func (r *EventRepository) selectNamed(ctx context.Context, exec sqlx.ExtContext, dest any, query string, params map[string]any) error {
+	namedQuery, args, err := r.bindNamed(query, params)
+	if err != nil {
+		return err
+	}
+
+	if err := sqlx.SelectContext(ctx, exec, dest, namedQuery, args...); err != nil {
+		return fmt.Errorf("failed to find events: %w", err)
+	}
+	return nil
+}
+
+// execNamed выполняет изменяющий запрос с именованными параметрами и возвращает количество
+// затронутых строк.
+func (r *EventRepository) execNamed(ctx context.Context, exec sqlx.ExtContext, query string, params map[string]any) (int64, error) {
+	namedQuery, args, err := r.bindNamed(query, params)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := exec.ExecContext(ctx, namedQuery, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected, nil
+}
+
+func (r *EventRepository) FindEventsToNotify(ctx context.Context, exec sqlx.ExtContext, now time.Time, limit int) ([]events.Event, error) {
+	var rows []eventRow
+
+	query := FindEventsToNotifyQuery
+	if limit > 0 {
+		query += " LIMIT " + strconv.Itoa(limit)
+	}
+	// Параллельно запущенные планировщики не должны брать одни и те же события
+	query += r.dialect.SkipLocked
+
+	if err := r.selectNamed(ctx, exec, &rows, query, map[string]any{"now": r.dialect.time(now)}); err != nil {
+		return nil, err
+	}
+
+	return toEvents(rows), nil
+}
+
+func (r *EventRepository) MarkNotified(ctx context.Context, exec sqlx.ExtContext, id string, notifiedAt, next *time.Time) error {
+	params := map[string]any{"id": id, "notified_at": r.dialect.timePtr(notifiedAt), "next_notify_at": r.dialect.timePtr(next)}
+	rowsAffected, err := r.execNamed(ctx, exec, MarkNotifiedQuery, params)
+	if err != nil {
+		return fmt.Errorf("failed to mark event as notified: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return repositories.ErrEntityNotFound
 	}
 
-	namedQuery = r.crudRepo.GetDB().Rebind(namedQuery)
+	return nil
+}
+
+func (r *EventRepository) LockUser(ctx context.Context, exec sqlx.ExtContext, userID string) error {
+	if r.dialect.LockUserQuery == "" {
+		return nil
+	}
+
+	if _, err := r.execNamed(ctx, exec, r.dialect.LockUserQuery, map[string]any{"user_id": userID}); err != nil {
+		return fmt.Errorf("failed to lock user events: %w", err)
+	}
+	return nil
+}
 
-	err = sqlx.SelectContext(ctx, exec, &eventsList, namedQuery, args...)
+// contextLogger возвращает логгер запроса из ctx с именем хранилища.
+func (r *EventRepository) contextLogger(ctx context.Context) logger.Logger {
+	return r.dialect.contextLogger(ctx)
+}
+
+func (r *EventRepository) DeleteOlderThan(ctx context.Context, exec sqlx.ExtContext, cutoff time.Time, limit int) (int64, error) {
+	if limit <= 0 {
+		return 0, repositories.ErrInvalidLimit
+	}
+
+	query := fmt.Sprintf(DeleteOlderThanBatchQuery, r.dialect.SkipLocked)
+	params := map[string]any{"cutoff": r.dialect.time(cutoff), "limit": limit}
+
+	deleted, err := r.execNamed(ctx, exec, query, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find events: %w", err)
+		return 0, fmt.Errorf("failed to delete old events: %w", err)
+	}
+	return deleted, nil
+}
+
+func (r *EventRepository) FindTrash(ctx context.Context, exec sqlx.ExtContext, userID string) ([]events.Event, error) {
+	var rows []eventRow
+
+	query := FindTrashQuery
+	params := make(map[string]any)
+	if userID != "" {
+		query += " AND user_id = :userID"
+		params["userID"] = userID
 	}
+	query += " ORDER BY deleted_at DESC, id"
 
-	return eventsList, nil
+	if err := r.selectNamed(ctx, exec, &rows, query, params); err != nil {
+		return nil, err
+	}
+
+	return toEvents(rows), nil
+}
+
+func (r *EventRepository) GetTrashedByID(ctx context.Context, exec sqlx.ExtContext, id string) (*events.Event, error) {
+	return r.getOne(ctx, exec, GetTrashedByIDQuery, map[string]any{"id": id}, "failed to get trashed event")
+}
+
+func (r *EventRepository) Restore(ctx context.Context, exec sqlx.ExtContext, id string) (*events.Event, error) {
+	params := map[string]any{"id": id, "updated_at": r.dialect.time(now())}
+	restored, err := r.getOne(ctx, exec, RestoreQuery, params, "failed to restore event")
+	if err != nil {
+		return nil, err
+	}
+	r.contextLogger(ctx).Debug("event restored", "event_id", id)
+	return restored, nil
+}
+
+// getOne выполняет запрос, возвращающий не более одного события.
+func (r *EventRepository) getOne(
+	ctx context.Context, exec sqlx.ExtContext, query string, params map[string]any, failure string,
+) (*events.Event, error) {
+	var row eventRow
+
+	namedQuery, args, err := r.bindNamed(query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sqlx.GetContext(ctx, exec, &row, namedQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repositories.ErrEntityNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", failure, err)
+	}
+
+	event := row.toEvent()
+	return &event, nil
+}
+
+func (r *EventRepository) PurgeTrash(ctx context.Context, exec sqlx.ExtContext, cutoff time.Time, limit int) (int64, error) {
+	if limit <= 0 {
+		return 0, repositories.ErrInvalidLimit
+	}
+
+	query := fmt.Sprintf(PurgeTrashBatchQuery, r.dialect.SkipLocked)
+	params := map[string]any{"cutoff": r.dialect.time(cutoff), "limit": limit}
+
+	purged, err := r.execNamed(ctx, exec, query, params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+	return purged, nil
 }
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventRepository_TimeZones(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	repo, err := NewEventRepository(NewEventCrudRepository(db, SQLite))
+	require.NoError(t, err)
+
+	moscow := time.FixedZone("MSK", 3*60*60)
+	start := time.Date(2024, 1, 1, 13, 0, 0, 0, moscow)
+	created, err := repo.Create(ctx, db, domain.Event{
+		Title:     "Meeting",
+		StartDate: start,
+		EndDate:   start.Add(time.Hour),
+		UserID:    "550e8400-e29b-41d4-a716-446655440001",
+	})
+	require.NoError(t, err)
+
+	// Границы в разных часовых поясах: 12:30 MSK = 09:30 UTC, событие начинается в 10:00 UTC.
+	from := time.Date(2024, 1, 1, 12, 30, 0, 0, moscow)
+	to := time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)
+	found, err := repo.FindEvent(ctx, db, "", &from, &to, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, created.ID, found[0].ID)
+	assert.True(t, start.Equal(found[0].StartDate))
+
+	from = time.Date(2024, 1, 1, 10, 0, 0, 1, time.UTC)
+	found, err = repo.FindEvent(ctx, db, "", &from, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, found, "nanoseconds take part in comparison")
+}
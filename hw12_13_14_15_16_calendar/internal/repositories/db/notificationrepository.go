@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	SaveNotificationQuery = `
+		INSERT INTO notifications (event_id, title, event_date, user_id, notify_at)
+		VALUES (:event_id, :title, :event_date, :user_id, :notify_at)
+		ON CONFLICT (event_id, notify_at) DO NOTHING
+	`
+	FindNotificationsByUserIDQuery = `
+		SELECT event_id, title, event_date, user_id, notify_at
+		FROM notifications
+		WHERE user_id = :user_id
+		ORDER BY notify_at
+	`
+)
+
+type NotificationRepository struct {
+	db      *sqlx.DB
+	dialect Dialect
+}
+
+func NewNotificationRepository(db *sqlx.DB, dialect Dialect) *NotificationRepository {
+	return &NotificationRepository{db: db, dialect: dialect}
+}
+
+func (r *NotificationRepository) GetDB() *sqlx.DB {
+	return r.db
+}
+
+func (r *NotificationRepository) Save(ctx context.Context, exec sqlx.ExtContext, notification domain.Notification) error {
+	query, args, err := sqlx.Named(SaveNotificationQuery, r.dialect.notificationParams(notification))
+	if err != nil {
+		return fmt.Errorf("failed to prepare named query: %w", err)
+	}
+
+	query = r.db.Rebind(query)
+
+	result, err := exec.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to save notification: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return repositories.ErrEntityAlreadyExists
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) FindByUserID(ctx context.Context, exec sqlx.ExtContext, userID string) ([]domain.Notification, error) {
+	var rows []notificationRow
+
+	query, args, err := sqlx.Named(FindNotificationsByUserIDQuery, map[string]any{"user_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare named query: %w", err)
+	}
+
+	query = r.db.Rebind(query)
+
+	err = sqlx.SelectContext(ctx, exec, &rows, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notifications: %w", err)
+	}
+
+	notifications := make([]domain.Notification, 0, len(rows))
+	for _, row := range rows {
+		notifications = append(notifications, row.toNotification())
+	}
+	return notifications, nil
+}
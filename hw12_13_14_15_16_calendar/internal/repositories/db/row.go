@@ -0,0 +1,152 @@
+package db
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	events "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+)
+
+// timestampLayout - формат хранения времени в SQLite: UTC фиксированной ширины, поэтому
+// строки сравниваются и сортируются так же, как моменты времени.
+const timestampLayout = "2006-01-02T15:04:05.000000000Z"
+
+// timestamp - время в колонке: TIMESTAMPTZ PostgreSQL или TEXT SQLite.
+type timestamp time.Time
+
+func (t timestamp) Value() (driver.Value, error) {
+	return time.Time(t).UTC().Format(timestampLayout), nil
+}
+
+func (t *timestamp) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case time.Time:
+		*t = timestamp(v)
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("unsupported timestamp type %T", src)
+	}
+
+	parsed, err := time.Parse(timestampLayout, s)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q: %w", s, err)
+	}
+	*t = timestamp(parsed)
+	return nil
+}
+
+func timePtr(ts *timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := time.Time(*ts)
+	return &t
+}
+
+// eventRow - строка таблицы events.
+type eventRow struct {
+	ID           string         `db:"id"`
+	CreatedAt    timestamp      `db:"created_at"`
+	UpdatedAt    timestamp      `db:"updated_at"`
+	Title        string         `db:"title"`
+	StartDate    timestamp      `db:"start_date"`
+	EndDate      timestamp      `db:"end_date"`
+	Description  string         `db:"description"`
+	UserID       string         `db:"user_id"`
+	OffsetTime   time.Duration  `db:"offset_time"`
+	NotifiedAt   *timestamp     `db:"notified_at"`
+	NextNotifyAt *timestamp     `db:"next_notify_at"`
+	RRule        string         `db:"rrule"`
+	ExDates      events.ExDates `db:"exdates"`
+	SeriesEnd    *timestamp     `db:"series_end"`
+	Version      int64          `db:"version"`
+	DeletedAt    *timestamp     `db:"deleted_at"`
+	TimeZone     string         `db:"time_zone"`
+}
+
+// eventParams возвращает параметры запроса для сохранения event.
+func (d Dialect) eventParams(event events.Event) map[string]any {
+	return map[string]any{
+		"id":             event.ID,
+		"created_at":     d.time(event.CreatedAt),
+		"updated_at":     d.time(event.UpdatedAt),
+		"title":          event.Title,
+		"start_date":     d.time(event.StartDate),
+		"end_date":       d.time(event.EndDate),
+		"description":    event.Description,
+		"user_id":        event.UserID,
+		"offset_time":    event.OffsetTime,
+		"next_notify_at": d.timePtr(event.NextNotifyAt),
+		"rrule":          event.RRule,
+		"exdates":        event.ExDates,
+		"series_end":     d.timePtr(event.SeriesEnd),
+		"version":        event.Version,
+		"time_zone":      event.TimeZone,
+	}
+}
+
+func (r eventRow) toEvent() events.Event {
+	return events.Event{
+		ID:           r.ID,
+		CreatedAt:    time.Time(r.CreatedAt),
+		UpdatedAt:    time.Time(r.UpdatedAt),
+		Title:        r.Title,
+		StartDate:    time.Time(r.StartDate),
+		EndDate:      time.Time(r.EndDate),
+		Description:  r.Description,
+		UserID:       r.UserID,
+		OffsetTime:   r.OffsetTime,
+		NotifiedAt:   timePtr(r.NotifiedAt),
+		NextNotifyAt: timePtr(r.NextNotifyAt),
+		RRule:        r.RRule,
+		ExDates:      r.ExDates,
+		SeriesEnd:    timePtr(r.SeriesEnd),
+		Version:      r.Version,
+		DeletedAt:    timePtr(r.DeletedAt),
+		TimeZone:     r.TimeZone,
+	}
+}
+
+func toEvents(rows []eventRow) []events.Event {
+	result := make([]events.Event, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, row.toEvent())
+	}
+	return result
+}
+
+// notificationRow - строка таблицы notifications.
+type notificationRow struct {
+	EventID  string    `db:"event_id"`
+	Title    string    `db:"title"`
+	Date     timestamp `db:"event_date"`
+	UserID   string    `db:"user_id"`
+	NotifyAt timestamp `db:"notify_at"`
+}
+
+// notificationParams возвращает параметры запроса для сохранения notification.
+func (d Dialect) notificationParams(notification events.Notification) map[string]any {
+	return map[string]any{
+		"event_id":   notification.ID,
+		"title":      notification.Title,
+		"event_date": d.time(notification.Date),
+		"user_id":    notification.UserID,
+		"notify_at":  d.time(notification.NotifyAt),
+	}
+}
+
+func (r notificationRow) toNotification() events.Notification {
+	return events.Notification{
+		ID:       r.EventID,
+		Title:    r.Title,
+		Date:     time.Time(r.Date),
+		UserID:   r.UserID,
+		NotifyAt: time.Time(r.NotifyAt),
+	}
+}
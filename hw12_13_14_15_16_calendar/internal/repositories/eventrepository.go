@@ -2,14 +2,47 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	events "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
 	"github.com/jmoiron/sqlx"
 )
 
+// ErrInvalidLimit - пакетное удаление вызвано без положительного размера порции.
+var ErrInvalidLimit = errors.New("delete limit must be positive")
+
 type EventRepository interface {
+	// FindEvent возвращает все подходящие события, упорядоченные по (start_date, id).
 	FindEvent(ctx context.Context, exec sqlx.ExtContext, userID string, startFrom, startTo, endFrom, endTo *time.Time) ([]events.Event, error)
+	// FindEventPage возвращает страницу подходящих событий и курсор следующей страницы,
+	// nil - если страница последняя.
+	FindEventPage(
+		ctx context.Context, exec sqlx.ExtContext, userID string, startFrom, startTo, endFrom, endTo *time.Time, page events.Page,
+	) ([]events.Event, *events.Cursor, error)
+	// FindEventsToNotify возвращает события, у которых наступил NextNotifyAt, в порядке NextNotifyAt.
+	// Какое вхождение серии уведомлять и не закончилось ли оно, решает планировщик.
+	FindEventsToNotify(ctx context.Context, exec sqlx.ExtContext, now time.Time, limit int) ([]events.Event, error)
+	// MarkNotified записывает время отправки уведомления (nil - уведомление не отправлялось,
+	// вхождения пропущены) и момент следующего уведомления, nil - уведомлять больше не о чем.
+	// Update сбрасывает NextNotifyAt на первое вхождение, если изменилось расписание вхождений.
+	MarkNotified(ctx context.Context, exec sqlx.ExtContext, id string, notifiedAt, next *time.Time) error
+	// DeleteOlderThan удаляет события, закончившиеся раньше cutoff, не более limit штук за вызов,
+	// начиная с закончившихся раньше всех. Возвращает количество удаленных событий.
+	// Удаление без ограничения не поддерживается: limit <= 0 - ErrInvalidLimit.
+	DeleteOlderThan(ctx context.Context, exec sqlx.ExtContext, cutoff time.Time, limit int) (int64, error)
+	// FindTrash возвращает события в корзине, последние удаленные - первыми; серии не разворачиваются.
+	FindTrash(ctx context.Context, exec sqlx.ExtContext, userID string) ([]events.Event, error)
+	// GetTrashedByID возвращает событие из корзины, ErrEntityNotFound - если его там нет.
+	GetTrashedByID(ctx context.Context, exec sqlx.ExtContext, id string) (*events.Event, error)
+	// Restore возвращает событие из корзины, ErrEntityNotFound - если его там нет.
+	Restore(ctx context.Context, exec sqlx.ExtContext, id string) (*events.Event, error)
+	// PurgeTrash удаляет не более limit событий, перенесенных в корзину раньше cutoff, начиная с самых старых;
+	// limit <= 0 - ErrInvalidLimit.
+	PurgeTrash(ctx context.Context, exec sqlx.ExtContext, cutoff time.Time, limit int) (int64, error)
+	// LockUser выстраивает в очередь транзакции одного пользователя до конца exec. Блокировка
+	// добровольная: атомарность проверки пересечений обеспечивает изоляция транзакции.
+	LockUser(ctx context.Context, exec sqlx.ExtContext, userID string) error
 }
 
 type CompositeEventRepository interface {
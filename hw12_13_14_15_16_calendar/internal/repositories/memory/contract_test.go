@@ -0,0 +1,18 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories/contract"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositories_Contract(t *testing.T) {
+	contract.Run(t, func(t *testing.T) contract.Backend {
+		t.Helper()
+		crudRepo := NewEventCrudRepository()
+		repo, err := NewEventRepository(crudRepo)
+		require.NoError(t, err)
+		return contract.Backend{Events: repo, Notifications: NewNotificationRepository(), TxManager: NewTxManager(crudRepo)}
+	})
+}
@@ -3,6 +3,7 @@ package memory
 import (
 	"context"
 	"sync"
+	"time"
 
 	events "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
@@ -13,6 +14,10 @@ import (
 type EventCrudRepository struct {
 	events map[string]events.Event
 	mu     sync.RWMutex
+	// persistence - журнал на диске, nil - события хранятся только в памяти.
+	persistence *persistence
+	// tx - открытая транзакция TxManager, изменяется под mu.
+	tx *transaction
 }
 
 func NewEventCrudRepository() *EventCrudRepository {
@@ -26,46 +31,107 @@ func (r *EventCrudRepository) GetDB() *sqlx.DB {
 	return nil // Memory storage doesn't have DB
 }
 
-func (r *EventCrudRepository) Create(_ context.Context, _ sqlx.ExtContext, event events.Event) (*events.Event, error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+// Connect всегда успешен: хранилище в памяти доступно, пока работает процесс.
+func (r *EventCrudRepository) Connect(_ context.Context) error {
+	return nil
+}
+
+// Close сохраняет события на диск, если хранилище создано через NewPersistentEventCrudRepository.
+func (r *EventCrudRepository) Close(_ context.Context) error {
+	if r.persistence == nil {
+		return nil
+	}
+	return r.closePersistence()
+}
+
+func (r *EventCrudRepository) Create(ctx context.Context, _ sqlx.ExtContext, event events.Event) (*events.Event, error) {
+	defer r.lock(ctx)()
 	var err error
 	newID, err := uuid.NewUUID()
 	if err != nil {
 		return nil, err
 	}
 	event.ID = newID.String()
+	event.CreatedAt = time.Now().UTC()
+	event.UpdatedAt = event.CreatedAt
+	event.Version = 1
+	event.NotifiedAt = nil
+	notifyAt := event.NotifyAt()
+	event.NextNotifyAt = &notifyAt
+	event.DeletedAt = nil
+	event.TimeZone = event.ZoneName()
+	if event.SeriesEnd, err = event.LastOccurrenceEnd(); err != nil {
+		return nil, err
+	}
 	if _, ok := r.events[event.ID]; ok {
 		return nil, repositories.ErrEntityAlreadyExists
 	}
-	r.events[event.ID] = event
+	if err = r.put(event.ID, event); err != nil {
+		return nil, err
+	}
 	return &event, nil
 }
 
-func (r *EventCrudRepository) Update(_ context.Context, _ sqlx.ExtContext, id string, event events.Event) (*events.Event, error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	if _, ok := r.events[id]; !ok {
+func (r *EventCrudRepository) Update(ctx context.Context, _ sqlx.ExtContext, id string, event events.Event) (*events.Event, error) {
+	defer r.lock(ctx)()
+	stored, ok := r.events[id]
+	if !ok || stored.DeletedAt != nil {
 		return nil, repositories.ErrEntityNotFound
 	}
-	r.events[id] = event
+	if event.Version != 0 && event.Version != stored.Version {
+		return nil, repositories.ErrVersionMismatch
+	}
+	event.TimeZone = event.ZoneName()
+	seriesEnd, err := event.LastOccurrenceEnd()
+	if err != nil {
+		return nil, err
+	}
+	event.ID = id
+	event.SeriesEnd = seriesEnd
+	event.CreatedAt = stored.CreatedAt
+	event.UpdatedAt = time.Now().UTC()
+	event.Version = stored.Version + 1
+	event.DeletedAt = nil
+	// Отметка об уведомлении сохраняется, пока не изменились начало события и смещение, а следующее
+	// уведомление - пока не изменилось расписание вхождений, как в БД
+	sameStart := stored.StartDate.Equal(event.StartDate) && stored.OffsetTime == event.OffsetTime
+	if sameStart {
+		event.NotifiedAt = stored.NotifiedAt
+	} else {
+		event.NotifiedAt = nil
+	}
+	if sameStart && stored.RRule == event.RRule && stored.TimeZone == event.TimeZone {
+		event.NextNotifyAt = stored.NextNotifyAt
+	} else {
+		notifyAt := event.NotifyAt()
+		event.NextNotifyAt = &notifyAt
+	}
+	if err = r.put(id, event); err != nil {
+		return nil, err
+	}
 	return &event, nil
 }
 
-func (r *EventCrudRepository) Delete(_ context.Context, _ sqlx.ExtContext, id string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	if _, ok := r.events[id]; !ok {
+func (r *EventCrudRepository) Delete(ctx context.Context, _ sqlx.ExtContext, id string, version int64) error {
+	defer r.lock(ctx)()
+	stored, ok := r.events[id]
+	if !ok || stored.DeletedAt != nil {
 		return repositories.ErrEntityNotFound
 	}
-	delete(r.events, id)
-	return nil
+	if version != 0 && version != stored.Version {
+		return repositories.ErrVersionMismatch
+	}
+	// Удаленное событие остается в корзине до очистки (PurgeTrash), которую для памяти запускает calendar
+	now := time.Now().UTC()
+	stored.DeletedAt = &now
+	stored.UpdatedAt = now
+	stored.Version++
+	return r.put(id, stored)
 }
 
-func (r *EventCrudRepository) GetByID(_ context.Context, _ sqlx.ExtContext, id string) (*events.Event, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	if event, exists := r.events[id]; exists {
+func (r *EventCrudRepository) GetByID(ctx context.Context, _ sqlx.ExtContext, id string) (*events.Event, error) {
+	defer r.rlock(ctx)()
+	if event, exists := r.events[id]; exists && event.DeletedAt == nil {
 		return &event, nil
 	}
 	return nil, repositories.ErrEntityNotFound
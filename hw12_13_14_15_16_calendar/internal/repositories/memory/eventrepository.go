@@ -2,12 +2,20 @@ package memory
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	events "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
 	"github.com/jmoiron/sqlx"
 )
 
+// contextLogger возвращает логгер запроса из ctx с именем репозитория.
+func contextLogger(ctx context.Context) logger.Logger {
+	return logger.FromContext(ctx).With(logger.ComponentKey, "memory")
+}
+
 type EventRepository struct {
 	crudRepo *EventCrudRepository
 }
@@ -23,28 +31,57 @@ func (r *EventRepository) GetDB() *sqlx.DB {
 }
 
 func (r *EventRepository) Create(ctx context.Context, exec sqlx.ExtContext, event events.Event) (*events.Event, error) {
-	return r.crudRepo.Create(ctx, exec, event)
+	created, err := r.crudRepo.Create(ctx, exec, event)
+	if err != nil {
+		return nil, err
+	}
+	contextLogger(ctx).Debug("event created", "event_id", created.ID)
+	return created, nil
 }
 
 func (r *EventRepository) Update(ctx context.Context, exec sqlx.ExtContext, id string, event events.Event) (*events.Event, error) {
-	return r.crudRepo.Update(ctx, exec, id, event)
+	updated, err := r.crudRepo.Update(ctx, exec, id, event)
+	if err != nil {
+		return nil, err
+	}
+	contextLogger(ctx).Debug("event updated", "event_id", id)
+	return updated, nil
 }
 
-func (r *EventRepository) Delete(ctx context.Context, exec sqlx.ExtContext, id string) error {
-	return r.crudRepo.Delete(ctx, exec, id)
+func (r *EventRepository) Delete(ctx context.Context, exec sqlx.ExtContext, id string, version int64) error {
+	if err := r.crudRepo.Delete(ctx, exec, id, version); err != nil {
+		return err
+	}
+	contextLogger(ctx).Debug("event moved to trash", "event_id", id)
+	return nil
 }
 
 func (r *EventRepository) GetByID(ctx context.Context, exec sqlx.ExtContext, id string) (*events.Event, error) {
 	return r.crudRepo.GetByID(ctx, exec, id)
 }
 
-func (r *EventRepository) FindEvent(_ context.Context, _ sqlx.ExtContext, userID string, startFrom, startTo, endFrom, endTo *time.Time) ([]events.Event, error) {
-	r.crudRepo.mu.RLock()
-	defer r.crudRepo.mu.RUnlock()
+func (r *EventRepository) FindEvent(ctx context.Context, exec sqlx.ExtContext, userID string, startFrom, startTo, endFrom, endTo *time.Time) ([]events.Event, error) {
+	result, _, err := r.FindEventPage(ctx, exec, userID, startFrom, startTo, endFrom, endTo, events.Page{})
+	return result, err
+}
+
+// FindEventPage разворачивает серии так же, как хранилища с БД: только в окне страницы,
+// которое задают курсор и разовые события, уже выбранные на страницу.
+func (r *EventRepository) FindEventPage(
+	ctx context.Context, _ sqlx.ExtContext, userID string, startFrom, startTo, endFrom, endTo *time.Time, page events.Page,
+) ([]events.Event, *events.Cursor, error) {
+	start := time.Now()
+	defer r.crudRepo.rlock(ctx)()
 
-	result := make([]events.Event, 0, len(r.crudRepo.events))
+	singles := make([]events.Event, 0, len(r.crudRepo.events))
+	var series []events.Event
 	for _, event := range r.crudRepo.events {
-		if userID != "" && event.UserID != userID {
+		if event.DeletedAt != nil || (userID != "" && event.UserID != userID) {
+			continue
+		}
+
+		if event.IsRecurring() {
+			series = append(series, event)
 			continue
 		}
 
@@ -64,8 +101,182 @@ func (r *EventRepository) FindEvent(_ context.Context, _ sqlx.ExtContext, userID
 			continue
 		}
 
+		singles = append(singles, event)
+	}
+
+	// Разовые события после курсора в порядке страницы, как их выбирает БД
+	singles, _ = events.Paginate(singles, events.Page{After: page.After, Descending: page.Descending})
+
+	result := singles
+	window := page.SeriesWindow(singles)
+	if !window.Empty() {
+		for _, event := range series {
+			// Серия разворачивается во вхождения, попадающие в запрошенное окно
+			occurrences, err := event.OccurrencesWithin(startFrom, startTo, endFrom, endTo, window)
+			if err != nil {
+				return nil, nil, err
+			}
+			result = append(result, occurrences...)
+		}
+	}
+
+	result, next := events.Paginate(result, page)
+	contextLogger(ctx).Debug("events found", "count", len(result), "duration", time.Since(start))
+	return result, next, nil
+}
+
+func (r *EventRepository) FindEventsToNotify(ctx context.Context, _ sqlx.ExtContext, now time.Time, limit int) ([]events.Event, error) {
+	defer r.crudRepo.rlock(ctx)()
+
+	result := make([]events.Event, 0)
+	for _, event := range r.crudRepo.events {
+		if event.NextNotifyAt == nil || event.NextNotifyAt.After(now) || event.DeletedAt != nil {
+			continue
+		}
+
+		result = append(result, event)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].NextNotifyAt.Before(*result[j].NextNotifyAt)
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+func (r *EventRepository) MarkNotified(ctx context.Context, _ sqlx.ExtContext, id string, notifiedAt, next *time.Time) error {
+	defer r.crudRepo.lock(ctx)()
+
+	event, ok := r.crudRepo.events[id]
+	if !ok {
+		return repositories.ErrEntityNotFound
+	}
+
+	if notifiedAt != nil {
+		event.NotifiedAt = notifiedAt
+	}
+	event.NextNotifyAt = next
+	return r.crudRepo.put(id, event)
+}
+
+// LockUser ничего не делает: транзакция TxManager держит блокировку всего хранилища.
+func (r *EventRepository) LockUser(_ context.Context, _ sqlx.ExtContext, _ string) error {
+	return nil
+}
+
+func (r *EventRepository) DeleteOlderThan(ctx context.Context, _ sqlx.ExtContext, cutoff time.Time, limit int) (int64, error) {
+	if limit <= 0 {
+		return 0, repositories.ErrInvalidLimit
+	}
+	defer r.crudRepo.lock(ctx)()
+
+	expired := make([]events.Event, 0)
+	for _, event := range r.crudRepo.events {
+		// Бесконечная серия не устаревает никогда
+		if event.SeriesEnd != nil && event.SeriesEnd.Before(cutoff) {
+			expired = append(expired, event)
+		}
+	}
+
+	// Как и в БД, порция состоит из событий, закончившихся раньше всех
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].SeriesEnd.Before(*expired[j].SeriesEnd)
+	})
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+
+	var deleted int64
+	for _, event := range expired {
+		if err := r.crudRepo.remove(event.ID); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+func (r *EventRepository) FindTrash(ctx context.Context, _ sqlx.ExtContext, userID string) ([]events.Event, error) {
+	defer r.crudRepo.rlock(ctx)()
+
+	result := make([]events.Event, 0)
+	for _, event := range r.crudRepo.events {
+		if event.DeletedAt == nil || (userID != "" && event.UserID != userID) {
+			continue
+		}
 		result = append(result, event)
 	}
 
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].DeletedAt.Equal(*result[j].DeletedAt) {
+			return result[i].DeletedAt.After(*result[j].DeletedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+
 	return result, nil
 }
+
+func (r *EventRepository) GetTrashedByID(ctx context.Context, _ sqlx.ExtContext, id string) (*events.Event, error) {
+	defer r.crudRepo.rlock(ctx)()
+
+	if event, ok := r.crudRepo.events[id]; ok && event.DeletedAt != nil {
+		return &event, nil
+	}
+	return nil, repositories.ErrEntityNotFound
+}
+
+func (r *EventRepository) Restore(ctx context.Context, _ sqlx.ExtContext, id string) (*events.Event, error) {
+	defer r.crudRepo.lock(ctx)()
+
+	event, ok := r.crudRepo.events[id]
+	if !ok || event.DeletedAt == nil {
+		return nil, repositories.ErrEntityNotFound
+	}
+
+	event.DeletedAt = nil
+	event.UpdatedAt = time.Now().UTC()
+	event.Version++
+	if err := r.crudRepo.put(id, event); err != nil {
+		return nil, err
+	}
+	contextLogger(ctx).Debug("event restored", "event_id", id)
+	return &event, nil
+}
+
+func (r *EventRepository) PurgeTrash(ctx context.Context, _ sqlx.ExtContext, cutoff time.Time, limit int) (int64, error) {
+	if limit <= 0 {
+		return 0, repositories.ErrInvalidLimit
+	}
+	defer r.crudRepo.lock(ctx)()
+
+	expired := make([]events.Event, 0)
+	for _, event := range r.crudRepo.events {
+		if event.DeletedAt != nil && event.DeletedAt.Before(cutoff) {
+			expired = append(expired, event)
+		}
+	}
+
+	// Как и в БД, порция состоит из событий, удаленных раньше всех
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].DeletedAt.Before(*expired[j].DeletedAt)
+	})
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+
+	var purged int64
+	for _, event := range expired {
+		if err := r.crudRepo.remove(event.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	return purged, nil
+}
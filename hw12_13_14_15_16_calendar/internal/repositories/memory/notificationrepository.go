@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
+	"github.com/jmoiron/sqlx"
+)
+
+type notificationKey struct {
+	eventID  string
+	notifyAt int64
+}
+
+type NotificationRepository struct {
+	notifications map[notificationKey]domain.Notification
+	mu            sync.RWMutex
+}
+
+func NewNotificationRepository() *NotificationRepository {
+	return &NotificationRepository{
+		notifications: make(map[notificationKey]domain.Notification),
+		mu:            sync.RWMutex{},
+	}
+}
+
+func (r *NotificationRepository) GetDB() *sqlx.DB {
+	return nil
+}
+
+func (r *NotificationRepository) Save(_ context.Context, _ sqlx.ExtContext, notification domain.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := notificationKey{eventID: notification.ID, notifyAt: notification.NotifyAt.UnixNano()}
+	if _, ok := r.notifications[key]; ok {
+		return repositories.ErrEntityAlreadyExists
+	}
+	r.notifications[key] = notification
+	return nil
+}
+
+func (r *NotificationRepository) FindByUserID(_ context.Context, _ sqlx.ExtContext, userID string) ([]domain.Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]domain.Notification, 0)
+	for _, notification := range r.notifications {
+		if notification.UserID == userID {
+			result = append(result, notification)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].NotifyAt.Before(result[j].NotifyAt)
+	})
+
+	return result, nil
+}
@@ -0,0 +1,440 @@
+package memory
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	events "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+)
+
+// Политики fsync журнала.
+const (
+	// FSyncAlways - fsync после каждой записи: изменения не теряются даже при сбое питания.
+	FSyncAlways = "always"
+	// FSyncInterval - fsync раз в секунду: при сбое теряются изменения за последнюю секунду.
+	FSyncInterval = "interval"
+	// FSyncNever - fsync выполняет ОС; при падении процесса данные не теряются, при сбое ОС - возможно.
+	FSyncNever = "never"
+)
+
+const (
+	snapshotFile = "events.snapshot"
+	journalFile  = "events.journal"
+
+	fsyncPeriod = time.Second
+
+	opPut    = "put"
+	opDelete = "delete"
+)
+
+var (
+	ErrCorruptedJournal = errors.New("corrupted journal")
+	// ErrStorageFailed - журнал не удалось вернуть в согласованное состояние после ошибки записи;
+	// изменения отклоняются до успешного сжатия (Compact).
+	ErrStorageFailed = errors.New("storage failed")
+)
+
+// PersistenceConfig - сохранение событий на диск: снимок всех событий и журнал изменений после него.
+type PersistenceConfig struct {
+	// Dir - каталог файлов снимка и журнала.
+	Dir string
+	// FSync - политика fsync журнала: FSyncAlways, FSyncInterval или FSyncNever.
+	FSync string
+	// CompactInterval - как часто переписывать снимок и очищать журнал, 0 - только при открытии и закрытии.
+	CompactInterval time.Duration
+}
+
+// storedEvent - событие на диске; в отличие от JSON API сохраняет служебные поля.
+type storedEvent struct {
+	events.Event
+	NotifiedAt   *time.Time `json:"notifiedAt,omitempty"`
+	NextNotifyAt *time.Time `json:"nextNotifyAt,omitempty"`
+	SeriesEnd    *time.Time `json:"seriesEnd,omitempty"`
+	Version      int64      `json:"version,omitempty"`
+	DeletedAt    *time.Time `json:"deletedAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	TimeZone     string     `json:"timeZone,omitempty"`
+}
+
+func newStoredEvent(event events.Event) *storedEvent {
+	return &storedEvent{
+		Event:        event,
+		NotifiedAt:   event.NotifiedAt,
+		NextNotifyAt: event.NextNotifyAt,
+		SeriesEnd:    event.SeriesEnd,
+		Version:      event.Version,
+		DeletedAt:    event.DeletedAt,
+		CreatedAt:    event.CreatedAt,
+		UpdatedAt:    event.UpdatedAt,
+		TimeZone:     event.TimeZone,
+	}
+}
+
+func (s *storedEvent) toEvent() events.Event {
+	event := s.Event
+	event.NotifiedAt = s.NotifiedAt
+	event.NextNotifyAt = s.NextNotifyAt
+	// Снимки без следующего уведомления заполняются так же, как миграцией БД
+	if event.NextNotifyAt == nil {
+		switch {
+		case event.NotifiedAt == nil:
+			notifyAt := event.NotifyAt()
+			event.NextNotifyAt = &notifyAt
+		case event.IsRecurring():
+			event.NextNotifyAt = event.NotifiedAt
+		}
+	}
+	event.SeriesEnd = s.SeriesEnd
+	// События, сохраненные до появления версий, получают первую версию
+	event.Version = max(s.Version, 1)
+	event.DeletedAt = s.DeletedAt
+	event.CreatedAt = s.CreatedAt
+	event.UpdatedAt = s.UpdatedAt
+	event.TimeZone = s.TimeZone
+	return event
+}
+
+// journalEntry - строка журнала: сохранение события целиком или его удаление.
+type journalEntry struct {
+	Op    string       `json:"op"`
+	ID    string       `json:"id"`
+	Event *storedEvent `json:"event,omitempty"`
+}
+
+// journalWriter - файл журнала.
+type journalWriter interface {
+	io.WriteSeeker
+	Sync() error
+	Truncate(size int64) error
+	Close() error
+}
+
+// persistence ведет журнал изменений. Методы вызываются под блокировкой репозитория.
+type persistence struct {
+	config  PersistenceConfig
+	log     logger.Logger
+	journal journalWriter
+	dirty   bool
+	failed  error
+
+	stop      chan struct{}
+	done      sync.WaitGroup
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewPersistentEventCrudRepository создает хранилище в памяти, восстановленное из каталога config.Dir.
+func NewPersistentEventCrudRepository(config PersistenceConfig, log logger.Logger) (*EventCrudRepository, error) {
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	r := NewEventCrudRepository()
+	if err := loadSnapshot(filepath.Join(config.Dir, snapshotFile), r.events); err != nil {
+		return nil, err
+	}
+	journalPath := filepath.Join(config.Dir, journalFile)
+	if err := replayJournal(journalPath, r.events); err != nil {
+		return nil, err
+	}
+
+	journal, err := os.OpenFile(journalPath, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	log = log.With(logger.ComponentKey, "memory")
+	r.persistence = &persistence{config: config, log: log, journal: journal, stop: make(chan struct{})}
+
+	// Недописанная после сбоя строка не должна оказаться перед новыми записями
+	if err := r.Compact(); err != nil {
+		_ = journal.Close()
+		return nil, err
+	}
+	log.Info("storage loaded", "path", config.Dir, "events", len(r.events))
+
+	r.persistence.done.Add(1)
+	go r.background()
+	return r, nil
+}
+
+func loadSnapshot(path string, target map[string]events.Event) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var stored storedEvent
+		if err := json.Unmarshal(scanner.Bytes(), &stored); err != nil {
+			return fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		target[stored.ID] = stored.toEvent()
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	return nil
+}
+
+// replayJournal применяет журнал к target, отбрасывая недописанную последнюю строку.
+func replayJournal(path string, target map[string]events.Event) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	for line := 1; len(data) > 0; line++ {
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			return nil
+		}
+		var entry journalEntry
+		if err := json.Unmarshal(data[:end], &entry); err != nil {
+			return fmt.Errorf("%w: line %d: %w", ErrCorruptedJournal, line, err)
+		}
+		switch {
+		case entry.Op == opPut && entry.Event != nil:
+			target[entry.ID] = entry.Event.toEvent()
+		case entry.Op == opDelete:
+			delete(target, entry.ID)
+		default:
+			return fmt.Errorf("%w: line %d: unknown operation %q", ErrCorruptedJournal, line, entry.Op)
+		}
+		data = data[end+1:]
+	}
+	return nil
+}
+
+// put сохраняет событие под ключом id: сначала в журнал, затем в память. Вызывается под r.mu.
+// В транзакции запись в журнал откладывается до фиксации.
+func (r *EventCrudRepository) put(id string, event events.Event) error {
+	entry := journalEntry{Op: opPut, ID: id, Event: newStoredEvent(event)}
+	if r.tx != nil {
+		r.tx.record(entry)
+	} else if err := r.appendJournal(entry); err != nil {
+		return err
+	}
+	r.events[id] = event
+	return nil
+}
+
+// remove удаляет событие: сначала в журнале, затем в памяти. Вызывается под r.mu.
+func (r *EventCrudRepository) remove(id string) error {
+	entry := journalEntry{Op: opDelete, ID: id}
+	if r.tx != nil {
+		r.tx.record(entry)
+	} else if err := r.appendJournal(entry); err != nil {
+		return err
+	}
+	delete(r.events, id)
+	return nil
+}
+
+// appendJournal дописывает записи в журнал одной операцией записи. При ошибке журнал
+// обрезается до прежнего размера: иначе недописанная строка испортит журнал, а откаченная
+// транзакция применится при следующем открытии.
+func (r *EventCrudRepository) appendJournal(entries ...journalEntry) error {
+	p := r.persistence
+	if p == nil || len(entries) == 0 {
+		return nil
+	}
+	if p.failed != nil {
+		return p.failed
+	}
+
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal journal entry: %w", err)
+		}
+		buf.Write(append(line, '\n'))
+	}
+
+	offset, err := p.journal.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("failed to get journal size: %w", err)
+	}
+	if err := p.write(buf.Bytes()); err != nil {
+		p.rewind(offset)
+		return err
+	}
+	return nil
+}
+
+func (p *persistence) write(data []byte) error {
+	if _, err := p.journal.Write(data); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if p.config.FSync == FSyncAlways {
+		if err := p.journal.Sync(); err != nil {
+			return fmt.Errorf("failed to sync journal: %w", err)
+		}
+	} else {
+		p.dirty = true
+	}
+	return nil
+}
+
+// rewind отрезает от журнала все, что записано после offset. Если это не удалось,
+// хранилище отклоняет изменения до успешного сжатия.
+func (p *persistence) rewind(offset int64) {
+	err := p.journal.Truncate(offset)
+	if err == nil {
+		_, err = p.journal.Seek(offset, io.SeekStart)
+	}
+	if err != nil {
+		p.failed = fmt.Errorf("%w: failed to rewind journal: %w", ErrStorageFailed, err)
+		p.log.Error("failed to rewind journal", "error", err)
+	}
+}
+
+// Compact атомарно заменяет снимок всеми событиями и очищает журнал.
+func (r *EventCrudRepository) Compact() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p := r.persistence
+	if p == nil {
+		return nil
+	}
+
+	path := filepath.Join(p.config.Dir, snapshotFile)
+	if err := writeSnapshot(path, r.events); err != nil {
+		return err
+	}
+	if err := p.journal.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate journal: %w", err)
+	}
+	// Снимок содержит только примененные изменения, журнал снова согласован
+	p.dirty = false
+	p.failed = nil
+	return nil
+}
+
+func writeSnapshot(path string, source map[string]events.Event) error {
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	writer := bufio.NewWriter(f)
+	encoder := json.NewEncoder(writer)
+	for _, event := range source {
+		if err = encoder.Encode(newStoredEvent(event)); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace snapshot: %w", err)
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open storage directory: %w", err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("failed to sync storage directory: %w", err)
+	}
+	return nil
+}
+
+// background выполняет fsync по политике FSyncInterval и периодическое сжатие журнала.
+func (r *EventCrudRepository) background() {
+	p := r.persistence
+	defer p.done.Done()
+
+	fsyncTicker := time.NewTicker(fsyncPeriod)
+	defer fsyncTicker.Stop()
+	var compact <-chan time.Time
+	if p.config.CompactInterval > 0 {
+		compactTicker := time.NewTicker(p.config.CompactInterval)
+		defer compactTicker.Stop()
+		compact = compactTicker.C
+	}
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-fsyncTicker.C:
+			if p.config.FSync == FSyncInterval {
+				if err := r.syncJournal(); err != nil {
+					p.log.Error("failed to sync journal", "error", err)
+				}
+			}
+		case <-compact:
+			if err := r.Compact(); err != nil {
+				p.log.Error("failed to compact storage", "error", err)
+			}
+		}
+	}
+}
+
+func (r *EventCrudRepository) syncJournal() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p := r.persistence
+	if !p.dirty {
+		return nil
+	}
+	if err := p.journal.Sync(); err != nil {
+		return err
+	}
+	p.dirty = false
+	return nil
+}
+
+// closePersistence сворачивает журнал в снимок и закрывает файлы; повторные вызовы возвращают результат первого.
+func (r *EventCrudRepository) closePersistence() error {
+	p := r.persistence
+	p.closeOnce.Do(func() {
+		close(p.stop)
+		p.done.Wait()
+
+		p.closeErr = r.Compact()
+		if err := p.journal.Close(); err != nil && p.closeErr == nil {
+			p.closeErr = fmt.Errorf("failed to close journal: %w", err)
+		}
+	})
+	return p.closeErr
+}
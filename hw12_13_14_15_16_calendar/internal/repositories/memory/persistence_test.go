@@ -0,0 +1,248 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openPersistent(t *testing.T, dir string) *EventCrudRepository {
+	t.Helper()
+	repo, err := NewPersistentEventCrudRepository(PersistenceConfig{Dir: dir, FSync: FSyncAlways}, logger.New("ERROR", io.Discard))
+	require.NoError(t, err)
+	return repo
+}
+
+func TestPersistentEventCrudRepository_Restore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	start := time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC)
+
+	crudRepo := openPersistent(t, dir)
+	repo, err := NewEventRepository(crudRepo)
+	require.NoError(t, err)
+
+	kept, err := crudRepo.Create(ctx, nil, domain.Event{
+		Title: "Daily", StartDate: start, EndDate: start.Add(time.Hour), UserID: "user-1",
+		OffsetTime: 15 * time.Minute, RRule: "FREQ=DAILY;COUNT=3", ExDates: domain.ExDates{start.AddDate(0, 0, 1)},
+		TimeZone: "Europe/Moscow",
+	})
+	require.NoError(t, err)
+	updated := *kept
+	updated.Title = "Daily standup"
+	_, err = crudRepo.Update(ctx, nil, kept.ID, updated)
+	require.NoError(t, err)
+	notifiedAt, next := start.Add(-15*time.Minute), start.Add(2*24*time.Hour-15*time.Minute)
+	require.NoError(t, repo.MarkNotified(ctx, nil, kept.ID, &notifiedAt, &next))
+
+	deleted, err := crudRepo.Create(ctx, nil, domain.Event{Title: "Old", StartDate: start, EndDate: start.Add(time.Hour), UserID: "user-1"})
+	require.NoError(t, err)
+	require.NoError(t, crudRepo.Delete(ctx, nil, deleted.ID, 0))
+
+	old, err := crudRepo.Create(ctx, nil, domain.Event{Title: "Older", StartDate: start.AddDate(-2, 0, 0), EndDate: start.AddDate(-2, 0, 0).Add(time.Hour), UserID: "user-1"})
+	require.NoError(t, err)
+	count, err := repo.DeleteOlderThan(ctx, nil, start.AddDate(-1, 0, 0), 10)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, count)
+
+	expected, err := crudRepo.GetByID(ctx, nil, kept.ID)
+	require.NoError(t, err)
+	require.NoError(t, crudRepo.Close(ctx))
+	require.NoError(t, crudRepo.Close(ctx), "repeated close")
+
+	restored := openPersistent(t, dir)
+	defer restored.Close(ctx)
+
+	got, err := restored.GetByID(ctx, nil, kept.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Daily standup", got.Title)
+	assert.Equal(t, expected.RRule, got.RRule)
+	assert.Equal(t, int64(2), got.Version)
+	require.NotNil(t, got.NotifiedAt)
+	assert.True(t, expected.NotifiedAt.Equal(*got.NotifiedAt))
+	require.NotNil(t, got.NextNotifyAt)
+	assert.True(t, next.Equal(*got.NextNotifyAt))
+	require.NotNil(t, got.SeriesEnd)
+	assert.True(t, expected.SeriesEnd.Equal(*got.SeriesEnd))
+	require.Len(t, got.ExDates, 1)
+	assert.True(t, expected.ExDates[0].Equal(got.ExDates[0]))
+	assert.False(t, got.CreatedAt.IsZero())
+	assert.True(t, expected.CreatedAt.Equal(got.CreatedAt))
+	assert.True(t, expected.UpdatedAt.Equal(got.UpdatedAt))
+	assert.Equal(t, "Europe/Moscow", got.TimeZone)
+
+	for _, id := range []string{deleted.ID, old.ID} {
+		_, err = restored.GetByID(ctx, nil, id)
+		assert.ErrorIs(t, err, repositories.ErrEntityNotFound)
+	}
+
+	// Удаленное событие остается в корзине
+	restoredRepo, err := NewEventRepository(restored)
+	require.NoError(t, err)
+	trashed, err := restoredRepo.GetTrashedByID(ctx, nil, deleted.ID)
+	require.NoError(t, err)
+	require.NotNil(t, trashed.DeletedAt)
+	assert.Equal(t, int64(2), trashed.Version)
+}
+
+func TestPersistentEventCrudRepository_Journal(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	journalPath := filepath.Join(dir, journalFile)
+	start := time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC)
+
+	repo := openPersistent(t, dir)
+	created, err := repo.Create(ctx, nil, domain.Event{Title: "Meeting", StartDate: start, EndDate: start.Add(time.Hour), UserID: "user-1"})
+	require.NoError(t, err)
+
+	info, err := os.Stat(journalPath)
+	require.NoError(t, err)
+	assert.Positive(t, info.Size(), "change is journaled before compaction")
+
+	require.NoError(t, repo.Compact())
+	info, err = os.Stat(journalPath)
+	require.NoError(t, err)
+	assert.Zero(t, info.Size())
+
+	// Процесс упал, не закрыв хранилище: журнал содержит удаление и недописанную строку.
+	require.NoError(t, repo.Delete(ctx, nil, created.ID, 0))
+	journal, err := os.OpenFile(journalPath, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = journal.WriteString(`{"op":"put","id":"torn","ev`)
+	require.NoError(t, err)
+	require.NoError(t, journal.Close())
+
+	restored := openPersistent(t, dir)
+	_, err = restored.GetByID(ctx, nil, created.ID)
+	assert.ErrorIs(t, err, repositories.ErrEntityNotFound)
+	_, err = restored.GetByID(ctx, nil, "torn")
+	assert.ErrorIs(t, err, repositories.ErrEntityNotFound)
+	require.NoError(t, restored.Close(ctx))
+	require.NoError(t, repo.Close(ctx))
+}
+
+func TestPersistentEventCrudRepository_CorruptedJournal(t *testing.T) {
+	dir := t.TempDir()
+	content := "{\"op\":\"delete\",\"id\":\"1\"}\nnot json\n{\"op\":\"delete\",\"id\":\"2\"}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, journalFile), []byte(content), 0o644))
+
+	_, err := NewPersistentEventCrudRepository(PersistenceConfig{Dir: dir, FSync: FSyncNever}, logger.New("ERROR", io.Discard))
+	require.ErrorIs(t, err, ErrCorruptedJournal)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+// faultyJournal - журнал, запись в который обрывается на середине.
+type faultyJournal struct {
+	journalWriter
+	failWrite    bool
+	failSync     bool
+	failTruncate bool
+}
+
+func (j *faultyJournal) Write(p []byte) (int, error) {
+	if j.failWrite {
+		n, _ := j.journalWriter.Write(p[:len(p)/2])
+		return n, errors.New("disk full")
+	}
+	return j.journalWriter.Write(p)
+}
+
+func (j *faultyJournal) Sync() error {
+	if j.failSync {
+		return errors.New("i/o error")
+	}
+	return j.journalWriter.Sync()
+}
+
+func (j *faultyJournal) Truncate(size int64) error {
+	if j.failTruncate {
+		return errors.New("i/o error")
+	}
+	return j.journalWriter.Truncate(size)
+}
+
+func TestPersistentEventCrudRepository_JournalFailure(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC)
+	event := domain.Event{Title: "Meeting", StartDate: start, EndDate: start.Add(time.Hour), UserID: "user-1"}
+
+	tests := []struct {
+		name   string
+		faults faultyJournal
+		inTx   bool
+	}{
+		{name: "torn write", faults: faultyJournal{failWrite: true}},
+		{name: "failed sync", faults: faultyJournal{failSync: true}},
+		{name: "failed sync on commit", faults: faultyJournal{failSync: true}, inTx: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			repo := openPersistent(t, dir)
+			kept, err := repo.Create(ctx, nil, event)
+			require.NoError(t, err)
+
+			journal := tt.faults
+			journal.journalWriter = repo.persistence.journal
+			repo.persistence.journal = &journal
+
+			if tt.inTx {
+				err = NewTxManager(repo).WithTransaction(ctx, nil, func(ctx context.Context, _ *sqlx.Tx) error {
+					_, err := repo.Create(ctx, nil, event)
+					return err
+				})
+			} else {
+				_, err = repo.Create(ctx, nil, event)
+			}
+			require.Error(t, err)
+
+			// Журнал обрезан до записи: после неудачи он пишется и читается как прежде
+			journal.failWrite, journal.failSync = false, false
+			next, err := repo.Create(ctx, nil, event)
+			require.NoError(t, err)
+
+			restored := openPersistent(t, dir)
+			defer restored.Close(ctx)
+			found, err := NewEventRepository(restored)
+			require.NoError(t, err)
+			all, err := found.FindEvent(ctx, nil, "", nil, nil, nil, nil)
+			require.NoError(t, err)
+			ids := make([]string, 0, len(all))
+			for _, e := range all {
+				ids = append(ids, e.ID)
+			}
+			assert.ElementsMatch(t, []string{kept.ID, next.ID}, ids)
+		})
+	}
+
+	t.Run("failed rewind", func(t *testing.T) {
+		repo := openPersistent(t, t.TempDir())
+		defer repo.Close(ctx)
+
+		journal := &faultyJournal{journalWriter: repo.persistence.journal, failWrite: true, failTruncate: true}
+		repo.persistence.journal = journal
+		_, err := repo.Create(ctx, nil, event)
+		require.Error(t, err)
+
+		journal.failWrite, journal.failTruncate = false, false
+		_, err = repo.Create(ctx, nil, event)
+		require.ErrorIs(t, err, ErrStorageFailed)
+
+		// Сжатие переписывает снимок и журнал, после него изменения снова принимаются
+		require.NoError(t, repo.Compact())
+		_, err = repo.Create(ctx, nil, event)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	events "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type txContextKey struct{}
+
+// TxManager выполняет транзакции над хранилищем в памяти, держа блокировку на запись до их завершения.
+type TxManager struct {
+	repo     *EventCrudRepository
+	observer database.TxObserver
+}
+
+func NewTxManager(repo *EventCrudRepository) *TxManager {
+	return &TxManager{repo: repo}
+}
+
+// NewTxManagerWithObserver создает менеджер, сообщающий observer о фиксации и откате транзакций.
+// Транзакции в памяти не повторяются, поэтому TransactionRetried не вызывается.
+func NewTxManagerWithObserver(repo *EventCrudRepository, observer database.TxObserver) *TxManager {
+	return &TxManager{repo: repo, observer: observer}
+}
+
+// GetDB возвращает nil: хранилище в памяти не использует БД.
+func (tm *TxManager) GetDB() *sqlx.DB {
+	return nil
+}
+
+// WithTransaction выполняет fn в транзакции, которую репозитории находят по ctx, поэтому tx - nil.
+// Вложенный вызов - точка сохранения. Транзакция opts.ReadOnly не блокирует хранилище на запись.
+func (tm *TxManager) WithTransaction(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx *sqlx.Tx) error) error {
+	r := tm.repo
+	if t := r.txFrom(ctx); t != nil {
+		return t.run(ctx, fn)
+	}
+	committed := false
+	if tm.observer != nil {
+		defer func() { tm.observer.TransactionFinished(committed) }()
+	}
+	if opts != nil && opts.ReadOnly {
+		err := fn(ctx, nil)
+		committed = err == nil
+		return err
+	}
+
+	r.mu.Lock()
+	t := &transaction{repo: r}
+	t.active.Store(true)
+	r.tx = t
+	defer func() {
+		t.active.Store(false)
+		r.tx = nil
+		r.mu.Unlock()
+	}()
+
+	err := t.run(context.WithValue(ctx, txContextKey{}, t), func(ctx context.Context, tx *sqlx.Tx) error {
+		if err := fn(ctx, tx); err != nil {
+			return err
+		}
+		if err := r.appendJournal(t.journal...); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil
+	})
+	committed = err == nil
+	return err
+}
+
+// undoEntry - версия события до изменения в транзакции.
+type undoEntry struct {
+	id      string
+	event   events.Event
+	existed bool
+}
+
+// transaction - открытая транзакция: журнал отката и записи журнала на диске,
+// которые попадут в него при фиксации. Обе последовательности растут вместе.
+type transaction struct {
+	repo    *EventCrudRepository
+	active  atomic.Bool
+	undo    []undoEntry
+	journal []journalEntry
+}
+
+// run выполняет fn и откатывает ее изменения при ошибке или панике.
+func (t *transaction) run(ctx context.Context, fn func(ctx context.Context, tx *sqlx.Tx) error) error {
+	mark := len(t.undo)
+	done := false
+	defer func() {
+		if !done {
+			t.rollbackTo(mark)
+		}
+	}()
+
+	err := fn(ctx, nil)
+	done = err == nil
+	return err
+}
+
+// record запоминает текущую версию события и откладывает запись в журнал до фиксации.
+func (t *transaction) record(entry journalEntry) {
+	event, existed := t.repo.events[entry.ID]
+	t.undo = append(t.undo, undoEntry{id: entry.ID, event: event, existed: existed})
+	t.journal = append(t.journal, entry)
+}
+
+// rollbackTo отменяет изменения, сделанные после mark, в обратном порядке.
+func (t *transaction) rollbackTo(mark int) {
+	for i := len(t.undo) - 1; i >= mark; i-- {
+		undo := t.undo[i]
+		if undo.existed {
+			t.repo.events[undo.id] = undo.event
+		} else {
+			delete(t.repo.events, undo.id)
+		}
+	}
+	t.undo = t.undo[:mark]
+	t.journal = t.journal[:mark]
+}
+
+// txFrom возвращает открытую транзакцию репозитория из ctx или nil.
+func (r *EventCrudRepository) txFrom(ctx context.Context) *transaction {
+	t, ok := ctx.Value(txContextKey{}).(*transaction)
+	if !ok || t.repo != r || !t.active.Load() {
+		return nil
+	}
+	return t
+}
+
+// lock блокирует хранилище на запись и возвращает функцию разблокировки.
+// Внутри транзакции блокировка уже захвачена, и lock ничего не делает.
+func (r *EventCrudRepository) lock(ctx context.Context) func() {
+	if r.txFrom(ctx) != nil {
+		return func() {}
+	}
+	r.mu.Lock()
+	return r.mu.Unlock
+}
+
+// rlock блокирует хранилище на чтение, внутри транзакции ничего не делает.
+func (r *EventCrudRepository) rlock(ctx context.Context) func() {
+	if r.txFrom(ctx) != nil {
+		return func() {}
+	}
+	r.mu.RLock()
+	return r.mu.RUnlock
+}
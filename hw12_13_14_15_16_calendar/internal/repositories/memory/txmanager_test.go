@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxManager_Isolation(t *testing.T) {
+	ctx := context.Background()
+	crudRepo := NewEventCrudRepository()
+	repo, err := NewEventRepository(crudRepo)
+	require.NoError(t, err)
+	tm := NewTxManager(crudRepo)
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	found := make(chan int)
+	err = tm.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := repo.Create(ctx, tx, domain.Event{Title: "First", StartDate: start, EndDate: start.Add(time.Hour), UserID: "user-1"}); err != nil {
+			return err
+		}
+
+		// Чтение вне транзакции ждет ее завершения
+		go func() {
+			events, _ := repo.FindEvent(context.Background(), nil, "user-1", nil, nil, nil, nil)
+			found <- len(events)
+		}()
+		select {
+		case n := <-found:
+			t.Errorf("read outside transaction was not blocked, found %d events", n)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		_, err := repo.Create(ctx, tx, domain.Event{Title: "Second", StartDate: start.Add(time.Hour), EndDate: start.Add(2 * time.Hour), UserID: "user-1"})
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, <-found, "read sees the committed transaction")
+}
+
+func TestTxManager_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+	crudRepo := NewEventCrudRepository()
+	repo, err := NewEventRepository(crudRepo)
+	require.NoError(t, err)
+	tm := NewTxManager(crudRepo)
+	readOnly := &sql.TxOptions{ReadOnly: true}
+
+	found := make(chan error)
+	err = tm.WithTransaction(ctx, readOnly, func(ctx context.Context, tx *sqlx.Tx) error {
+		// Чтения только для чтения не ждут друг друга
+		go func() {
+			found <- tm.WithTransaction(context.Background(), readOnly, func(ctx context.Context, tx *sqlx.Tx) error {
+				_, err := repo.FindEvent(ctx, tx, "user-1", nil, nil, nil, nil)
+				return err
+			})
+		}()
+		select {
+		case err := <-found:
+			return err
+		case <-time.After(time.Second):
+			return errors.New("read-only transaction was blocked by another one")
+		}
+	})
+	require.NoError(t, err)
+}
+
+func TestTxManager_Nested(t *testing.T) {
+	ctx := context.Background()
+	crudRepo := NewEventCrudRepository()
+	tm := NewTxManager(crudRepo)
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	errRollback := errors.New("rollback")
+
+	err := tm.WithTransaction(ctx, nil, func(ctx context.Context, _ *sqlx.Tx) error {
+		return tm.WithTransaction(ctx, nil, func(ctx context.Context, _ *sqlx.Tx) error {
+			if _, err := crudRepo.Create(ctx, nil, domain.Event{Title: "Nested", StartDate: start, EndDate: start.Add(time.Hour), UserID: "user-1"}); err != nil {
+				return err
+			}
+			return errRollback
+		})
+	})
+	require.ErrorIs(t, err, errRollback)
+	assert.Empty(t, crudRepo.events, "nested call joins the outer transaction and is rolled back with it")
+}
+
+func TestTxManager_Journal(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	crudRepo := openPersistent(t, dir)
+	tm := NewTxManager(crudRepo)
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	var committed *domain.Event
+	err := tm.WithTransaction(ctx, nil, func(ctx context.Context, _ *sqlx.Tx) error {
+		var err error
+		committed, err = crudRepo.Create(ctx, nil, domain.Event{Title: "Committed", StartDate: start, EndDate: start.Add(time.Hour), UserID: "user-1"})
+		return err
+	})
+	require.NoError(t, err)
+
+	errRollback := errors.New("rollback")
+	err = tm.WithTransaction(ctx, nil, func(ctx context.Context, _ *sqlx.Tx) error {
+		if _, err := crudRepo.Create(ctx, nil, domain.Event{Title: "Rolled back", StartDate: start, EndDate: start.Add(time.Hour), UserID: "user-1"}); err != nil {
+			return err
+		}
+		if err := crudRepo.Delete(ctx, nil, committed.ID, 0); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	require.ErrorIs(t, err, errRollback)
+
+	// Журнал читается без закрытия хранилища, как после падения процесса:
+	// в нем только зафиксированные изменения
+	restored := openPersistent(t, dir)
+	require.Len(t, restored.events, 1)
+	assert.Equal(t, "Committed", restored.events[committed.ID].Title)
+
+	require.NoError(t, restored.Close(ctx))
+	require.NoError(t, crudRepo.Close(ctx))
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type NotificationRepository interface {
+	// Save сохраняет уведомление. Уведомление идентифицируется ID события и временем отправки,
+	// для уже сохраненного уведомления возвращается ErrEntityAlreadyExists.
+	Save(ctx context.Context, exec sqlx.ExtContext, notification domain.Notification) error
+	FindByUserID(ctx context.Context, exec sqlx.ExtContext, userID string) ([]domain.Notification, error)
+	GetDB() *sqlx.DB
+}
@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
+	"github.com/jmoiron/sqlx"
+)
+
+type CleanerConfig struct {
+	// Retention - сколько хранить событие после его окончания.
+	Retention time.Duration
+	// TrashRetention - сколько хранить удаленное событие в корзине.
+	TrashRetention time.Duration
+	Interval       time.Duration
+	// BatchSize - сколько событий удалять в одной транзакции, должен быть положительным.
+	BatchSize int
+}
+
+// deleteFunc удаляет порцию событий старше cutoff и возвращает их количество.
+type deleteFunc func(ctx context.Context, exec sqlx.ExtContext, cutoff time.Time, limit int) (int64, error)
+
+// Cleaner периодически удаляет старые события и очищает корзину порциями, каждая в своей транзакции.
+type Cleaner struct {
+	repository repositories.CompositeEventRepository
+	txManager  database.TxManager
+	logger     logger.Logger
+	config     CleanerConfig
+	now        func() time.Time
+}
+
+func NewCleaner(
+	repo repositories.CompositeEventRepository,
+	txManager database.TxManager,
+	log logger.Logger,
+	config CleanerConfig,
+) *Cleaner {
+	return &Cleaner{
+		repository: repo,
+		txManager:  txManager,
+		logger:     log.With(logger.ComponentKey, "cleaner"),
+		config:     config,
+		now:        time.Now,
+	}
+}
+
+// Run запускает очистку сразу и далее с заданным интервалом до отмены контекста.
+func (c *Cleaner) Run(ctx context.Context) error {
+	c.logger.Info("started", "interval", c.config.Interval,
+		"retention", c.config.Retention, "trash_retention", c.config.TrashRetention)
+
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := c.Cleanup(ctx); err != nil {
+			c.logger.Error("failed to clean up events", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			c.logger.Info("stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Cleanup выполняет один проход очистки и возвращает количество удаленных событий.
+func (c *Cleaner) Cleanup(ctx context.Context) (int64, error) {
+	now := c.now()
+
+	deleted, err := c.deleteInBatches(ctx, now.Add(-c.config.Retention), c.repository.DeleteOlderThan)
+	if err != nil {
+		return deleted, fmt.Errorf("failed to delete old events: %w", err)
+	}
+	c.logger.Info("old events deleted", "count", deleted)
+
+	purged, err := c.deleteInBatches(ctx, now.Add(-c.config.TrashRetention), c.repository.PurgeTrash)
+	if err != nil {
+		return deleted + purged, fmt.Errorf("failed to purge trash: %w", err)
+	}
+	c.logger.Info("trashed events purged", "count", purged)
+
+	return deleted + purged, nil
+}
+
+// deleteInBatches вызывает del, пока он удаляет полные порции.
+func (c *Cleaner) deleteInBatches(ctx context.Context, cutoff time.Time, del deleteFunc) (int64, error) {
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		deleted, err := c.deleteBatch(ctx, cutoff, del)
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+		if deleted < int64(c.config.BatchSize) {
+			return total, nil
+		}
+	}
+}
+
+func (c *Cleaner) deleteBatch(ctx context.Context, cutoff time.Time, del deleteFunc) (int64, error) {
+	var deleted int64
+	err := executeWithTx(ctx, c.txManager, func(ctx context.Context, exec sqlx.ExtContext) error {
+		var err error
+		deleted, err = del(ctx, exec, cutoff, c.config.BatchSize)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,143 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleaner_Cleanup(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	repo := newTestRepository(t)
+	c := NewCleaner(repo, nil, logger.New("ERROR", &bytes.Buffer{}), CleanerConfig{
+		Retention:      365 * 24 * time.Hour,
+		TrashRetention: 30 * 24 * time.Hour,
+		Interval:       time.Hour,
+		BatchSize:      2,
+	})
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		_, err := repo.Create(ctx, nil, domain.Event{
+			Title:     "Old event",
+			StartDate: now.AddDate(-2, 0, i),
+			EndDate:   now.AddDate(-2, 0, i).Add(time.Hour),
+			UserID:    "user-1",
+		})
+		require.NoError(t, err)
+	}
+
+	kept, err := repo.Create(ctx, nil, domain.Event{
+		Title:     "Recent event",
+		StartDate: now.AddDate(0, -6, 0),
+		EndDate:   now.AddDate(0, -6, 0).Add(time.Hour),
+		UserID:    "user-1",
+	})
+	require.NoError(t, err)
+
+	t.Run("deletes all old events in batches", func(t *testing.T) {
+		deleted, err := c.Cleanup(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), deleted)
+
+		events, err := repo.FindEvent(ctx, nil, "", nil, nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, kept.ID, events[0].ID)
+	})
+
+	t.Run("nothing to delete", func(t *testing.T) {
+		deleted, err := c.Cleanup(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+	})
+}
+
+func TestCleaner_PurgeTrash(t *testing.T) {
+	ctx := context.Background()
+	// Время удаления задает хранилище, поэтому отсчет идет от текущего времени
+	deletedAt := time.Now()
+	start := deletedAt.AddDate(0, 1, 0)
+
+	repo := newTestRepository(t)
+	c := NewCleaner(repo, nil, logger.New("ERROR", &bytes.Buffer{}), CleanerConfig{
+		Retention:      365 * 24 * time.Hour,
+		TrashRetention: 30 * 24 * time.Hour,
+		Interval:       time.Hour,
+		BatchSize:      2,
+	})
+
+	for i := 0; i < 3; i++ {
+		created, err := repo.Create(ctx, nil, domain.Event{
+			Title:     "Deleted event",
+			StartDate: start.Add(time.Duration(i) * time.Hour),
+			EndDate:   start.Add(time.Duration(i)*time.Hour + 30*time.Minute),
+			UserID:    "user-1",
+		})
+		require.NoError(t, err)
+		require.NoError(t, repo.Delete(ctx, nil, created.ID, 0))
+	}
+
+	kept, err := repo.Create(ctx, nil, domain.Event{
+		Title:     "Kept event",
+		StartDate: start.AddDate(0, 0, 1),
+		EndDate:   start.AddDate(0, 0, 1).Add(time.Hour),
+		UserID:    "user-1",
+	})
+	require.NoError(t, err)
+
+	t.Run("keeps events within trash retention", func(t *testing.T) {
+		c.now = func() time.Time { return deletedAt.AddDate(0, 0, 29) }
+		purged, err := c.Cleanup(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, purged)
+
+		trash, err := repo.FindTrash(ctx, nil, "")
+		require.NoError(t, err)
+		assert.Len(t, trash, 3)
+	})
+
+	t.Run("purges trash in batches", func(t *testing.T) {
+		c.now = func() time.Time { return deletedAt.AddDate(0, 0, 31) }
+		purged, err := c.Cleanup(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), purged)
+
+		trash, err := repo.FindTrash(ctx, nil, "")
+		require.NoError(t, err)
+		assert.Empty(t, trash)
+
+		_, err = repo.GetByID(ctx, nil, kept.ID)
+		require.NoError(t, err)
+	})
+}
+
+func TestCleaner_Run_StopsOnContextCancel(t *testing.T) {
+	c := NewCleaner(newTestRepository(t), nil, logger.New("ERROR", &bytes.Buffer{}), CleanerConfig{
+		Retention: time.Hour,
+		Interval:  10 * time.Millisecond,
+		BatchSize: 10,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Run(ctx)
+	}()
+
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("cleaner did not stop")
+	}
+}
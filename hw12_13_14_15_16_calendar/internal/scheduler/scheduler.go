@@ -0,0 +1,185 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	Topic     string
+	Interval  time.Duration
+	BatchSize int
+}
+
+// Scheduler периодически выбирает события, по которым наступило время уведомления,
+// и публикует уведомления в очередь.
+type Scheduler struct {
+	repository repositories.CompositeEventRepository
+	txManager  database.TxManager
+	producer   queue.Producer
+	logger     logger.Logger
+	config     Config
+	now        func() time.Time
+}
+
+func New(
+	repo repositories.CompositeEventRepository,
+	txManager database.TxManager,
+	producer queue.Producer,
+	log logger.Logger,
+	config Config,
+) *Scheduler {
+	return &Scheduler{
+		repository: repo,
+		txManager:  txManager,
+		producer:   producer,
+		logger:     log.With(logger.ComponentKey, "scheduler"),
+		config:     config,
+		now:        time.Now,
+	}
+}
+
+// Run запускает сканирование сразу и далее с заданным интервалом до отмены контекста.
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.logger.Info("started", "interval", s.config.Interval)
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Notify(ctx); err != nil {
+			s.logger.Error("failed to send notifications", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Notify выполняет один проход: публикует уведомления по всем наступившим вхождениям событий
+// и переносит у событий момент следующего уведомления. Возвращает количество отправленных уведомлений.
+func (s *Scheduler) Notify(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		sent, processed, err := s.notifyBatch(ctx)
+		total += sent
+		if err != nil {
+			return total, err
+		}
+		if processed == 0 || processed < s.config.BatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		s.logger.Info("notifications sent", "count", total)
+	}
+	return total, nil
+}
+
+// notifyBatch обрабатывает одну пачку событий. Возвращает число отправленных уведомлений
+// и число обработанных событий: событие без наступившего вхождения только переносится.
+func (s *Scheduler) notifyBatch(ctx context.Context) (int, int, error) {
+	sent, processed := 0, 0
+	err := executeWithTx(ctx, s.txManager, func(ctx context.Context, exec sqlx.ExtContext) error {
+		now := s.now()
+		dueEvents, err := s.repository.FindEventsToNotify(ctx, exec, now, s.config.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range dueEvents {
+			occurrence, next, err := dueOccurrence(event, now)
+			if err != nil {
+				// Ошибка в правиле не исправится сама: событие снимается с уведомлений, чтобы не останавливать пачку
+				s.logger.Error("failed to find occurrence of event", "event_id", event.ID, "error", err)
+			}
+
+			var notifiedAt *time.Time
+			if occurrence != nil {
+				if err := s.publish(ctx, domain.NewNotification(*occurrence)); err != nil {
+					return err
+				}
+				notifiedAt = &now
+				sent++
+			}
+			if err := s.repository.MarkNotified(ctx, exec, event.ID, notifiedAt, next); err != nil {
+				return fmt.Errorf("failed to mark event %s as notified: %w", event.ID, err)
+			}
+		}
+		processed = len(dueEvents)
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return sent, processed, nil
+}
+
+// dueOccurrence возвращает вхождение события, о котором пора уведомить, и момент следующего
+// уведомления, nil - уведомлять больше не о чем. Вхождения, закончившиеся к now (планировщик
+// не работал), пропускаются без уведомления; если после них ни одно вхождение еще не наступило,
+// возвращается только момент следующего уведомления.
+func dueOccurrence(event domain.Event, now time.Time) (*domain.Event, *time.Time, error) {
+	from := event.NextNotifyAt.Add(event.OffsetTime)
+	if notEnded := now.Add(-event.EndDate.Sub(event.StartDate)).Add(time.Nanosecond); notEnded.After(from) {
+		from = notEnded
+	}
+
+	occurrence, err := event.NextOccurrence(from)
+	if err != nil || occurrence == nil {
+		return nil, nil, err
+	}
+	if occurrence.NotifyAt().After(now) {
+		next := occurrence.NotifyAt()
+		return nil, &next, nil
+	}
+
+	following, err := event.NextOccurrence(occurrence.StartDate.Add(time.Nanosecond))
+	if err != nil || following == nil {
+		return occurrence, nil, err
+	}
+	next := following.NotifyAt()
+	return occurrence, &next, nil
+}
+
+func (s *Scheduler) publish(ctx context.Context, notification domain.Notification) error {
+	value, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	msg := queue.Message{
+		Topic: s.config.Topic,
+		Key:   []byte(notification.ID),
+		Value: value,
+	}
+	if err := s.producer.Publish(ctx, msg); err != nil {
+		return fmt.Errorf("failed to publish notification for event %s: %w", notification.ID, err)
+	}
+
+	s.logger.Debug("notification published", "event_id", notification.ID)
+	return nil
+}
+
+func executeWithTx(ctx context.Context, txManager database.TxManager, fn func(context.Context, sqlx.ExtContext) error) error {
+	if txManager == nil {
+		return fn(ctx, nil)
+	}
+	return txManager.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+		return fn(ctx, tx)
+	})
+}
@@ -0,0 +1,239 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+	memoryqueue "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue/memory"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingProducer struct{}
+
+func (p *failingProducer) Publish(_ context.Context, _ queue.Message) error {
+	return errors.New("broker unavailable")
+}
+
+func (p *failingProducer) Close() error {
+	return nil
+}
+
+func newTestRepository(t *testing.T) *memory.EventRepository {
+	t.Helper()
+	repo, err := memory.NewEventRepository(memory.NewEventCrudRepository())
+	require.NoError(t, err)
+	return repo
+}
+
+func TestScheduler_Notify(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	repo := newTestRepository(t)
+	broker := memoryqueue.NewBroker()
+	s := New(repo, nil, broker, logger.New("ERROR", &bytes.Buffer{}), Config{
+		Topic:     "notifications",
+		Interval:  time.Minute,
+		BatchSize: 1,
+	})
+	s.now = func() time.Time { return now }
+
+	due, err := repo.Create(ctx, nil, domain.Event{
+		Title:      "Due event",
+		StartDate:  now.Add(30 * time.Minute),
+		EndDate:    now.Add(time.Hour),
+		UserID:     "user-1",
+		OffsetTime: time.Hour,
+	})
+	require.NoError(t, err)
+
+	started, err := repo.Create(ctx, nil, domain.Event{
+		Title:     "Started event",
+		StartDate: now.Add(-10 * time.Minute),
+		EndDate:   now.Add(10 * time.Minute),
+		UserID:    "user-2",
+	})
+	require.NoError(t, err)
+
+	_, err = repo.Create(ctx, nil, domain.Event{
+		Title:      "Future event",
+		StartDate:  now.Add(2 * time.Hour),
+		EndDate:    now.Add(3 * time.Hour),
+		UserID:     "user-1",
+		OffsetTime: time.Hour,
+	})
+	require.NoError(t, err)
+
+	finished, err := repo.Create(ctx, nil, domain.Event{
+		Title:     "Finished event",
+		StartDate: now.Add(-2 * time.Hour),
+		EndDate:   now.Add(-time.Hour),
+		UserID:    "user-1",
+	})
+	require.NoError(t, err)
+
+	t.Run("publishes notifications for due events", func(t *testing.T) {
+		sent, err := s.Notify(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, sent)
+
+		messages := broker.Messages("notifications")
+		require.Len(t, messages, 2)
+
+		notifications := make(map[string]domain.Notification)
+		for _, msg := range messages {
+			var n domain.Notification
+			require.NoError(t, json.Unmarshal(msg.Value, &n))
+			assert.Equal(t, n.ID, string(msg.Key))
+			notifications[n.ID] = n
+		}
+
+		require.Contains(t, notifications, due.ID)
+		assert.Equal(t, "Due event", notifications[due.ID].Title)
+		assert.Equal(t, "user-1", notifications[due.ID].UserID)
+		assert.True(t, due.StartDate.Equal(notifications[due.ID].Date))
+		assert.True(t, now.Add(-30*time.Minute).Equal(notifications[due.ID].NotifyAt))
+		assert.Contains(t, notifications, started.ID)
+	})
+
+	t.Run("does not resend notified events", func(t *testing.T) {
+		sent, err := s.Notify(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, sent)
+		assert.Len(t, broker.Messages("notifications"), 2)
+
+		stored, err := repo.GetByID(ctx, nil, due.ID)
+		require.NoError(t, err)
+		require.NotNil(t, stored.NotifiedAt)
+		assert.True(t, now.Equal(*stored.NotifiedAt))
+
+		// Закончившееся событие пропускается без уведомления
+		stored, err = repo.GetByID(ctx, nil, finished.ID)
+		require.NoError(t, err)
+		assert.Nil(t, stored.NotifiedAt)
+		assert.Nil(t, stored.NextNotifyAt)
+	})
+}
+
+func TestScheduler_Notify_RecurringSeries(t *testing.T) {
+	ctx := context.Background()
+	dtstart := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	repo := newTestRepository(t)
+	broker := memoryqueue.NewBroker()
+	s := New(repo, nil, broker, logger.New("ERROR", &bytes.Buffer{}), Config{
+		Topic:     "notifications",
+		Interval:  time.Minute,
+		BatchSize: 10,
+	})
+
+	series, err := repo.Create(ctx, nil, domain.Event{
+		Title:      "Standup",
+		StartDate:  dtstart,
+		EndDate:    dtstart.Add(30 * time.Minute),
+		UserID:     "user-1",
+		OffsetTime: time.Hour,
+		RRule:      "FREQ=DAILY",
+	})
+	require.NoError(t, err)
+
+	notifyAt := func(now time.Time) []domain.Notification {
+		t.Helper()
+		s.now = func() time.Time { return now }
+		before := len(broker.Messages("notifications"))
+		_, err := s.Notify(ctx)
+		require.NoError(t, err)
+
+		var result []domain.Notification
+		for _, msg := range broker.Messages("notifications")[before:] {
+			var n domain.Notification
+			require.NoError(t, json.Unmarshal(msg.Value, &n))
+			result = append(result, n)
+		}
+		return result
+	}
+
+	t.Run("notifies each day", func(t *testing.T) {
+		for day := 0; day < 2; day++ {
+			start := dtstart.AddDate(0, 0, day)
+			sent := notifyAt(start.Add(-time.Hour))
+			require.Len(t, sent, 1)
+			assert.Equal(t, series.ID, sent[0].ID)
+			assert.True(t, start.Equal(sent[0].Date), sent[0].Date)
+			assert.True(t, start.Add(-time.Hour).Equal(sent[0].NotifyAt))
+
+			assert.Empty(t, notifyAt(start.Add(-30*time.Minute)), "occurrence is notified once")
+		}
+
+		stored, err := repo.GetByID(ctx, nil, series.ID)
+		require.NoError(t, err)
+		require.NotNil(t, stored.NextNotifyAt)
+		assert.True(t, dtstart.AddDate(0, 0, 2).Add(-time.Hour).Equal(*stored.NextNotifyAt))
+	})
+
+	t.Run("skips occurrences ended while scheduler was down", func(t *testing.T) {
+		start := dtstart.AddDate(0, 0, 4)
+		sent := notifyAt(start.Add(15 * time.Minute))
+		require.Len(t, sent, 1)
+		assert.True(t, start.Equal(sent[0].Date), sent[0].Date)
+
+		stored, err := repo.GetByID(ctx, nil, series.ID)
+		require.NoError(t, err)
+		require.NotNil(t, stored.NextNotifyAt)
+		assert.True(t, start.AddDate(0, 0, 1).Add(-time.Hour).Equal(*stored.NextNotifyAt))
+	})
+}
+
+func TestScheduler_Notify_PublishError(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	repo := newTestRepository(t)
+	s := New(repo, nil, &failingProducer{}, logger.New("ERROR", &bytes.Buffer{}), Config{
+		Topic:     "notifications",
+		Interval:  time.Minute,
+		BatchSize: 10,
+	})
+	s.now = func() time.Time { return now }
+
+	event, err := repo.Create(ctx, nil, domain.Event{
+		Title:     "Due event",
+		StartDate: now,
+		EndDate:   now.Add(time.Hour),
+		UserID:    "user-1",
+	})
+	require.NoError(t, err)
+
+	sent, err := s.Notify(ctx)
+	require.Error(t, err)
+	assert.Equal(t, 0, sent)
+
+	stored, err := repo.GetByID(ctx, nil, event.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.NotifiedAt, "event must stay unnotified when publishing fails")
+	require.NotNil(t, stored.NextNotifyAt)
+	assert.True(t, now.Equal(*stored.NextNotifyAt))
+}
+
+func TestScheduler_Run_StopsOnContextCancel(t *testing.T) {
+	repo := newTestRepository(t)
+	s := New(repo, nil, memoryqueue.NewBroker(), logger.New("ERROR", &bytes.Buffer{}), Config{
+		Topic:     "notifications",
+		Interval:  10 * time.Millisecond,
+		BatchSize: 10,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	require.NoError(t, s.Run(ctx))
+}
@@ -0,0 +1,201 @@
+package internalgrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/grpc/pb"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type findInPeriodFunc func(ctx context.Context, userID string, date time.Time) ([]domain.Event, error)
+
+// EventServer реализует gRPC-сервис Calendar поверх app.Application.
+type EventServer struct {
+	pb.UnimplementedCalendarServer
+
+	app    app.Application
+	logger logger.Logger
+}
+
+func NewEventServer(app app.Application, log logger.Logger) *EventServer {
+	return &EventServer{
+		app:    app,
+		logger: log,
+	}
+}
+
+// log возвращает логгер с идентификатором текущего запроса.
+func (s *EventServer) log(ctx context.Context) logger.Logger {
+	return logger.WithContext(ctx, s.logger)
+}
+
+func (s *EventServer) CreateEvent(ctx context.Context, req *pb.CreateEventRequest) (*pb.EventResponse, error) {
+	if req.GetEvent() == nil {
+		return nil, status.Error(codes.InvalidArgument, "event is required")
+	}
+
+	createdEvent, err := s.app.CreateEvent(ctx, eventToDomain(req.GetEvent()))
+	if err != nil {
+		s.log(ctx).Error("failed to create event", "error", err)
+		return nil, toStatus(err)
+	}
+
+	s.log(ctx).Info("event created successfully", "event_id", createdEvent.ID)
+	return &pb.EventResponse{Event: eventToProto(*createdEvent)}, nil
+}
+
+func (s *EventServer) UpdateEvent(ctx context.Context, req *pb.UpdateEventRequest) (*pb.EventResponse, error) {
+	if req.GetEvent() == nil {
+		return nil, status.Error(codes.InvalidArgument, "event is required")
+	}
+
+	event := eventToDomain(req.GetEvent())
+	event.ID = req.GetId()
+	event.Version = req.GetVersion()
+
+	updatedEvent, err := s.app.UpdateEvent(ctx, req.GetId(), event)
+	if err != nil {
+		s.log(ctx).Error("failed to update event", "error", err)
+		return nil, toStatus(err)
+	}
+
+	s.log(ctx).Info("event updated successfully", "event_id", req.GetId())
+	return &pb.EventResponse{Event: eventToProto(*updatedEvent)}, nil
+}
+
+func (s *EventServer) PatchEvent(ctx context.Context, req *pb.PatchEventRequest) (*pb.EventResponse, error) {
+	patch, err := eventPatchToDomain(req.GetEvent(), req.GetUpdateMask())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	patchedEvent, err := s.app.PatchEvent(ctx, req.GetId(), patch, req.GetVersion())
+	if err != nil {
+		s.log(ctx).Error("failed to patch event", "error", err)
+		return nil, toStatus(err)
+	}
+
+	s.log(ctx).Info("event patched successfully", "event_id", req.GetId())
+	return &pb.EventResponse{Event: eventToProto(*patchedEvent)}, nil
+}
+
+func (s *EventServer) DeleteEvent(ctx context.Context, req *pb.DeleteEventRequest) (*emptypb.Empty, error) {
+	if err := s.app.DeleteEvent(ctx, req.GetId(), req.GetVersion()); err != nil {
+		s.log(ctx).Error("failed to delete event", "error", err)
+		return nil, toStatus(err)
+	}
+
+	s.log(ctx).Info("event deleted successfully", "event_id", req.GetId())
+	return &emptypb.Empty{}, nil
+}
+
+func (s *EventServer) GetEvent(ctx context.Context, req *pb.GetEventRequest) (*pb.EventResponse, error) {
+	event, err := s.app.GetEventByID(ctx, req.GetId())
+	if err != nil {
+		s.log(ctx).Error("failed to get event", "error", err)
+		return nil, toStatus(err)
+	}
+
+	return &pb.EventResponse{Event: eventToProto(*event)}, nil
+}
+
+func (s *EventServer) FindEvents(ctx context.Context, req *pb.FindEventsRequest) (*pb.EventsResponse, error) {
+	page, err := domain.NewPage(int(req.GetLimit()), req.GetCursor(), req.GetDescending())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	findedEvents, next, err := s.app.FindEvent(ctx, req.GetUserId(),
+		timestampToTime(req.GetStartFrom()), timestampToTime(req.GetStartTo()),
+		timestampToTime(req.GetEndFrom()), timestampToTime(req.GetEndTo()), page)
+	if err != nil {
+		s.log(ctx).Error("failed to find events", "error", err)
+		return nil, toStatus(err)
+	}
+
+	response := &pb.EventsResponse{Events: eventsToProto(findedEvents)}
+	if next != nil {
+		response.NextCursor = next.Encode()
+	}
+	return response, nil
+}
+
+func (s *EventServer) ListDayEvents(ctx context.Context, req *pb.ListEventsRequest) (*pb.EventsResponse, error) {
+	return s.listPeriodEvents(ctx, req, s.app.FindDayEvents)
+}
+
+func (s *EventServer) ListWeekEvents(ctx context.Context, req *pb.ListEventsRequest) (*pb.EventsResponse, error) {
+	return s.listPeriodEvents(ctx, req, s.app.FindWeekEvents)
+}
+
+func (s *EventServer) ListMonthEvents(ctx context.Context, req *pb.ListEventsRequest) (*pb.EventsResponse, error) {
+	return s.listPeriodEvents(ctx, req, s.app.FindMonthEvents)
+}
+
+func (s *EventServer) FindTrash(ctx context.Context, req *pb.FindTrashRequest) (*pb.EventsResponse, error) {
+	trashed, err := s.app.FindTrash(ctx, req.GetUserId())
+	if err != nil {
+		s.log(ctx).Error("failed to find trashed events", "error", err)
+		return nil, toStatus(err)
+	}
+
+	return &pb.EventsResponse{Events: eventsToProto(trashed)}, nil
+}
+
+func (s *EventServer) RestoreEvent(ctx context.Context, req *pb.RestoreEventRequest) (*pb.EventResponse, error) {
+	restored, err := s.app.RestoreEvent(ctx, req.GetId())
+	if err != nil {
+		s.log(ctx).Error("failed to restore event", "error", err)
+		return nil, toStatus(err)
+	}
+
+	s.log(ctx).Info("event restored successfully", "event_id", req.GetId())
+	return &pb.EventResponse{Event: eventToProto(*restored)}, nil
+}
+
+func (s *EventServer) listPeriodEvents(ctx context.Context, req *pb.ListEventsRequest, find findInPeriodFunc) (*pb.EventsResponse, error) {
+	loc := time.UTC
+	if req.GetTimezone() != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.GetTimezone()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid time zone")
+		}
+	}
+
+	date, err := time.ParseInLocation(time.DateOnly, req.GetDate(), loc)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid date format, use YYYY-MM-DD")
+	}
+
+	findedEvents, err := find(ctx, req.GetUserId(), date)
+	if err != nil {
+		s.log(ctx).Error("failed to find events", "error", err)
+		return nil, toStatus(err)
+	}
+
+	return &pb.EventsResponse{Events: eventsToProto(findedEvents)}, nil
+}
+
+// toStatus выбирает код gRPC по категории ошибки сервиса: занятое время - FailedPrecondition,
+// устаревшая версия события - Aborted, клиенту стоит перечитать событие и повторить запрос.
+func toStatus(err error) error {
+	switch services.KindOf(err) {
+	case services.KindNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case services.KindConflict:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case services.KindValidation:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case services.KindPrecondition:
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
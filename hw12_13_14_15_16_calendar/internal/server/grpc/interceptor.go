@@ -0,0 +1,106 @@
+package internalgrpc
+
+import (
+	"context"
+	"net"
+	"path"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/metrics"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// requestIDHeader - ключ метаданных с идентификатором запроса, как X-Request-ID в HTTP.
+	requestIDHeader    = "x-request-id"
+	maxRequestIDLength = 128
+)
+
+// RequestIDInterceptor берет идентификатор запроса из метаданных x-request-id или генерирует новый,
+// возвращает его в заголовке ответа и сохраняет в контексте вместе с логгером запроса.
+func RequestIDInterceptor(log logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var requestID string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if ids := md.Get(requestIDHeader); len(ids) > 0 && len(ids[0]) <= maxRequestIDLength {
+				requestID = ids[0]
+			}
+		}
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		if err := grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID)); err != nil {
+			log.Warn("failed to set request id header", "error", err)
+		}
+
+		ctx = logger.WithRequestID(ctx, requestID)
+		ctx = logger.NewContext(ctx, log.With(logger.RequestIDKey, requestID))
+		return handler(ctx, req)
+	}
+}
+
+// LoggingInterceptor пишет запись о каждом вызове с теми же полями, что и handlers.LoggingMiddleware:
+// вместо URI - полное имя метода, вместо HTTP-статуса - код gRPC.
+func LoggingInterceptor(log logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		log := logger.WithContext(ctx, log)
+
+		log.Info("grpc request",
+			"client_ip", getClientIP(ctx),
+			"method", info.FullMethod,
+			"code", status.Code(err).String(),
+			"latency", time.Since(start),
+			"user_agent", getUserAgent(ctx),
+		)
+
+		if err != nil {
+			log.Error("Handler error", "error", err)
+		}
+
+		return resp, err
+	}
+}
+
+// MetricsInterceptor учитывает вызовы по имени метода и коду gRPC.
+func MetricsInterceptor(m *metrics.Metrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.ObserveRequest(metrics.TransportGRPC, path.Base(info.FullMethod), status.Code(err).String(), time.Since(start))
+		return resp, err
+	}
+}
+
+func getClientIP(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ip := md.Get("x-forwarded-for"); len(ip) > 0 {
+			return ip[0]
+		}
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "-"
+	}
+
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
+
+func getUserAgent(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ua := md.Get("user-agent"); len(ua) > 0 {
+			return ua[0]
+		}
+	}
+	return "-"
+}
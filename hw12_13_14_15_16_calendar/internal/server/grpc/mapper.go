@@ -0,0 +1,124 @@
+package internalgrpc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/grpc/pb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func eventToDomain(e *pb.Event) domain.Event {
+	event := domain.Event{
+		ID:          e.GetId(),
+		Title:       e.GetTitle(),
+		Description: e.GetDescription(),
+		UserID:      e.GetUserId(),
+		OffsetTime:  e.GetOffsetTime().AsDuration(),
+		RRule:       e.GetRrule(),
+		TimeZone:    e.GetTimeZone(),
+	}
+	for _, ts := range e.GetExdates() {
+		event.ExDates = append(event.ExDates, ts.AsTime())
+	}
+	// Незаданная дата остается нулевой, чтобы ее отклонила валидация сервиса
+	if e.GetStartDate() != nil {
+		event.StartDate = e.GetStartDate().AsTime()
+	}
+	if e.GetEndDate() != nil {
+		event.EndDate = e.GetEndDate().AsTime()
+	}
+	return event
+}
+
+// eventPatchToDomain собирает патч из полей e, перечисленных в mask. Поле из маски,
+// не заданное в e, сбрасывается.
+func eventPatchToDomain(e *pb.Event, mask *fieldmaskpb.FieldMask) (domain.EventPatch, error) {
+	if len(mask.GetPaths()) == 0 {
+		return domain.EventPatch{}, errors.New("update_mask is required")
+	}
+
+	var patch domain.EventPatch
+	for _, path := range mask.GetPaths() {
+		switch path {
+		case "title":
+			patch.Title = ptr(e.GetTitle())
+		case "start_date":
+			patch.StartDate = ptr(timestampOrZero(e.GetStartDate()))
+		case "end_date":
+			patch.EndDate = ptr(timestampOrZero(e.GetEndDate()))
+		case "description":
+			patch.Description = ptr(e.GetDescription())
+		case "user_id":
+			patch.UserID = ptr(e.GetUserId())
+		case "offset_time":
+			patch.OffsetTime = ptr(e.GetOffsetTime().AsDuration())
+		case "rrule":
+			patch.RRule = ptr(e.GetRrule())
+		case "exdates":
+			exDates := domain.ExDates{}
+			for _, ts := range e.GetExdates() {
+				exDates = append(exDates, ts.AsTime())
+			}
+			patch.ExDates = &exDates
+		case "time_zone":
+			patch.TimeZone = ptr(e.GetTimeZone())
+		default:
+			return domain.EventPatch{}, fmt.Errorf("unknown update_mask path %q", path)
+		}
+	}
+	return patch, nil
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// timestampOrZero возвращает нулевое время для незаданной даты, чтобы ее отклонила валидация сервиса.
+func timestampOrZero(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func eventToProto(e domain.Event) *pb.Event {
+	exDates := make([]*timestamppb.Timestamp, 0, len(e.ExDates))
+	for _, t := range e.ExDates {
+		exDates = append(exDates, timestamppb.New(t))
+	}
+
+	return &pb.Event{
+		Id:          e.ID,
+		Title:       e.Title,
+		StartDate:   timestamppb.New(e.StartDate),
+		EndDate:     timestamppb.New(e.EndDate),
+		Description: e.Description,
+		UserId:      e.UserID,
+		OffsetTime:  durationpb.New(e.OffsetTime),
+		Rrule:       e.RRule,
+		Exdates:     exDates,
+		Version:     e.Version,
+		TimeZone:    e.ZoneName(),
+	}
+}
+
+func eventsToProto(events []domain.Event) []*pb.Event {
+	result := make([]*pb.Event, 0, len(events))
+	for _, e := range events {
+		result = append(result, eventToProto(e))
+	}
+	return result
+}
+
+func timestampToTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
@@ -0,0 +1,89 @@
+package internalgrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/grpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+const defaultShutdownTimeout = 5 * time.Second
+
+type Server struct {
+	server *grpc.Server
+	logger logger.Logger
+	addr   string
+}
+
+// NewServer создает сервер; interceptors выполняются после RequestIDInterceptor и LoggingInterceptor.
+func NewServer(log logger.Logger, eventServer *EventServer, addr string, interceptors ...grpc.UnaryServerInterceptor) *Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(append([]grpc.UnaryServerInterceptor{RequestIDInterceptor(log), LoggingInterceptor(log)}, interceptors...)...),
+	)
+	pb.RegisterCalendarServer(server, eventServer)
+	reflection.Register(server)
+
+	return &Server{
+		server: server,
+		logger: log,
+		addr:   addr,
+	}
+}
+
+// Start слушает addr и обслуживает запросы до отмены ctx или вызова Stop.
+func (s *Server) Start(ctx context.Context) error {
+	s.logger.Info("starting gRPC server", "addr", s.addr)
+
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- s.Serve(listener)
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), defaultShutdownTimeout)
+		defer cancel()
+
+		return s.Stop(shutdownCtx)
+	}
+}
+
+// Serve обслуживает запросы на уже открытом listener.
+func (s *Server) Serve(listener net.Listener) error {
+	if err := s.server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// Stop дожидается завершения активных вызовов, а по истечении ctx прерывает их.
+func (s *Server) Stop(ctx context.Context) error {
+	s.logger.Info("stopping gRPC server")
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
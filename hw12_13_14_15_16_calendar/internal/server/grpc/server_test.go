@@ -0,0 +1,291 @@
+package internalgrpc
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/metrics"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories/memory"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/grpc/pb"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newTestClient(t *testing.T, logOutput *bytes.Buffer, interceptors ...grpc.UnaryServerInterceptor) pb.CalendarClient {
+	t.Helper()
+
+	log := logger.New("INFO", logOutput)
+	repo, err := memory.NewEventRepository(memory.NewEventCrudRepository())
+	require.NoError(t, err)
+	calendar := app.New(services.NewEventService(repo, nil), nil, log, time.Monday)
+
+	server := NewServer(log, NewEventServer(calendar, log), "bufconn", interceptors...)
+	listener := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		require.NoError(t, server.Stop(ctx))
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return pb.NewCalendarClient(conn)
+}
+
+func TestServer_EventLifecycle(t *testing.T) {
+	ctx := context.Background()
+	var logOutput bytes.Buffer
+	client := newTestClient(t, &logOutput)
+
+	userID := uuid.New().String()
+	start := time.Date(2026, 2, 11, 10, 0, 0, 0, time.UTC)
+
+	created, err := client.CreateEvent(ctx, &pb.CreateEventRequest{Event: &pb.Event{
+		Title:      "Team Meeting",
+		StartDate:  timestamppb.New(start),
+		EndDate:    timestamppb.New(start.Add(time.Hour)),
+		UserId:     userID,
+		OffsetTime: durationpb.New(15 * time.Minute),
+		TimeZone:   "Europe/Moscow",
+	}})
+	require.NoError(t, err)
+	id := created.GetEvent().GetId()
+	require.NotEmpty(t, id)
+	assert.Equal(t, 15*time.Minute, created.GetEvent().GetOffsetTime().AsDuration())
+	assert.Equal(t, "Europe/Moscow", created.GetEvent().GetTimeZone())
+
+	t.Run("get", func(t *testing.T) {
+		resp, err := client.GetEvent(ctx, &pb.GetEventRequest{Id: id})
+		require.NoError(t, err)
+		assert.Equal(t, "Team Meeting", resp.GetEvent().GetTitle())
+		assert.True(t, start.Equal(resp.GetEvent().GetStartDate().AsTime()))
+	})
+
+	version := created.GetEvent().GetVersion()
+	t.Run("update", func(t *testing.T) {
+		update := &pb.UpdateEventRequest{Id: id, Version: version, Event: &pb.Event{
+			Title:     "Team Meeting - Updated",
+			StartDate: timestamppb.New(start),
+			EndDate:   timestamppb.New(start.Add(2 * time.Hour)),
+			UserId:    userID,
+		}}
+		resp, err := client.UpdateEvent(ctx, update)
+		require.NoError(t, err)
+		assert.Equal(t, "Team Meeting - Updated", resp.GetEvent().GetTitle())
+		assert.Greater(t, resp.GetEvent().GetVersion(), version)
+
+		// Повтор с прежней версией отклоняется
+		_, err = client.UpdateEvent(ctx, update)
+		assert.Equal(t, codes.Aborted, status.Code(err))
+		version = resp.GetEvent().GetVersion()
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		resp, err := client.PatchEvent(ctx, &pb.PatchEventRequest{
+			Id: id, Version: version,
+			Event:      &pb.Event{Description: "Weekly sync"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"description", "time_zone"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Team Meeting - Updated", resp.GetEvent().GetTitle(), "field outside the mask is kept")
+		assert.Equal(t, "Weekly sync", resp.GetEvent().GetDescription())
+		assert.Empty(t, resp.GetEvent().GetTimeZone(), "masked field without value is reset")
+		version = resp.GetEvent().GetVersion()
+	})
+
+	t.Run("find", func(t *testing.T) {
+		resp, err := client.FindEvents(ctx, &pb.FindEventsRequest{UserId: userID, StartFrom: timestamppb.New(start)})
+		require.NoError(t, err)
+		require.Len(t, resp.GetEvents(), 1)
+		assert.Equal(t, id, resp.GetEvents()[0].GetId())
+	})
+
+	t.Run("list day, week and month", func(t *testing.T) {
+		req := &pb.ListEventsRequest{UserId: userID, Date: "2026-02-11", Timezone: "Europe/Moscow"}
+
+		day, err := client.ListDayEvents(ctx, req)
+		require.NoError(t, err)
+		assert.Len(t, day.GetEvents(), 1)
+
+		week, err := client.ListWeekEvents(ctx, req)
+		require.NoError(t, err)
+		assert.Len(t, week.GetEvents(), 1)
+
+		month, err := client.ListMonthEvents(ctx, &pb.ListEventsRequest{UserId: userID, Date: "2026-03-01"})
+		require.NoError(t, err)
+		assert.Empty(t, month.GetEvents())
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := client.DeleteEvent(ctx, &pb.DeleteEventRequest{Id: id, Version: version - 1})
+		assert.Equal(t, codes.Aborted, status.Code(err))
+
+		_, err = client.DeleteEvent(ctx, &pb.DeleteEventRequest{Id: id, Version: version})
+		require.NoError(t, err)
+
+		_, err = client.GetEvent(ctx, &pb.GetEventRequest{Id: id})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("trash and restore", func(t *testing.T) {
+		trash, err := client.FindTrash(ctx, &pb.FindTrashRequest{UserId: userID})
+		require.NoError(t, err)
+		require.Len(t, trash.GetEvents(), 1)
+		assert.Equal(t, id, trash.GetEvents()[0].GetId())
+
+		// Время удаленного события заняли: восстановить его нельзя
+		busy, err := client.CreateEvent(ctx, &pb.CreateEventRequest{Event: &pb.Event{
+			Title: "Other", StartDate: timestamppb.New(start), EndDate: timestamppb.New(start.Add(time.Hour)), UserId: userID,
+		}})
+		require.NoError(t, err)
+		_, err = client.RestoreEvent(ctx, &pb.RestoreEventRequest{Id: id})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		_, err = client.DeleteEvent(ctx, &pb.DeleteEventRequest{Id: busy.GetEvent().GetId()})
+		require.NoError(t, err)
+		restored, err := client.RestoreEvent(ctx, &pb.RestoreEventRequest{Id: id})
+		require.NoError(t, err)
+		assert.Equal(t, "Weekly sync", restored.GetEvent().GetDescription())
+
+		_, err = client.GetEvent(ctx, &pb.GetEventRequest{Id: id})
+		require.NoError(t, err)
+	})
+
+	t.Run("calls are logged", func(t *testing.T) {
+		assert.True(t, strings.Contains(logOutput.String(), "method=/calendar.v1.Calendar/CreateEvent code=OK"))
+		assert.True(t, strings.Contains(logOutput.String(), "method=/calendar.v1.Calendar/GetEvent code=NotFound"))
+	})
+}
+
+func TestServer_InvalidArguments(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, &bytes.Buffer{})
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{
+			name: "create without event",
+			call: func() error {
+				_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{})
+				return err
+			},
+		},
+		{
+			name: "create without title",
+			call: func() error {
+				_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{Event: &pb.Event{UserId: uuid.New().String()}})
+				return err
+			},
+		},
+		{
+			name: "invalid event time zone",
+			call: func() error {
+				start := time.Date(2026, 2, 11, 10, 0, 0, 0, time.UTC)
+				_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{Event: &pb.Event{
+					Title: "Meeting", StartDate: timestamppb.New(start), EndDate: timestamppb.New(start.Add(time.Hour)),
+					UserId: uuid.New().String(), TimeZone: "Mars/Olympus",
+				}})
+				return err
+			},
+		},
+		{
+			name: "patch without update mask",
+			call: func() error {
+				_, err := client.PatchEvent(ctx, &pb.PatchEventRequest{Id: uuid.New().String(), Event: &pb.Event{Title: "Meeting"}})
+				return err
+			},
+		},
+		{
+			name: "patch unknown field",
+			call: func() error {
+				_, err := client.PatchEvent(ctx, &pb.PatchEventRequest{
+					Id: uuid.New().String(), UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"version"}},
+				})
+				return err
+			},
+		},
+		{
+			name: "invalid date",
+			call: func() error {
+				_, err := client.ListDayEvents(ctx, &pb.ListEventsRequest{Date: "11.02.2026"})
+				return err
+			},
+		},
+		{
+			name: "invalid time zone",
+			call: func() error {
+				_, err := client.ListDayEvents(ctx, &pb.ListEventsRequest{Date: "2026-02-11", Timezone: "Mars/Olympus"})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, codes.InvalidArgument, status.Code(tt.call()))
+		})
+	}
+}
+
+func TestServer_Metrics(t *testing.T) {
+	m := metrics.New()
+	client := newTestClient(t, &bytes.Buffer{}, MetricsInterceptor(m))
+
+	_, err := client.GetEvent(context.Background(), &pb.GetEventRequest{Id: uuid.New().String()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metrics.Path, nil))
+	assert.Contains(t, rec.Body.String(), `calendar_requests_total{code="NotFound",operation="GetEvent",transport="grpc"} 1`)
+}
+
+func TestServer_RequestID(t *testing.T) {
+	var logOutput bytes.Buffer
+	client := newTestClient(t, &logOutput)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), requestIDHeader, "client-id")
+	var header metadata.MD
+	_, err := client.GetEvent(ctx, &pb.GetEventRequest{Id: uuid.New().String()}, grpc.Header(&header))
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	assert.Equal(t, []string{"client-id"}, header.Get(requestIDHeader))
+	assert.Contains(t, logOutput.String(), "method=/calendar.v1.Calendar/GetEvent code=NotFound")
+	assert.Contains(t, logOutput.String(), "request_id=client-id")
+
+	_, err = client.GetEvent(context.Background(), &pb.GetEventRequest{Id: uuid.New().String()}, grpc.Header(&header))
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Len(t, header.Get(requestIDHeader), 1)
+	_, err = uuid.Parse(header.Get(requestIDHeader)[0])
+	require.NoError(t, err)
+}
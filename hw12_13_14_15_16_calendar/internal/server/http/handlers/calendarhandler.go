@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/ical"
+	genhandlers "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/http/handlers/generated"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/http/handlers/mapper"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	calendarContentType = "text/calendar; charset=utf-8"
+	// maxImportSize ограничивает размер импортируемого файла.
+	maxImportSize      = 10 << 20
+	invalidEventCode   = "invalid_event"
+	importFileFormName = "file"
+)
+
+var (
+	errInvalidCalendar = echo.NewHTTPError(http.StatusBadRequest, "invalid iCalendar data")
+	errImportTooLarge  = echo.NewHTTPError(http.StatusRequestEntityTooLarge,
+		fmt.Sprintf("calendar file exceeds %d MiB", maxImportSize>>20))
+)
+
+func (h *EventHandler) ExportCalendar(ctx echo.Context, userID openapi_types.UUID) error {
+	exported, err := h.app.ExportEvents(ctx.Request().Context(), userID.String())
+	if err != nil {
+		return fmt.Errorf("failed to export events: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, exported); err != nil {
+		return fmt.Errorf("failed to encode calendar: %w", err)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="calendar.ics"`)
+	return ctx.Blob(http.StatusOK, calendarContentType, buf.Bytes())
+}
+
+func (h *EventHandler) ImportCalendar(ctx echo.Context, userID openapi_types.UUID) error {
+	// Файл больше лимита отклоняется целиком, а не обрезается
+	req := ctx.Request()
+	req.Body = http.MaxBytesReader(ctx.Response(), req.Body, maxImportSize)
+
+	body, err := importFile(ctx)
+	if err != nil {
+		h.log(ctx).Error("failed to read calendar", "error", err)
+		return importError(err, errInvalidRequestBody)
+	}
+	defer body.Close()
+
+	items, err := ical.Decode(body)
+	if err != nil {
+		h.log(ctx).Error("failed to decode calendar", "error", err)
+		return importError(err, errInvalidCalendar)
+	}
+
+	valid := make([]domain.Event, 0, len(items))
+	for _, item := range items {
+		if item.Err == nil {
+			valid = append(valid, item.Event)
+		}
+	}
+
+	results, err := h.app.ImportEvents(ctx.Request().Context(), userID.String(), valid)
+	if err != nil {
+		return fmt.Errorf("failed to import events: %w", err)
+	}
+	if len(results) != len(valid) {
+		return fmt.Errorf("failed to import events: got %d results for %d events", len(results), len(valid))
+	}
+
+	response := genhandlers.ImportResponse{Items: make([]genhandlers.ImportItem, 0, len(items))}
+	next := 0
+	for _, item := range items {
+		uid, title := item.UID, item.Event.Title
+		reportItem := genhandlers.ImportItem{Uid: &uid, Title: &title}
+
+		switch {
+		case item.Err != nil:
+			reportItem.Error = &genhandlers.ErrorResponse{Code: invalidEventCode, Message: item.Err.Error()}
+		case results[next].Err != nil:
+			_, errResponse := errorResponse(results[next].Err)
+			reportItem.Error = &errResponse
+			next++
+		default:
+			event, err := mapper.DomainToResponse(*results[next].Event)
+			if err != nil {
+				return fmt.Errorf("failed to convert event to response: %w", err)
+			}
+			reportItem.Event = &event
+			next++
+		}
+
+		if reportItem.Error != nil {
+			response.Failed++
+		} else {
+			response.Created++
+		}
+		response.Items = append(response.Items, reportItem)
+	}
+
+	h.log(ctx).Info("calendar imported", "created", response.Created, "failed", response.Failed)
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// importError возвращает 413, если err вызвана превышением maxImportSize, иначе invalid.
+func importError(err error, invalid *echo.HTTPError) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return errImportTooLarge
+	}
+	return invalid
+}
+
+// importFile возвращает импортируемый файл: поле file формы multipart или тело запроса.
+func importFile(ctx echo.Context) (io.ReadCloser, error) {
+	contentType := ctx.Request().Header.Get(echo.HeaderContentType)
+	if !strings.HasPrefix(contentType, echo.MIMEMultipartForm) {
+		return ctx.Request().Body, nil
+	}
+
+	header, err := ctx.FormFile(importFileFormName)
+	if err != nil {
+		return nil, err
+	}
+	return header.Open()
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	genhandlers "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/http/handlers/generated"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const importCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:first@example.com\r\n" +
+	"DTSTART:20260210T100000Z\r\n" +
+	"DTEND:20260210T110000Z\r\n" +
+	"SUMMARY:Team Meeting\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"TRIGGER:-PT15M\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:broken@example.com\r\n" +
+	"SUMMARY:No start\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:busy@example.com\r\n" +
+	"DTSTART:20260210T103000Z\r\n" +
+	"DTEND:20260210T113000Z\r\n" +
+	"SUMMARY:Overlapping\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestEventHandler_ExportCalendar(t *testing.T) {
+	mockApp := new(MockApplication)
+	mockLogger := new(MockLogger)
+	handler := NewEventHandler(mockApp, mockLogger)
+
+	userID := uuid.New()
+	start := time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC)
+	mockApp.On("ExportEvents", mock.Anything, userID.String()).Return([]domain.Event{
+		{
+			ID:         uuid.New().String(),
+			Title:      "Standup",
+			StartDate:  start,
+			EndDate:    start.Add(15 * time.Minute),
+			UserID:     userID.String(),
+			OffsetTime: 5 * time.Minute,
+			RRule:      "FREQ=DAILY",
+		},
+	}, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/users/"+userID.String()+"/calendar.ics", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	require.NoError(t, handler.ExportCalendar(c, userID))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, calendarContentType, rec.Header().Get(echo.HeaderContentType))
+	body := rec.Body.String()
+	assert.Contains(t, body, "BEGIN:VEVENT\r\n")
+	assert.Contains(t, body, "SUMMARY:Standup\r\n")
+	assert.Contains(t, body, "RRULE:FREQ=DAILY\r\n")
+	assert.Contains(t, body, "TRIGGER:-PT5M\r\n")
+	mockApp.AssertExpectations(t)
+}
+
+func TestEventHandler_ImportCalendar(t *testing.T) {
+	userID := uuid.New()
+	created := &domain.Event{
+		ID:         uuid.New().String(),
+		Title:      "Team Meeting",
+		StartDate:  time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC),
+		EndDate:    time.Date(2026, 2, 10, 11, 0, 0, 0, time.UTC),
+		UserID:     userID.String(),
+		OffsetTime: 15 * time.Minute,
+	}
+
+	newRequest := map[string]func(t *testing.T) *http.Request{
+		"raw body": func(_ *testing.T) *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "/users/"+userID.String()+"/import", strings.NewReader(importCalendar))
+			req.Header.Set(echo.HeaderContentType, "text/calendar")
+			return req
+		},
+		"multipart form": func(t *testing.T) *http.Request {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			part, err := writer.CreateFormFile("file", "calendar.ics")
+			require.NoError(t, err)
+			_, err = part.Write([]byte(importCalendar))
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			req := httptest.NewRequest(http.MethodPost, "/users/"+userID.String()+"/import", &body)
+			req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+			return req
+		},
+	}
+
+	for name, build := range newRequest {
+		t.Run(name, func(t *testing.T) {
+			mockApp := new(MockApplication)
+			mockLogger := new(MockLogger)
+			handler := NewEventHandler(mockApp, mockLogger)
+
+			mockApp.On("ImportEvents", mock.Anything, userID.String(), mock.MatchedBy(func(evs []domain.Event) bool {
+				return len(evs) == 2 && evs[0].OffsetTime == 15*time.Minute && evs[1].Title == "Overlapping"
+			})).Return([]app.ImportResult{{Event: created}, {Err: services.ErrDateBusy}}, nil)
+			mockLogger.On("Info", mock.Anything).Return()
+
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(build(t), rec)
+
+			require.NoError(t, handler.ImportCalendar(c, userID))
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var response genhandlers.ImportResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, 1, response.Created)
+			assert.Equal(t, 2, response.Failed)
+			require.Len(t, response.Items, 3)
+
+			assert.Equal(t, "first@example.com", *response.Items[0].Uid)
+			require.NotNil(t, response.Items[0].Event)
+			assert.Equal(t, created.ID, response.Items[0].Event.Id.String())
+
+			require.NotNil(t, response.Items[1].Error)
+			assert.Equal(t, invalidEventCode, response.Items[1].Error.Code)
+
+			require.NotNil(t, response.Items[2].Error)
+			assert.Equal(t, "date_busy", response.Items[2].Error.Code)
+			mockApp.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEventHandler_ImportCalendar_InvalidFile(t *testing.T) {
+	mockApp := new(MockApplication)
+	mockLogger := new(MockLogger)
+	handler := NewEventHandler(mockApp, mockLogger)
+	mockLogger.On("Error", mock.Anything).Return()
+
+	userID := uuid.New()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/users/"+userID.String()+"/import", strings.NewReader("not a calendar"))
+	req.Header.Set(echo.HeaderContentType, "text/calendar")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := handler.ImportCalendar(c, userID)
+	require.Error(t, err)
+	HTTPErrorHandler(mockLogger)(err, c)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var response genhandlers.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "invalid iCalendar data", response.Message)
+	mockApp.AssertNotCalled(t, "ImportEvents", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEventHandler_ImportCalendar_TooLarge(t *testing.T) {
+	padding := "X-PADDING:" + strings.Repeat("a", 1000) + "\r\n"
+	oversized := "BEGIN:VCALENDAR\r\n" + strings.Repeat(padding, maxImportSize/len(padding)+1) + "END:VCALENDAR\r\n"
+
+	tests := []struct {
+		name    string
+		request func(userID uuid.UUID) *http.Request
+	}{
+		{
+			name: "body",
+			request: func(userID uuid.UUID) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/users/"+userID.String()+"/import", strings.NewReader(oversized))
+				req.Header.Set(echo.HeaderContentType, "text/calendar")
+				return req
+			},
+		},
+		{
+			name: "multipart form",
+			request: func(userID uuid.UUID) *http.Request {
+				var body bytes.Buffer
+				writer := multipart.NewWriter(&body)
+				part, err := writer.CreateFormFile("file", "calendar.ics")
+				require.NoError(t, err)
+				_, err = part.Write([]byte(oversized))
+				require.NoError(t, err)
+				require.NoError(t, writer.Close())
+				req := httptest.NewRequest(http.MethodPost, "/users/"+userID.String()+"/import", &body)
+				req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+				return req
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockApp := new(MockApplication)
+			mockLogger := new(MockLogger)
+			handler := NewEventHandler(mockApp, mockLogger)
+			mockLogger.On("Error", mock.Anything).Return()
+
+			userID := uuid.New()
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(tt.request(userID), rec)
+
+			err := handler.ImportCalendar(c, userID)
+			require.Error(t, err)
+			HTTPErrorHandler(mockLogger)(err, c)
+
+			assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+			var response genhandlers.ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, "calendar file exceeds 10 MiB", response.Message)
+			mockApp.AssertNotCalled(t, "ImportEvents", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
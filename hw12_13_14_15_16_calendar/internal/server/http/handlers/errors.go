@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	genhandlers "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/http/handlers/generated"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/services"
+)
+
+const internalErrorMessage = "internal server error"
+
+var errInvalidRequestBody = echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+
+// HTTPErrorHandler - единая точка преобразования ошибок обработчиков в ответ:
+// ошибки сервисов отображаются в 404/409/412/422, ошибки echo - в их статус, остальное - в 500.
+func HTTPErrorHandler(log logger.Logger) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+		log := logger.WithContext(c.Request().Context(), log)
+
+		status, response := errorResponse(err)
+		if status >= http.StatusInternalServerError {
+			log.Error("internal error", "error", err)
+		}
+
+		var writeErr error
+		if c.Request().Method == http.MethodHead {
+			writeErr = c.NoContent(status)
+		} else {
+			writeErr = c.JSON(status, response)
+		}
+		if writeErr != nil {
+			log.Error("failed to write error response", "error", writeErr)
+		}
+	}
+}
+
+func errorResponse(err error) (int, genhandlers.ErrorResponse) {
+	if serviceErr, ok := services.AsError(err); ok {
+		response := genhandlers.ErrorResponse{Code: serviceErr.Code, Message: serviceErr.Message}
+		if serviceErr.Field != "" {
+			response.Field = &serviceErr.Field
+		}
+		return statusForKind(serviceErr.Kind), response
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code, genhandlers.ErrorResponse{
+			Code:    statusCode(httpErr.Code),
+			Message: fmt.Sprint(httpErr.Message),
+		}
+	}
+
+	return http.StatusInternalServerError, genhandlers.ErrorResponse{
+		Code:    statusCode(http.StatusInternalServerError),
+		Message: internalErrorMessage,
+	}
+}
+
+func statusForKind(kind services.ErrorKind) int {
+	switch kind {
+	case services.KindNotFound:
+		return http.StatusNotFound
+	case services.KindConflict:
+		return http.StatusConflict
+	case services.KindPrecondition:
+		return http.StatusPreconditionFailed
+	case services.KindValidation:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// statusCode строит код ошибки из текста HTTP-статуса: 404 -> "not_found".
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	genhandlers "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/http/handlers/generated"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/services"
+)
+
+func TestHTTPErrorHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+		expectedField  string
+		logged         bool
+	}{
+		{
+			name:           "not found",
+			err:            fmt.Errorf("failed to get event: %w", services.ErrEventNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "event_not_found",
+		},
+		{
+			name:           "conflict",
+			err:            services.ErrDateBusy,
+			expectedStatus: http.StatusConflict,
+			expectedCode:   "date_busy",
+		},
+		{
+			name:           "precondition",
+			err:            fmt.Errorf("failed to update event: %w", services.ErrVersionMismatch),
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedCode:   "version_mismatch",
+		},
+		{
+			name:           "validation with field",
+			err:            services.ErrInvalidDateRange,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCode:   "invalid_date_range",
+			expectedField:  "endDate",
+		},
+		{
+			name:           "echo error",
+			err:            echo.NewHTTPError(http.StatusBadRequest, "invalid time zone"),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "bad_request",
+		},
+		{
+			name:           "unknown error is hidden",
+			err:            errors.New("connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   "internal_server_error",
+			logged:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLogger := new(MockLogger)
+			if tt.logged {
+				mockLogger.On("Error", mock.Anything).Return()
+			}
+
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+			HTTPErrorHandler(mockLogger)(tt.err, c)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			var response genhandlers.ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedCode, response.Code)
+			assert.NotContains(t, response.Message, "connection refused")
+			if tt.expectedField != "" {
+				require.NotNil(t, response.Field)
+				assert.Equal(t, tt.expectedField, *response.Field)
+			} else {
+				assert.Nil(t, response.Field)
+			}
+
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLoggingMiddleware_LogsErrorStatus(t *testing.T) {
+	var logOutput bytes.Buffer
+	log := logger.New("INFO", &logOutput)
+
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler(log)
+	e.Use(LoggingMiddleware(log))
+	e.POST("/event", func(echo.Context) error {
+		return services.ErrDateBusy
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/event", nil))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, logOutput.String(), "method=POST path=/event proto=HTTP/1.1 status=409")
+}
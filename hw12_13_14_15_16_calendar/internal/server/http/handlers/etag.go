@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/services"
+)
+
+const headerETag = "ETag"
+
+// HeaderNextCursor - заголовок ответа findEvents с курсором следующей страницы.
+const HeaderNextCursor = "X-Next-Cursor"
+
+var errIfMatchRequired = echo.NewHTTPError(http.StatusPreconditionRequired, "If-Match header is required")
+
+// setETag передает версию события в заголовке ETag.
+func setETag(ctx echo.Context, event domain.Event) {
+	ctx.Response().Header().Set(headerETag, strconv.Quote(strconv.FormatInt(event.Version, 10)))
+}
+
+// ifMatchVersion возвращает версию события из заголовка If-Match; "*" - любая версия (0).
+// Без заголовка изменение запрещено (428). ETag, который не может совпасть с версией события
+// (слабый, список или чужой формат), отклоняется как устаревший (412).
+func ifMatchVersion(ifMatch *string) (int64, error) {
+	if ifMatch == nil || strings.TrimSpace(*ifMatch) == "" {
+		return 0, errIfMatchRequired
+	}
+
+	value := strings.TrimSpace(*ifMatch)
+	if value == "*" {
+		return 0, nil
+	}
+
+	unquoted, err := strconv.Unquote(value)
+	if err != nil || !strings.HasPrefix(value, `"`) {
+		return 0, services.ErrVersionMismatch
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, services.ErrVersionMismatch
+	}
+	return version, nil
+}
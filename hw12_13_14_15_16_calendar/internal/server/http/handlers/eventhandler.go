@@ -1,17 +1,24 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
 	genhandlers "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/http/handlers/generated"
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/http/handlers/mapper"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+type findInPeriodFunc func(ctx context.Context, userID string, date time.Time) ([]domain.Event, error)
+
 type EventHandler struct {
 	app    app.Application
 	logger logger.Logger
@@ -24,106 +31,277 @@ func NewEventHandler(app app.Application, log logger.Logger) *EventHandler {
 	}
 }
 
+// log возвращает логгер с идентификатором текущего запроса.
+func (h *EventHandler) log(ctx echo.Context) logger.Logger {
+	return logger.WithContext(ctx.Request().Context(), h.logger)
+}
+
 func (h *EventHandler) CreateEvent(ctx echo.Context) error {
 	var req genhandlers.CreateEventRequest
 	if err := ctx.Bind(&req); err != nil {
-		h.logger.Error("failed to decode request: " + err.Error())
-		return ctx.JSON(http.StatusBadRequest, genhandlers.ErrorResponse{Error: "invalid request body"})
+		h.log(ctx).Error("failed to decode request", "error", err)
+		return errInvalidRequestBody
 	}
 
 	event := mapper.CreateRequestToDomain(req)
 
 	createdEvent, err := h.app.CreateEvent(ctx.Request().Context(), event)
 	if err != nil {
-		h.logger.Error("failed to create event: " + err.Error())
-		return ctx.JSON(http.StatusInternalServerError, genhandlers.ErrorResponse{Error: "internal server error"})
+		return fmt.Errorf("failed to create event: %w", err)
 	}
 
-	h.logger.Info("event created successfully: " + createdEvent.ID)
+	h.log(ctx).Info("event created successfully", "event_id", createdEvent.ID)
 
 	response, err := mapper.DomainToResponse(*createdEvent)
 	if err != nil {
-		h.logger.Error("failed to convert event to response: " + err.Error())
-		return ctx.JSON(http.StatusInternalServerError, genhandlers.ErrorResponse{Error: "internal server error"})
+		return fmt.Errorf("failed to convert event to response: %w", err)
 	}
+	setETag(ctx, *createdEvent)
 	return ctx.JSON(http.StatusCreated, response)
 }
 
 func (h *EventHandler) GetEvent(ctx echo.Context, id openapi_types.UUID) error {
 	event, err := h.app.GetEventByID(ctx.Request().Context(), id.String())
 	if err != nil {
-		h.logger.Error("failed to get event: " + err.Error())
-		return ctx.JSON(http.StatusNotFound, genhandlers.ErrorResponse{Error: "event not found"})
+		return fmt.Errorf("failed to get event: %w", err)
 	}
 
 	response, err := mapper.DomainToResponse(*event)
 	if err != nil {
-		h.logger.Error("failed to convert event to response: " + err.Error())
-		return ctx.JSON(http.StatusInternalServerError, genhandlers.ErrorResponse{Error: "internal server error"})
+		return fmt.Errorf("failed to convert event to response: %w", err)
 	}
+	setETag(ctx, *event)
 	return ctx.JSON(http.StatusOK, response)
 }
 
-func (h *EventHandler) UpdateEvent(ctx echo.Context, id openapi_types.UUID) error {
+// UpdateEvent изменяет событие, только если заголовок If-Match содержит его текущий ETag.
+func (h *EventHandler) UpdateEvent(ctx echo.Context, id openapi_types.UUID, params genhandlers.UpdateEventParams) error {
+	version, err := ifMatchVersion(params.IfMatch)
+	if err != nil {
+		return err
+	}
+
 	var req genhandlers.UpdateEventRequest
 	if err := ctx.Bind(&req); err != nil {
-		h.logger.Error("failed to decode request: " + err.Error())
-		return ctx.JSON(http.StatusBadRequest, genhandlers.ErrorResponse{Error: "invalid request body"})
+		h.log(ctx).Error("failed to decode request", "error", err)
+		return errInvalidRequestBody
 	}
 
 	event := mapper.UpdateRequestToDomain(req, id.String())
+	event.Version = version
 
 	updatedEvent, err := h.app.UpdateEvent(ctx.Request().Context(), id.String(), event)
 	if err != nil {
-		h.logger.Error("failed to update event: " + err.Error())
-		if err.Error() == "event not found" {
-			return ctx.JSON(http.StatusNotFound, genhandlers.ErrorResponse{Error: "event not found"})
-		}
-		return ctx.JSON(http.StatusInternalServerError, genhandlers.ErrorResponse{Error: err.Error()})
+		return fmt.Errorf("failed to update event: %w", err)
 	}
 
-	h.logger.Info("event updated successfully: " + id.String())
+	h.log(ctx).Info("event updated successfully", "event_id", id.String())
 
 	response, err := mapper.DomainToResponse(*updatedEvent)
 	if err != nil {
-		h.logger.Error("failed to convert event to response: " + err.Error())
-		return ctx.JSON(http.StatusInternalServerError, genhandlers.ErrorResponse{Error: "internal server error"})
+		return fmt.Errorf("failed to convert event to response: %w", err)
 	}
 
+	setETag(ctx, *updatedEvent)
 	return ctx.JSON(http.StatusOK, response)
 }
 
-func (h *EventHandler) DeleteEvent(ctx echo.Context, id openapi_types.UUID) error {
-	if err := h.app.DeleteEvent(ctx.Request().Context(), id.String()); err != nil {
-		h.logger.Error("failed to delete event: " + err.Error())
+// PatchEvent частично изменяет событие по JSON Merge Patch из тела запроса, только если
+// заголовок If-Match содержит текущий ETag события.
+func (h *EventHandler) PatchEvent(ctx echo.Context, id openapi_types.UUID, params genhandlers.PatchEventParams) error {
+	version, err := ifMatchVersion(params.IfMatch)
+	if err != nil {
+		return err
+	}
 
-		if err.Error() == "event not found" {
-			return ctx.JSON(http.StatusNotFound, genhandlers.ErrorResponse{Error: "event not found"})
-		}
-		return ctx.JSON(http.StatusInternalServerError, genhandlers.ErrorResponse{Error: err.Error()})
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		h.log(ctx).Error("failed to read request", "error", err)
+		return errInvalidRequestBody
+	}
+	patch, err := mapper.PatchRequestToDomain(body)
+	if err != nil {
+		h.log(ctx).Error("failed to decode request", "error", err)
+		return errInvalidRequestBody
+	}
+
+	patchedEvent, err := h.app.PatchEvent(ctx.Request().Context(), id.String(), patch, version)
+	if err != nil {
+		return fmt.Errorf("failed to patch event: %w", err)
+	}
+
+	h.log(ctx).Info("event patched successfully", "event_id", id.String())
+
+	response, err := mapper.DomainToResponse(*patchedEvent)
+	if err != nil {
+		return fmt.Errorf("failed to convert event to response: %w", err)
+	}
+
+	setETag(ctx, *patchedEvent)
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteEvent удаляет событие, только если заголовок If-Match содержит его текущий ETag.
+func (h *EventHandler) DeleteEvent(ctx echo.Context, id openapi_types.UUID, params genhandlers.DeleteEventParams) error {
+	version, err := ifMatchVersion(params.IfMatch)
+	if err != nil {
+		return err
 	}
 
-	h.logger.Info("event deleted successfully: " + id.String())
+	if err := h.app.DeleteEvent(ctx.Request().Context(), id.String(), version); err != nil {
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+
+	h.log(ctx).Info("event deleted successfully", "event_id", id.String())
 	return ctx.NoContent(http.StatusNoContent)
 }
 
+func (h *EventHandler) RestoreEvent(ctx echo.Context, id openapi_types.UUID) error {
+	restored, err := h.app.RestoreEvent(ctx.Request().Context(), id.String())
+	if err != nil {
+		return fmt.Errorf("failed to restore event: %w", err)
+	}
+
+	response, err := mapper.DomainToResponse(*restored)
+	if err != nil {
+		return fmt.Errorf("failed to convert event to response: %w", err)
+	}
+
+	h.log(ctx).Info("event restored successfully", "event_id", id.String())
+	setETag(ctx, *restored)
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (h *EventHandler) ListTrash(ctx echo.Context, params genhandlers.ListTrashParams) error {
+	var userID string
+	if params.UserId != nil {
+		userID = params.UserId.String()
+	}
+
+	trashed, err := h.app.FindTrash(ctx.Request().Context(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to find trashed events: %w", err)
+	}
+
+	response, err := mapper.DomainSliceToResponse(trashed)
+	if err != nil {
+		return fmt.Errorf("failed to convert events to response: %w", err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
 func (h *EventHandler) FindEvents(ctx echo.Context, params genhandlers.FindEventsParams) error {
 	var userID string
 	if params.UserId != nil {
 		userID = params.UserId.String()
 	}
 
-	findedEvents, err := h.app.FindEvent(ctx.Request().Context(), userID, params.StartFrom, params.StartTo, params.EndFrom, params.EndTo)
+	page, err := findEventsPage(params)
+	if err != nil {
+		h.log(ctx).Error("invalid page parameters", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	findedEvents, next, err := h.app.FindEvent(ctx.Request().Context(), userID, params.StartFrom, params.StartTo, params.EndFrom, params.EndTo, page)
 	if err != nil {
-		h.logger.Error("failed to find events: " + err.Error())
-		return ctx.JSON(http.StatusInternalServerError, genhandlers.ErrorResponse{Error: err.Error()})
+		return fmt.Errorf("failed to find events: %w", err)
 	}
 
 	response, err := mapper.DomainSliceToResponse(findedEvents)
 	if err != nil {
-		h.logger.Error("failed to convert events to response: " + err.Error())
-		return ctx.JSON(http.StatusInternalServerError, genhandlers.ErrorResponse{Error: "internal server error"})
+		return fmt.Errorf("failed to convert events to response: %w", err)
+	}
+
+	if next != nil {
+		ctx.Response().Header().Set(HeaderNextCursor, next.Encode())
 	}
 
 	return ctx.JSON(http.StatusOK, response)
 }
+
+func findEventsPage(params genhandlers.FindEventsParams) (domain.Page, error) {
+	var limit int
+	if params.Limit != nil {
+		// Явно переданный 0 - ошибка, а не размер страницы по умолчанию
+		if *params.Limit == 0 {
+			return domain.Page{}, domain.ErrInvalidPageLimit
+		}
+		limit = *params.Limit
+	}
+
+	var cursor string
+	if params.Cursor != nil {
+		cursor = *params.Cursor
+	}
+
+	descending := false
+	if params.Sort != nil {
+		switch *params.Sort {
+		case genhandlers.Asc:
+		case genhandlers.Desc:
+			descending = true
+		default:
+			return domain.Page{}, fmt.Errorf("invalid sort %q, use asc or desc", *params.Sort)
+		}
+	}
+
+	return domain.NewPage(limit, cursor, descending)
+}
+
+func (h *EventHandler) ListDayEvents(ctx echo.Context, date openapi_types.Date, params genhandlers.ListDayEventsParams) error {
+	return h.listPeriodEvents(ctx, date, params.UserId, params.Tz, params.XTimezone, h.app.FindDayEvents)
+}
+
+func (h *EventHandler) ListWeekEvents(ctx echo.Context, date openapi_types.Date, params genhandlers.ListWeekEventsParams) error {
+	return h.listPeriodEvents(ctx, date, params.UserId, params.Tz, params.XTimezone, h.app.FindWeekEvents)
+}
+
+func (h *EventHandler) ListMonthEvents(ctx echo.Context, date openapi_types.Date, params genhandlers.ListMonthEventsParams) error {
+	return h.listPeriodEvents(ctx, date, params.UserId, params.Tz, params.XTimezone, h.app.FindMonthEvents)
+}
+
+func (h *EventHandler) listPeriodEvents(
+	ctx echo.Context,
+	date openapi_types.Date,
+	userIDParam *openapi_types.UUID,
+	tz, tzHeader *string,
+	find findInPeriodFunc,
+) error {
+	loc, err := userLocation(tz, tzHeader)
+	if err != nil {
+		h.log(ctx).Error("failed to load time zone", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid time zone")
+	}
+
+	var userID string
+	if userIDParam != nil {
+		userID = userIDParam.String()
+	}
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	findedEvents, err := find(ctx.Request().Context(), userID, day)
+	if err != nil {
+		return fmt.Errorf("failed to find events: %w", err)
+	}
+
+	response, err := mapper.DomainSliceToResponse(findedEvents)
+	if err != nil {
+		return fmt.Errorf("failed to convert events to response: %w", err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// userLocation возвращает часовой пояс пользователя: параметр запроса tz имеет приоритет
+// над заголовком X-Timezone, по умолчанию используется UTC.
+func userLocation(tz, tzHeader *string) (*time.Location, error) {
+	switch {
+	case tz != nil && *tz != "":
+		return time.LoadLocation(*tz)
+	case tzHeader != nil && *tzHeader != "":
+		return time.LoadLocation(*tzHeader)
+	default:
+		return time.UTC, nil
+	}
+}
@@ -11,8 +11,10 @@ import (
 
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
 	genhandlers "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/http/handlers/generated"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/services"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -85,13 +87,14 @@ func TestEventHandler_CreateEvent_InvalidRequest(t *testing.T) {
 
 	err := handler.CreateEvent(c)
 
-	require.NoError(t, err)
+	require.Error(t, err)
+	HTTPErrorHandler(mockLogger)(err, c)
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 
 	var response genhandlers.ErrorResponse
 	err = json.Unmarshal(rec.Body.Bytes(), &response)
 	require.NoError(t, err)
-	assert.Equal(t, "invalid request body", response.Error)
+	assert.Equal(t, "invalid request body", response.Message)
 
 	mockLogger.AssertExpectations(t)
 }
@@ -120,13 +123,14 @@ func TestEventHandler_CreateEvent_ServiceError(t *testing.T) {
 
 	err := handler.CreateEvent(c)
 
-	require.NoError(t, err)
+	require.Error(t, err)
+	HTTPErrorHandler(mockLogger)(err, c)
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
 
 	var response genhandlers.ErrorResponse
 	err = json.Unmarshal(rec.Body.Bytes(), &response)
 	require.NoError(t, err)
-	assert.Equal(t, "internal server error", response.Error)
+	assert.Equal(t, "internal server error", response.Message)
 
 	mockApp.AssertExpectations(t)
 	mockLogger.AssertExpectations(t)
@@ -148,6 +152,7 @@ func TestEventHandler_GetEvent_Success(t *testing.T) {
 		Description: "Test Description",
 		UserID:      userID.String(),
 		OffsetTime:  0,
+		Version:     3,
 	}
 
 	mockApp.On("GetEventByID", mock.Anything, eventID.String()).Return(event, nil)
@@ -161,6 +166,7 @@ func TestEventHandler_GetEvent_Success(t *testing.T) {
 
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
 
 	var response genhandlers.Event
 	err = json.Unmarshal(rec.Body.Bytes(), &response)
@@ -178,8 +184,7 @@ func TestEventHandler_GetEvent_NotFound(t *testing.T) {
 
 	eventID := uuid.New()
 
-	mockApp.On("GetEventByID", mock.Anything, eventID.String()).Return(nil, errors.New("event not found"))
-	mockLogger.On("Error", mock.Anything).Return()
+	mockApp.On("GetEventByID", mock.Anything, eventID.String()).Return(nil, services.ErrEventNotFound)
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/event/"+eventID.String(), nil)
@@ -188,13 +193,14 @@ func TestEventHandler_GetEvent_NotFound(t *testing.T) {
 
 	err := handler.GetEvent(c, eventID)
 
-	require.NoError(t, err)
+	require.Error(t, err)
+	HTTPErrorHandler(mockLogger)(err, c)
 	assert.Equal(t, http.StatusNotFound, rec.Code)
 
 	var response genhandlers.ErrorResponse
 	err = json.Unmarshal(rec.Body.Bytes(), &response)
 	require.NoError(t, err)
-	assert.Equal(t, "event not found", response.Error)
+	assert.Equal(t, "event not found", response.Message)
 
 	mockApp.AssertExpectations(t)
 	mockLogger.AssertExpectations(t)
@@ -216,9 +222,12 @@ func TestEventHandler_UpdateEvent_Success(t *testing.T) {
 		Description: "Updated Description",
 		UserID:      userID.String(),
 		OffsetTime:  30 * time.Minute,
+		Version:     2,
 	}
 
-	mockApp.On("UpdateEvent", mock.Anything, eventID.String(), mock.Anything).Return(updatedEvent, nil)
+	mockApp.On("UpdateEvent", mock.Anything, eventID.String(), mock.MatchedBy(func(event domain.Event) bool {
+		return event.Version == 1
+	})).Return(updatedEvent, nil)
 	mockLogger.On("Info", mock.Anything).Return()
 
 	e := echo.New()
@@ -235,10 +244,11 @@ func TestEventHandler_UpdateEvent_Success(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	err := handler.UpdateEvent(c, eventID)
+	err := handler.UpdateEvent(c, eventID, genhandlers.UpdateEventParams{IfMatch: ifMatch(`"1"`)})
 
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
 
 	var response genhandlers.Event
 	err = json.Unmarshal(rec.Body.Bytes(), &response)
@@ -258,8 +268,7 @@ func TestEventHandler_UpdateEvent_NotFound(t *testing.T) {
 	eventID := uuid.New()
 	userID := uuid.New()
 
-	mockApp.On("UpdateEvent", mock.Anything, eventID.String(), mock.Anything).Return(nil, errors.New("event not found"))
-	mockLogger.On("Error", mock.Anything).Return()
+	mockApp.On("UpdateEvent", mock.Anything, eventID.String(), mock.Anything).Return(nil, services.ErrEventNotFound)
 
 	e := echo.New()
 	reqBody := `{
@@ -273,15 +282,16 @@ func TestEventHandler_UpdateEvent_NotFound(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	err := handler.UpdateEvent(c, eventID)
+	err := handler.UpdateEvent(c, eventID, genhandlers.UpdateEventParams{IfMatch: ifMatch(`"1"`)})
 
-	require.NoError(t, err)
+	require.Error(t, err)
+	HTTPErrorHandler(mockLogger)(err, c)
 	assert.Equal(t, http.StatusNotFound, rec.Code)
 
 	var response genhandlers.ErrorResponse
 	err = json.Unmarshal(rec.Body.Bytes(), &response)
 	require.NoError(t, err)
-	assert.Equal(t, "event not found", response.Error)
+	assert.Equal(t, "event not found", response.Message)
 
 	mockApp.AssertExpectations(t)
 	mockLogger.AssertExpectations(t)
@@ -302,15 +312,16 @@ func TestEventHandler_UpdateEvent_InvalidRequest(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	err := handler.UpdateEvent(c, eventID)
+	err := handler.UpdateEvent(c, eventID, genhandlers.UpdateEventParams{IfMatch: ifMatch(`"1"`)})
 
-	require.NoError(t, err)
+	require.Error(t, err)
+	HTTPErrorHandler(mockLogger)(err, c)
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 
 	var response genhandlers.ErrorResponse
 	err = json.Unmarshal(rec.Body.Bytes(), &response)
 	require.NoError(t, err)
-	assert.Equal(t, "invalid request body", response.Error)
+	assert.Equal(t, "invalid request body", response.Message)
 
 	mockLogger.AssertExpectations(t)
 }
@@ -322,7 +333,7 @@ func TestEventHandler_DeleteEvent_Success(t *testing.T) {
 
 	eventID := uuid.New()
 
-	mockApp.On("DeleteEvent", mock.Anything, eventID.String()).Return(nil)
+	mockApp.On("DeleteEvent", mock.Anything, eventID.String(), int64(1)).Return(nil)
 	mockLogger.On("Info", mock.Anything).Return()
 
 	e := echo.New()
@@ -330,7 +341,7 @@ func TestEventHandler_DeleteEvent_Success(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	err := handler.DeleteEvent(c, eventID)
+	err := handler.DeleteEvent(c, eventID, genhandlers.DeleteEventParams{IfMatch: ifMatch(`"1"`)})
 
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusNoContent, rec.Code)
@@ -346,28 +357,350 @@ func TestEventHandler_DeleteEvent_NotFound(t *testing.T) {
 
 	eventID := uuid.New()
 
-	mockApp.On("DeleteEvent", mock.Anything, eventID.String()).Return(errors.New("event not found"))
-	mockLogger.On("Error", mock.Anything).Return()
+	mockApp.On("DeleteEvent", mock.Anything, eventID.String(), int64(1)).Return(services.ErrEventNotFound)
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodDelete, "/event/"+eventID.String(), nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	err := handler.DeleteEvent(c, eventID)
+	err := handler.DeleteEvent(c, eventID, genhandlers.DeleteEventParams{IfMatch: ifMatch(`"1"`)})
 
-	require.NoError(t, err)
+	require.Error(t, err)
+	HTTPErrorHandler(mockLogger)(err, c)
 	assert.Equal(t, http.StatusNotFound, rec.Code)
 
 	var response genhandlers.ErrorResponse
 	err = json.Unmarshal(rec.Body.Bytes(), &response)
 	require.NoError(t, err)
-	assert.Equal(t, "event not found", response.Error)
+	assert.Equal(t, "event not found", response.Message)
 
 	mockApp.AssertExpectations(t)
 	mockLogger.AssertExpectations(t)
 }
 
+func ifMatch(etag string) *string {
+	return &etag
+}
+
+func TestEventHandler_UpdateEvent_Preconditions(t *testing.T) {
+	eventID := uuid.New()
+	userID := uuid.New().String()
+	reqBody := `{
+		"title": "Test Event",
+		"startDate": "2024-01-01T10:00:00Z",
+		"endDate": "2024-01-01T11:00:00Z",
+		"userId": "` + userID + `"
+	}`
+
+	tests := []struct {
+		name           string
+		ifMatch        *string
+		serviceErr     error
+		wantVersion    int64
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "missing If-Match", expectedStatus: http.StatusPreconditionRequired, expectedCode: "precondition_required"},
+		{name: "empty If-Match", ifMatch: ifMatch(""), expectedStatus: http.StatusPreconditionRequired, expectedCode: "precondition_required"},
+		{name: "weak ETag", ifMatch: ifMatch(`W/"1"`), expectedStatus: http.StatusPreconditionFailed, expectedCode: "version_mismatch"},
+		{name: "malformed ETag", ifMatch: ifMatch(`"abc"`), expectedStatus: http.StatusPreconditionFailed, expectedCode: "version_mismatch"},
+		{
+			name:           "stale ETag",
+			ifMatch:        ifMatch(`"1"`),
+			serviceErr:     services.ErrVersionMismatch,
+			wantVersion:    1,
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedCode:   "version_mismatch",
+		},
+		{name: "any version", ifMatch: ifMatch("*"), wantVersion: 0, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockApp := new(MockApplication)
+			mockLogger := new(MockLogger)
+			mockLogger.On("Info", mock.Anything).Return().Maybe()
+			handler := NewEventHandler(mockApp, mockLogger)
+
+			callsService := tt.serviceErr != nil || tt.expectedStatus == http.StatusOK
+			if callsService {
+				var updated *domain.Event
+				if tt.serviceErr == nil {
+					updated = &domain.Event{ID: eventID.String(), Title: "Test Event", UserID: userID, Version: 5}
+				}
+				mockApp.On("UpdateEvent", mock.Anything, eventID.String(), mock.MatchedBy(func(event domain.Event) bool {
+					return event.Version == tt.wantVersion
+				})).Return(updated, tt.serviceErr)
+			}
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, "/event/"+eventID.String(), strings.NewReader(reqBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if err := handler.UpdateEvent(c, eventID, genhandlers.UpdateEventParams{IfMatch: tt.ifMatch}); err != nil {
+				HTTPErrorHandler(mockLogger)(err, c)
+			}
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedCode != "" {
+				var response genhandlers.ErrorResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedCode, response.Code)
+			} else {
+				assert.Equal(t, `"5"`, rec.Header().Get("ETag"))
+			}
+
+			mockApp.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEventHandler_DeleteEvent_Preconditions(t *testing.T) {
+	eventID := uuid.New()
+
+	t.Run("missing If-Match", func(t *testing.T) {
+		mockApp := new(MockApplication)
+		handler := NewEventHandler(mockApp, new(MockLogger))
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/event/"+eventID.String(), nil), rec)
+
+		err := handler.DeleteEvent(c, eventID, genhandlers.DeleteEventParams{})
+		require.Error(t, err)
+		HTTPErrorHandler(new(MockLogger))(err, c)
+		assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+		mockApp.AssertNotCalled(t, "DeleteEvent", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("stale ETag", func(t *testing.T) {
+		mockApp := new(MockApplication)
+		mockApp.On("DeleteEvent", mock.Anything, eventID.String(), int64(2)).Return(services.ErrVersionMismatch)
+		handler := NewEventHandler(mockApp, new(MockLogger))
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/event/"+eventID.String(), nil), rec)
+
+		err := handler.DeleteEvent(c, eventID, genhandlers.DeleteEventParams{IfMatch: ifMatch(`"2"`)})
+		require.Error(t, err)
+		HTTPErrorHandler(new(MockLogger))(err, c)
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+		var response genhandlers.ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "version_mismatch", response.Code)
+		mockApp.AssertExpectations(t)
+	})
+}
+
+func TestEventHandler_PatchEvent(t *testing.T) {
+	eventID := uuid.New()
+	userID := uuid.New()
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	title := "Renamed"
+	var empty string
+	offset := 30 * time.Minute
+	zone := "Europe/Berlin"
+
+	tests := []struct {
+		name           string
+		body           string
+		ifMatch        *string
+		patch          *domain.EventPatch
+		serviceErr     error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "set and reset fields",
+			body:           `{"title": "Renamed", "description": null, "offsetTime": 30}`,
+			ifMatch:        ifMatch(`"4"`),
+			patch:          &domain.EventPatch{Title: &title, Description: &empty, OffsetTime: &offset},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "set time zone",
+			body:           `{"timeZone": "Europe/Berlin"}`,
+			ifMatch:        ifMatch(`"4"`),
+			patch:          &domain.EventPatch{TimeZone: &zone},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "reset owner",
+			body:           `{"userId": null}`,
+			ifMatch:        ifMatch(`"4"`),
+			patch:          &domain.EventPatch{UserID: &empty},
+			serviceErr:     services.ErrInvalidUserID,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCode:   "required",
+		},
+		{
+			name:           "date is busy",
+			body:           `{"startDate": "2024-01-01T09:00:00Z"}`,
+			ifMatch:        ifMatch(`"4"`),
+			patch:          &domain.EventPatch{StartDate: func() *time.Time { t := start.Add(-time.Hour); return &t }()},
+			serviceErr:     services.ErrDateBusy,
+			expectedStatus: http.StatusConflict,
+			expectedCode:   "date_busy",
+		},
+		{name: "not an object", body: `[{"op": "remove", "path": "/title"}]`, ifMatch: ifMatch(`"4"`), expectedStatus: http.StatusBadRequest, expectedCode: "bad_request"},
+		{name: "null patch", body: `null`, ifMatch: ifMatch(`"4"`), expectedStatus: http.StatusBadRequest, expectedCode: "bad_request"},
+		{name: "invalid field type", body: `{"startDate": "tomorrow"}`, ifMatch: ifMatch(`"4"`), expectedStatus: http.StatusBadRequest, expectedCode: "bad_request"},
+		{name: "missing If-Match", body: `{"title": "Renamed"}`, expectedStatus: http.StatusPreconditionRequired, expectedCode: "precondition_required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockApp := new(MockApplication)
+			mockLogger := new(MockLogger)
+			mockLogger.On("Info", mock.Anything).Return().Maybe()
+			mockLogger.On("Error", mock.Anything).Return().Maybe()
+			handler := NewEventHandler(mockApp, mockLogger)
+
+			if tt.patch != nil {
+				var patched *domain.Event
+				if tt.serviceErr == nil {
+					patched = &domain.Event{
+						ID: eventID.String(), Title: title, StartDate: start, EndDate: start.Add(time.Hour),
+						UserID: userID.String(), OffsetTime: offset, Version: 5,
+					}
+				}
+				mockApp.On("PatchEvent", mock.Anything, eventID.String(), *tt.patch, int64(4)).Return(patched, tt.serviceErr)
+			}
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPatch, "/event/"+eventID.String(), strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, "application/merge-patch+json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if err := handler.PatchEvent(c, eventID, genhandlers.PatchEventParams{IfMatch: tt.ifMatch}); err != nil {
+				HTTPErrorHandler(mockLogger)(err, c)
+			}
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedCode != "" {
+				var response genhandlers.ErrorResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedCode, response.Code)
+			} else {
+				assert.Equal(t, `"5"`, rec.Header().Get("ETag"))
+				var response genhandlers.Event
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, title, *response.Title)
+				assert.Equal(t, int64(30), *response.OffsetTime)
+			}
+
+			mockApp.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEventHandler_RestoreEvent(t *testing.T) {
+	eventID := uuid.New()
+	userID := uuid.New()
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "restored", expectedStatus: http.StatusOK},
+		{name: "not in trash", serviceErr: services.ErrEventNotFound, expectedStatus: http.StatusNotFound, expectedCode: "event_not_found"},
+		{name: "date is busy", serviceErr: services.ErrDateBusy, expectedStatus: http.StatusConflict, expectedCode: "date_busy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockApp := new(MockApplication)
+			mockLogger := new(MockLogger)
+			mockLogger.On("Info", mock.Anything).Return().Maybe()
+			mockLogger.On("Error", mock.Anything).Return().Maybe()
+			handler := NewEventHandler(mockApp, mockLogger)
+
+			var restored *domain.Event
+			if tt.serviceErr == nil {
+				restored = &domain.Event{
+					ID: eventID.String(), Title: "Restored", StartDate: start, EndDate: start.Add(time.Hour),
+					UserID: userID.String(), Version: 3,
+				}
+			}
+			mockApp.On("RestoreEvent", mock.Anything, eventID.String()).Return(restored, tt.serviceErr)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/event/"+eventID.String()+"/restore", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if err := handler.RestoreEvent(c, eventID); err != nil {
+				HTTPErrorHandler(mockLogger)(err, c)
+			}
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedCode != "" {
+				var response genhandlers.ErrorResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedCode, response.Code)
+			} else {
+				assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+				var response genhandlers.Event
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, "Restored", *response.Title)
+				assert.Nil(t, response.DeletedAt)
+			}
+
+			mockApp.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEventHandler_ListTrash(t *testing.T) {
+	mockApp := new(MockApplication)
+	mockLogger := new(MockLogger)
+	handler := NewEventHandler(mockApp, mockLogger)
+
+	userID := uuid.New()
+	deletedAt := time.Date(2024, 1, 5, 8, 30, 0, 0, time.UTC)
+
+	trashed := []domain.Event{
+		{
+			ID:        uuid.New().String(),
+			Title:     "Deleted event",
+			StartDate: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+			UserID:    userID.String(),
+			DeletedAt: &deletedAt,
+		},
+	}
+
+	mockApp.On("FindTrash", mock.Anything, userID.String()).Return(trashed, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/trash?userId="+userID.String(), nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := handler.ListTrash(c, genhandlers.ListTrashParams{UserId: &userID})
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response []genhandlers.Event
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	assert.Equal(t, "Deleted event", *response[0].Title)
+	require.NotNil(t, response[0].DeletedAt)
+	assert.True(t, deletedAt.Equal(*response[0].DeletedAt))
+
+	mockApp.AssertExpectations(t)
+}
+
 func TestEventHandler_FindEvents_ByUserID(t *testing.T) {
 	mockApp := new(MockApplication)
 	mockLogger := new(MockLogger)
@@ -394,7 +727,7 @@ func TestEventHandler_FindEvents_ByUserID(t *testing.T) {
 		},
 	}
 
-	mockApp.On("FindEvent", mock.Anything, userID.String(), (*time.Time)(nil), (*time.Time)(nil), (*time.Time)(nil), (*time.Time)(nil)).Return(events, nil)
+	mockApp.On("FindEvent", mock.Anything, userID.String(), (*time.Time)(nil), (*time.Time)(nil), (*time.Time)(nil), (*time.Time)(nil), domain.Page{Limit: domain.DefaultPageLimit}).Return(events, nil, nil)
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/event?userId="+userID.String(), nil)
@@ -416,6 +749,7 @@ func TestEventHandler_FindEvents_ByUserID(t *testing.T) {
 	assert.Len(t, response, 2)
 	assert.Equal(t, "Event 1", *response[0].Title)
 	assert.Equal(t, "Event 2", *response[1].Title)
+	assert.Empty(t, rec.Header().Get(HeaderNextCursor))
 
 	mockApp.AssertExpectations(t)
 }
@@ -440,7 +774,7 @@ func TestEventHandler_FindEvents_ByDateRange(t *testing.T) {
 		},
 	}
 
-	mockApp.On("FindEvent", mock.Anything, userID.String(), &startFrom, &startTo, (*time.Time)(nil), (*time.Time)(nil)).Return(events, nil)
+	mockApp.On("FindEvent", mock.Anything, userID.String(), &startFrom, &startTo, (*time.Time)(nil), (*time.Time)(nil), domain.Page{Limit: domain.DefaultPageLimit}).Return(events, nil, nil)
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/event", nil)
@@ -491,7 +825,7 @@ func TestEventHandler_FindEvents_AllEvents(t *testing.T) {
 		},
 	}
 
-	mockApp.On("FindEvent", mock.Anything, "", (*time.Time)(nil), (*time.Time)(nil), (*time.Time)(nil), (*time.Time)(nil)).Return(events, nil)
+	mockApp.On("FindEvent", mock.Anything, "", (*time.Time)(nil), (*time.Time)(nil), (*time.Time)(nil), (*time.Time)(nil), domain.Page{Limit: domain.DefaultPageLimit}).Return(events, nil, nil)
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/event", nil)
@@ -518,7 +852,7 @@ func TestEventHandler_FindEvents_ServiceError(t *testing.T) {
 	mockLogger := new(MockLogger)
 	handler := NewEventHandler(mockApp, mockLogger)
 
-	mockApp.On("FindEvent", mock.Anything, "", (*time.Time)(nil), (*time.Time)(nil), (*time.Time)(nil), (*time.Time)(nil)).Return(nil, errors.New("database error"))
+	mockApp.On("FindEvent", mock.Anything, "", (*time.Time)(nil), (*time.Time)(nil), (*time.Time)(nil), (*time.Time)(nil), domain.Page{Limit: domain.DefaultPageLimit}).Return(nil, nil, errors.New("database error"))
 	mockLogger.On("Error", mock.Anything).Return()
 
 	e := echo.New()
@@ -530,18 +864,85 @@ func TestEventHandler_FindEvents_ServiceError(t *testing.T) {
 
 	err := handler.FindEvents(c, params)
 
-	require.NoError(t, err)
+	require.Error(t, err)
+	HTTPErrorHandler(mockLogger)(err, c)
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
 
 	var response genhandlers.ErrorResponse
 	err = json.Unmarshal(rec.Body.Bytes(), &response)
 	require.NoError(t, err)
-	assert.Equal(t, "database error", response.Error)
+	assert.Equal(t, "internal_server_error", response.Code)
+	assert.Equal(t, "internal server error", response.Message)
 
 	mockApp.AssertExpectations(t)
 	mockLogger.AssertExpectations(t)
 }
 
+func TestEventHandler_FindEvents_Page(t *testing.T) {
+	mockApp := new(MockApplication)
+	mockLogger := new(MockLogger)
+	handler := NewEventHandler(mockApp, mockLogger)
+
+	start := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	after := domain.Cursor{StartDate: start.Add(24 * time.Hour), ID: uuid.New().String()}
+	found := []domain.Event{{
+		ID: uuid.New().String(), Title: "Event", StartDate: start, EndDate: start.Add(time.Hour), UserID: uuid.New().String(),
+	}}
+	next := domain.CursorOf(found[0])
+
+	page := domain.Page{Limit: 1, After: &after, Descending: true}
+	mockApp.On("FindEvent", mock.Anything, "", (*time.Time)(nil), (*time.Time)(nil), (*time.Time)(nil), (*time.Time)(nil), page).
+		Return(found, &next, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/event", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	limit, cursor, sort := 1, after.Encode(), genhandlers.Desc
+	err := handler.FindEvents(c, genhandlers.FindEventsParams{Limit: &limit, Cursor: &cursor, Sort: &sort})
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response []genhandlers.Event
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response, 1)
+	assert.Equal(t, next.Encode(), rec.Header().Get(HeaderNextCursor))
+
+	mockApp.AssertExpectations(t)
+}
+
+func TestEventHandler_FindEvents_InvalidPage(t *testing.T) {
+	zero, tooBig, badCursor, badSort := 0, domain.MaxPageLimit+1, "not a cursor", genhandlers.FindEventsParamsSort("up")
+	tests := map[string]genhandlers.FindEventsParams{
+		"zero limit":     {Limit: &zero},
+		"too big limit":  {Limit: &tooBig},
+		"invalid cursor": {Cursor: &badCursor},
+		"invalid sort":   {Sort: &badSort},
+	}
+
+	for name, params := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockApp := new(MockApplication)
+			mockLogger := new(MockLogger)
+			handler := NewEventHandler(mockApp, mockLogger)
+			mockLogger.On("Error", mock.Anything).Return()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/event", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.FindEvents(c, params)
+			require.Error(t, err)
+			HTTPErrorHandler(mockLogger)(err, c)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			mockApp.AssertNotCalled(t, "FindEvent")
+		})
+	}
+}
+
 func TestEventHandler_CreateEvent_InvalidUUID(t *testing.T) {
 	mockApp := new(MockApplication)
 	mockLogger := new(MockLogger)
@@ -566,14 +967,131 @@ func TestEventHandler_CreateEvent_InvalidUUID(t *testing.T) {
 
 	err := handler.CreateEvent(c)
 
-	require.NoError(t, err)
+	require.Error(t, err)
+	HTTPErrorHandler(mockLogger)(err, c)
 	// Ошибка парсинга UUID при Bind -> BadRequest
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 
 	var response genhandlers.ErrorResponse
 	err = json.Unmarshal(rec.Body.Bytes(), &response)
 	require.NoError(t, err)
-	assert.Equal(t, "invalid request body", response.Error)
+	assert.Equal(t, "invalid request body", response.Message)
 
 	mockLogger.AssertExpectations(t)
 }
+
+func TestEventHandler_ListPeriodEvents(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	date := openapi_types.Date{Time: time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)}
+	found := []domain.Event{{
+		ID:        uuid.New().String(),
+		Title:     "Team Meeting",
+		StartDate: time.Date(2026, 2, 11, 10, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 2, 11, 11, 0, 0, 0, time.UTC),
+		UserID:    userID.String(),
+	}}
+
+	tests := []struct {
+		name     string
+		method   string
+		call     func(h *EventHandler, c echo.Context, tz, header *string) error
+		tz       *string
+		header   *string
+		expected time.Time
+	}{
+		{
+			name:   "day in UTC by default",
+			method: "FindDayEvents",
+			call: func(h *EventHandler, c echo.Context, tz, header *string) error {
+				return h.ListDayEvents(c, date, genhandlers.ListDayEventsParams{UserId: &userID, Tz: tz, XTimezone: header})
+			},
+			expected: time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "week in time zone from header",
+			method: "FindWeekEvents",
+			call: func(h *EventHandler, c echo.Context, tz, header *string) error {
+				return h.ListWeekEvents(c, date, genhandlers.ListWeekEventsParams{UserId: &userID, Tz: tz, XTimezone: header})
+			},
+			header:   strPtr("Europe/Moscow"),
+			expected: time.Date(2026, 2, 11, 0, 0, 0, 0, moscow),
+		},
+		{
+			name:   "month with query param overriding header",
+			method: "FindMonthEvents",
+			call: func(h *EventHandler, c echo.Context, tz, header *string) error {
+				return h.ListMonthEvents(c, date, genhandlers.ListMonthEventsParams{UserId: &userID, Tz: tz, XTimezone: header})
+			},
+			tz:       strPtr("Asia/Tokyo"),
+			header:   strPtr("Europe/Moscow"),
+			expected: time.Date(2026, 2, 11, 0, 0, 0, 0, tokyo),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockApp := new(MockApplication)
+			mockLogger := new(MockLogger)
+			handler := NewEventHandler(mockApp, mockLogger)
+
+			mockApp.On(tt.method, mock.Anything, userID.String(), mock.MatchedBy(func(d time.Time) bool {
+				return d.Equal(tt.expected) && d.Location().String() == tt.expected.Location().String()
+			})).Return(found, nil)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/events", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := tt.call(handler, c, tt.tz, tt.header)
+
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var response []genhandlers.Event
+			err = json.Unmarshal(rec.Body.Bytes(), &response)
+			require.NoError(t, err)
+			require.Len(t, response, 1)
+			assert.Equal(t, "Team Meeting", *response[0].Title)
+
+			mockApp.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEventHandler_ListDayEvents_InvalidTimezone(t *testing.T) {
+	mockApp := new(MockApplication)
+	mockLogger := new(MockLogger)
+	handler := NewEventHandler(mockApp, mockLogger)
+
+	mockLogger.On("Error", mock.Anything).Return()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/events/day/2026-02-11", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	date := openapi_types.Date{Time: time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)}
+	err := handler.ListDayEvents(c, date, genhandlers.ListDayEventsParams{Tz: strPtr("Mars/Olympus")})
+
+	require.Error(t, err)
+	HTTPErrorHandler(mockLogger)(err, c)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var response genhandlers.ErrorResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, "invalid time zone", response.Message)
+
+	mockApp.AssertNotCalled(t, "FindDayEvents", mock.Anything, mock.Anything, mock.Anything)
+	mockLogger.AssertExpectations(t)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
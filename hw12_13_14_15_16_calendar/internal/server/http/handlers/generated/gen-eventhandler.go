@@ -21,6 +21,12 @@ import (
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+// Defines values for FindEventsParamsSort.
+const (
+	Asc  FindEventsParamsSort = "asc"
+	Desc FindEventsParamsSort = "desc"
+)
+
 // CreateEventRequest defines model for CreateEventRequest.
 type CreateEventRequest struct {
 	// Description Event description
@@ -29,12 +35,22 @@ type CreateEventRequest struct {
 	// EndDate Event end date and time in RFC3339 format
 	EndDate time.Time `json:"endDate"`
 
+	// Exdates Start times of occurrences excluded from the series
+	Exdates *[]time.Time `json:"exdates,omitempty"`
+
 	// OffsetTime Time offset in minutes for notifications or reminders
 	OffsetTime *int64 `json:"offsetTime,omitempty"`
 
+	// Rrule Recurrence rule (RFC 5545 subset): FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY, COUNT, UNTIL.
+	// Empty for a single event. Occurrences are expanded in timeZone (TZID on iCalendar import), otherwise in UTC.
+	Rrule *string `json:"rrule,omitempty"`
+
 	// StartDate Event start date and time in RFC3339 format
 	StartDate time.Time `json:"startDate"`
 
+	// TimeZone IANA time zone of the event, e.g. "Europe/Moscow"; empty for UTC
+	TimeZone *string `json:"timeZone,omitempty"`
+
 	// Title Event title
 	Title string `json:"title"`
 
@@ -44,27 +60,46 @@ type CreateEventRequest struct {
 
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
-	// Error Error message
-	Error string `json:"error"`
+	// Code Machine-readable error code
+	Code string `json:"code"`
+
+	// Field Request field that failed validation
+	Field *string `json:"field,omitempty"`
+
+	// Message Human-readable error message
+	Message string `json:"message"`
 }
 
 // Event defines model for Event.
 type Event struct {
+	// DeletedAt Time the event was moved to the trash, absent for events not in the trash
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+
 	// Description Event description
 	Description *string `json:"description,omitempty"`
 
 	// EndDate Event end date and time
 	EndDate *time.Time `json:"endDate,omitempty"`
 
+	// Exdates Start times of occurrences excluded from the series
+	Exdates *[]time.Time `json:"exdates,omitempty"`
+
 	// Id Unique event identifier
 	Id *openapi_types.UUID `json:"id,omitempty"`
 
 	// OffsetTime Time offset in minutes
 	OffsetTime *int64 `json:"offsetTime,omitempty"`
 
+	// Rrule Recurrence rule (RFC 5545 subset): FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY, COUNT, UNTIL.
+	// Empty for a single event. Occurrences are expanded in timeZone (TZID on iCalendar import), otherwise in UTC.
+	Rrule *string `json:"rrule,omitempty"`
+
 	// StartDate Event start date and time
 	StartDate *time.Time `json:"startDate,omitempty"`
 
+	// TimeZone IANA time zone of the event, absent for UTC
+	TimeZone *string `json:"timeZone,omitempty"`
+
 	// Title Event title
 	Title *string `json:"title,omitempty"`
 
@@ -72,6 +107,61 @@ type Event struct {
 	UserId *openapi_types.UUID `json:"userId,omitempty"`
 }
 
+// ImportItem defines model for ImportItem.
+type ImportItem struct {
+	Error *ErrorResponse `json:"error,omitempty"`
+	Event *Event         `json:"event,omitempty"`
+
+	// Title SUMMARY of the VEVENT
+	Title *string `json:"title,omitempty"`
+
+	// Uid UID of the VEVENT in the imported file
+	Uid *string `json:"uid,omitempty"`
+}
+
+// ImportResponse defines model for ImportResponse.
+type ImportResponse struct {
+	// Created Number of created events
+	Created int `json:"created"`
+
+	// Failed Number of events that were not created
+	Failed int `json:"failed"`
+
+	// Items Result for every VEVENT in file order
+	Items []ImportItem `json:"items"`
+}
+
+// PatchEventRequest JSON Merge Patch (RFC 7396) of the event: omitted fields are kept, null resets a field
+// to its default. Resetting a required field (title, startDate, endDate, userId) fails validation.
+type PatchEventRequest struct {
+	// Description Event description
+	Description *string `json:"description"`
+
+	// EndDate Event end date and time in RFC3339 format
+	EndDate *time.Time `json:"endDate"`
+
+	// Exdates Start times of occurrences excluded from the series
+	Exdates *[]time.Time `json:"exdates"`
+
+	// OffsetTime Time offset in minutes for notifications or reminders
+	OffsetTime *int64 `json:"offsetTime"`
+
+	// Rrule Recurrence rule (RFC 5545 subset), empty or null for a single event
+	Rrule *string `json:"rrule"`
+
+	// StartDate Event start date and time in RFC3339 format
+	StartDate *time.Time `json:"startDate"`
+
+	// TimeZone IANA time zone of the event, null resets it to UTC
+	TimeZone *string `json:"timeZone"`
+
+	// Title Event title
+	Title *string `json:"title"`
+
+	// UserId ID of the user who owns the event
+	UserId *openapi_types.UUID `json:"userId"`
+}
+
 // SuccessResponse defines model for SuccessResponse.
 type SuccessResponse struct {
 	// Data Response data (can be an object, array, or string)
@@ -100,15 +190,25 @@ type UpdateEventRequest struct {
 	// EndDate Event end date and time in RFC3339 format
 	EndDate time.Time `json:"endDate"`
 
+	// Exdates Start times of occurrences excluded from the series
+	Exdates *[]time.Time `json:"exdates,omitempty"`
+
 	// Id Event ID
 	Id openapi_types.UUID `json:"id"`
 
 	// OffsetTime Time offset in minutes for notifications or reminders
 	OffsetTime *int64 `json:"offsetTime,omitempty"`
 
+	// Rrule Recurrence rule (RFC 5545 subset): FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY, COUNT, UNTIL.
+	// Empty for a single event. Occurrences are expanded in timeZone (TZID on iCalendar import), otherwise in UTC.
+	Rrule *string `json:"rrule,omitempty"`
+
 	// StartDate Event start date and time in RFC3339 format
 	StartDate time.Time `json:"startDate"`
 
+	// TimeZone IANA time zone of the event; if omitted, the stored time zone is kept
+	TimeZone *string `json:"timeZone,omitempty"`
+
 	// Title Event title
 	Title string `json:"title"`
 
@@ -116,6 +216,24 @@ type UpdateEventRequest struct {
 	UserId openapi_types.UUID `json:"userId"`
 }
 
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// PeriodDate defines model for PeriodDate.
+type PeriodDate = openapi_types.Date
+
+// TimezoneHeader defines model for TimezoneHeader.
+type TimezoneHeader = string
+
+// TimezoneQuery defines model for TimezoneQuery.
+type TimezoneQuery = string
+
+// UserIdPath defines model for UserIdPath.
+type UserIdPath = openapi_types.UUID
+
+// UserIdQuery defines model for UserIdQuery.
+type UserIdQuery = openapi_types.UUID
+
 // FindEventsParams defines parameters for FindEvents.
 type FindEventsParams struct {
 	// UserId Filter events by user ID
@@ -132,14 +250,97 @@ type FindEventsParams struct {
 
 	// EndTo Filter events ending until this date (RFC3339 format)
 	EndTo *time.Time `form:"endTo,omitempty" json:"endTo,omitempty"`
+
+	// Limit Maximum number of events in the page
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque cursor from the X-Next-Cursor header of the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Sort Sort direction by start date
+	Sort *FindEventsParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+}
+
+// FindEventsParamsSort defines parameters for FindEvents.
+type FindEventsParamsSort string
+
+// DeleteEventParams defines parameters for DeleteEvent.
+type DeleteEventParams struct {
+	// IfMatch ETag of the event from the last read or update. The header is required: requests without it are rejected with 428, requests with a stale ETag - with 412. "*" matches any version of an existing event
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PatchEventParams defines parameters for PatchEvent.
+type PatchEventParams struct {
+	// IfMatch ETag of the event from the last read or update. The header is required: requests without it are rejected with 428, requests with a stale ETag - with 412. "*" matches any version of an existing event
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// UpdateEventParams defines parameters for UpdateEvent.
+type UpdateEventParams struct {
+	// IfMatch ETag of the event from the last read or update. The header is required: requests without it are rejected with 428, requests with a stale ETag - with 412. "*" matches any version of an existing event
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// ListDayEventsParams defines parameters for ListDayEvents.
+type ListDayEventsParams struct {
+	// UserId Filter events by user ID
+	UserId *UserIdQuery `form:"userId,omitempty" json:"userId,omitempty"`
+
+	// Tz IANA time zone of the user, takes precedence over the X-Timezone header (default UTC)
+	Tz *TimezoneQuery `form:"tz,omitempty" json:"tz,omitempty"`
+
+	// XTimezone IANA time zone of the user (default UTC)
+	XTimezone *TimezoneHeader `json:"X-Timezone,omitempty"`
+}
+
+// ListMonthEventsParams defines parameters for ListMonthEvents.
+type ListMonthEventsParams struct {
+	// UserId Filter events by user ID
+	UserId *UserIdQuery `form:"userId,omitempty" json:"userId,omitempty"`
+
+	// Tz IANA time zone of the user, takes precedence over the X-Timezone header (default UTC)
+	Tz *TimezoneQuery `form:"tz,omitempty" json:"tz,omitempty"`
+
+	// XTimezone IANA time zone of the user (default UTC)
+	XTimezone *TimezoneHeader `json:"X-Timezone,omitempty"`
+}
+
+// ListWeekEventsParams defines parameters for ListWeekEvents.
+type ListWeekEventsParams struct {
+	// UserId Filter events by user ID
+	UserId *UserIdQuery `form:"userId,omitempty" json:"userId,omitempty"`
+
+	// Tz IANA time zone of the user, takes precedence over the X-Timezone header (default UTC)
+	Tz *TimezoneQuery `form:"tz,omitempty" json:"tz,omitempty"`
+
+	// XTimezone IANA time zone of the user (default UTC)
+	XTimezone *TimezoneHeader `json:"X-Timezone,omitempty"`
+}
+
+// ListTrashParams defines parameters for ListTrash.
+type ListTrashParams struct {
+	// UserId Filter events by user ID
+	UserId *UserIdQuery `form:"userId,omitempty" json:"userId,omitempty"`
+}
+
+// ImportCalendarMultipartBody defines parameters for ImportCalendar.
+type ImportCalendarMultipartBody struct {
+	File *openapi_types.File `json:"file,omitempty"`
 }
 
 // CreateEventJSONRequestBody defines body for CreateEvent for application/json ContentType.
 type CreateEventJSONRequestBody = CreateEventRequest
 
+// PatchEventApplicationMergePatchPlusJSONRequestBody defines body for PatchEvent for application/merge-patch+json ContentType.
+type PatchEventApplicationMergePatchPlusJSONRequestBody = PatchEventRequest
+
 // UpdateEventJSONRequestBody defines body for UpdateEvent for application/json ContentType.
 type UpdateEventJSONRequestBody = UpdateEventRequest
 
+// ImportCalendarMultipartRequestBody defines body for ImportCalendar for multipart/form-data ContentType.
+type ImportCalendarMultipartRequestBody ImportCalendarMultipartBody
+
 // AsEvent returns the union data inside the SuccessResponse_Data as a Event
 func (t SuccessResponse_Data) AsEvent() (Event, error) {
 	var body Event
@@ -238,13 +439,37 @@ type ServerInterface interface {
 	CreateEvent(ctx echo.Context) error
 	// Delete an event
 	// (DELETE /event/{id})
-	DeleteEvent(ctx echo.Context, id openapi_types.UUID) error
+	DeleteEvent(ctx echo.Context, id openapi_types.UUID, params DeleteEventParams) error
 	// Get event by ID
 	// (GET /event/{id})
 	GetEvent(ctx echo.Context, id openapi_types.UUID) error
+	// Partially update an event
+	// (PATCH /event/{id})
+	PatchEvent(ctx echo.Context, id openapi_types.UUID, params PatchEventParams) error
 	// Update an event
 	// (PUT /event/{id})
-	UpdateEvent(ctx echo.Context, id openapi_types.UUID) error
+	UpdateEvent(ctx echo.Context, id openapi_types.UUID, params UpdateEventParams) error
+	// Restore an event from the trash
+	// (POST /event/{id}/restore)
+	RestoreEvent(ctx echo.Context, id openapi_types.UUID) error
+	// List events for a day
+	// (GET /events/day/{date})
+	ListDayEvents(ctx echo.Context, date PeriodDate, params ListDayEventsParams) error
+	// List events for a month
+	// (GET /events/month/{date})
+	ListMonthEvents(ctx echo.Context, date PeriodDate, params ListMonthEventsParams) error
+	// List events for a week
+	// (GET /events/week/{date})
+	ListWeekEvents(ctx echo.Context, date PeriodDate, params ListWeekEventsParams) error
+	// List deleted events
+	// (GET /trash)
+	ListTrash(ctx echo.Context, params ListTrashParams) error
+	// Export user calendar
+	// (GET /users/{userId}/calendar.ics)
+	ExportCalendar(ctx echo.Context, userId UserIdPath) error
+	// Import events from iCalendar
+	// (POST /users/{userId}/import)
+	ImportCalendar(ctx echo.Context, userId UserIdPath) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -293,6 +518,27 @@ func (w *ServerInterfaceWrapper) FindEvents(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter endTo: %s", err))
 	}
 
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.FindEvents(ctx, params)
 	return err
@@ -318,8 +564,28 @@ func (w *ServerInterfaceWrapper) DeleteEvent(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteEventParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.DeleteEvent(ctx, id)
+	err = w.Handler.DeleteEvent(ctx, id, params)
 	return err
 }
 
@@ -339,6 +605,42 @@ func (w *ServerInterfaceWrapper) GetEvent(ctx echo.Context) error {
 	return err
 }
 
+// PatchEvent converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchEvent(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchEventParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PatchEvent(ctx, id, params)
+	return err
+}
+
 // UpdateEvent converts echo context to params.
 func (w *ServerInterfaceWrapper) UpdateEvent(ctx echo.Context) error {
 	var err error
@@ -350,8 +652,241 @@ func (w *ServerInterfaceWrapper) UpdateEvent(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpdateEventParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpdateEvent(ctx, id, params)
+	return err
+}
+
+// RestoreEvent converts echo context to params.
+func (w *ServerInterfaceWrapper) RestoreEvent(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RestoreEvent(ctx, id)
+	return err
+}
+
+// ListDayEvents converts echo context to params.
+func (w *ServerInterfaceWrapper) ListDayEvents(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "date" -------------
+	var date PeriodDate
+
+	err = runtime.BindStyledParameterWithOptions("simple", "date", ctx.Param("date"), &date, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter date: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListDayEventsParams
+	// ------------- Optional query parameter "userId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "userId", ctx.QueryParams(), &params.UserId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	// ------------- Optional query parameter "tz" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tz", ctx.QueryParams(), &params.Tz)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tz: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "X-Timezone" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Timezone")]; found {
+		var XTimezone TimezoneHeader
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Timezone, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Timezone", valueList[0], &XTimezone, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Timezone: %s", err))
+		}
+
+		params.XTimezone = &XTimezone
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListDayEvents(ctx, date, params)
+	return err
+}
+
+// ListMonthEvents converts echo context to params.
+func (w *ServerInterfaceWrapper) ListMonthEvents(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "date" -------------
+	var date PeriodDate
+
+	err = runtime.BindStyledParameterWithOptions("simple", "date", ctx.Param("date"), &date, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter date: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListMonthEventsParams
+	// ------------- Optional query parameter "userId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "userId", ctx.QueryParams(), &params.UserId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	// ------------- Optional query parameter "tz" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tz", ctx.QueryParams(), &params.Tz)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tz: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "X-Timezone" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Timezone")]; found {
+		var XTimezone TimezoneHeader
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Timezone, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Timezone", valueList[0], &XTimezone, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Timezone: %s", err))
+		}
+
+		params.XTimezone = &XTimezone
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListMonthEvents(ctx, date, params)
+	return err
+}
+
+// ListWeekEvents converts echo context to params.
+func (w *ServerInterfaceWrapper) ListWeekEvents(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "date" -------------
+	var date PeriodDate
+
+	err = runtime.BindStyledParameterWithOptions("simple", "date", ctx.Param("date"), &date, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter date: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListWeekEventsParams
+	// ------------- Optional query parameter "userId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "userId", ctx.QueryParams(), &params.UserId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	// ------------- Optional query parameter "tz" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tz", ctx.QueryParams(), &params.Tz)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tz: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "X-Timezone" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Timezone")]; found {
+		var XTimezone TimezoneHeader
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Timezone, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Timezone", valueList[0], &XTimezone, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Timezone: %s", err))
+		}
+
+		params.XTimezone = &XTimezone
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListWeekEvents(ctx, date, params)
+	return err
+}
+
+// ListTrash converts echo context to params.
+func (w *ServerInterfaceWrapper) ListTrash(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListTrashParams
+	// ------------- Optional query parameter "userId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "userId", ctx.QueryParams(), &params.UserId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListTrash(ctx, params)
+	return err
+}
+
+// ExportCalendar converts echo context to params.
+func (w *ServerInterfaceWrapper) ExportCalendar(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "userId" -------------
+	var userId UserIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userId", ctx.Param("userId"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ExportCalendar(ctx, userId)
+	return err
+}
+
+// ImportCalendar converts echo context to params.
+func (w *ServerInterfaceWrapper) ImportCalendar(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "userId" -------------
+	var userId UserIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userId", ctx.Param("userId"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.UpdateEvent(ctx, id)
+	err = w.Handler.ImportCalendar(ctx, userId)
 	return err
 }
 
@@ -387,42 +922,98 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.POST(baseURL+"/event", wrapper.CreateEvent)
 	router.DELETE(baseURL+"/event/:id", wrapper.DeleteEvent)
 	router.GET(baseURL+"/event/:id", wrapper.GetEvent)
+	router.PATCH(baseURL+"/event/:id", wrapper.PatchEvent)
 	router.PUT(baseURL+"/event/:id", wrapper.UpdateEvent)
+	router.POST(baseURL+"/event/:id/restore", wrapper.RestoreEvent)
+	router.GET(baseURL+"/events/day/:date", wrapper.ListDayEvents)
+	router.GET(baseURL+"/events/month/:date", wrapper.ListMonthEvents)
+	router.GET(baseURL+"/events/week/:date", wrapper.ListWeekEvents)
+	router.GET(baseURL+"/trash", wrapper.ListTrash)
+	router.GET(baseURL+"/users/:userId/calendar.ics", wrapper.ExportCalendar)
+	router.POST(baseURL+"/users/:userId/import", wrapper.ImportCalendar)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xaX2/jNhL/KgTvHnYBKZZs2Yn1dL1NUgRo0UXq4oArggMtjm32JFJLUk6NwN/9QFKy",
-	"JUv22llnN3vNWyyNOH8485vfkHnCichywYFrheMnrJIFZMT++UEC0XCzBK7v4VMBSpunuRQ5SM3AylBQ",
-	"iWS5ZoK3fmL7Kao/8zD8SbI8BRzjfwH8N10hDSRDasUTlAFoxufYw3qVGwmlpfm99jBwek007FMBnCJK",
-	"NCDCKdIsA8Q4ur/9MBgMxmgmZEZ0Q3U/6I/8oO+HwSQM4yCIg+Df2MOlZIzNWr5Zp8sWMZsp0BPz1poz",
-	"I0WqcRx4O6YZCeSEjTkZ44UGZcxBXGg2YwkxkgoJiSRkjFOQqm5mULOIcT2KttYwrmEO0pijNJH6UHCs",
-	"wLPDE5waHs10utcW97Kua2K2/+f9O18okHe0vd7dNRIzpBeAjAR6XAgkHrmyT8DoamgZDgO4ioLAh/54",
-	"6kchjXxyGY78KBqNhsMoCoIgqHtYFIy2rVl7WMKngkmgOP4dV85sd2CbqBvDHzariOkfkGjj042UQt6D",
-	"ygVX0K4pMK87ImgeowyUIvNmDH/gyH6DRJIU0lj3OdOdik7bbOy+lzo/X1Wzjhz7jbNPRZlOiFHgpmpB",
-	"NpSG/QFEw9GlD1fjqR/26cAn0XDkR/3RKIzCy+iozGqjyueR5OtAxRswHAcMrUL6tUgSUGp/mVOiSdv8",
-	"St7sAkHvEsLR1OwGcit7iEhJVp5pGk77e+xhweGXGY5/f8J/lzDDMf5bb9vVe2VL77nSXntPmGnIrA3H",
-	"iFeuWcX28x3vH9YermCp5U8Zhk7c+iUHaXsgMupT0ECRcvKzIk1XxwX6t5x+C5qCfCTBxIoWKdCvTFr6",
-	"54A3Z8bd9VfBsxdjSeHwNdCk8CuiIfKRy3n6nREmJ/I81mTWYnwmjGOJ4JoktswhIyw1Gos8F1L/o/Tg",
-	"IhEZ9jAnmeVHH+/Qr07ARKgZF/PSZFtGOJmb6CYkBU6JdFFRG5Nj/KF6Y3dJoR8+3mEPL0Eqt1R4EVwE",
-	"Nvlz4CRnOMaDi+BigD2cE72wSNSDimDNQXfAJRCZLKxBTj16ZHqBhH1PUjRjqQap0HTltvHu2marTVtJ",
-	"+NzSAlHhqskDfMs4valcyYkkGZglbLtoKr+1i1eKtyqekxnMLPipALna7kO5uV45ZBrvP5tAh220SWQ2",
-	"bSZFhvSCKReKd82yfd9Zt0E4CWospstiu/6ttMnUYfSBCj/a8oJrlp5qev9q0h/Ew3E8HB80fSLObjhw",
-	"+oIBB05fJNyl1S8VbOD0OaF+MPjo+J4Fh34QVPhWggTJ87Rsgb0/lGMvpYWq9ndo/l6StIAts2yV94FB",
-	"bENV9jU1wx+O5Qb11h80Ou++AaIC2B3OX7Wy4zCnnQI/C64X6QrlUpgmgiQsGTx2uzuchMN40HB3fHU5",
-	"SyhM/WFI+n40oJf+tOHueDzecXewz9/hJIza/n4sDbuvDDvR4wfbFrdJd4jO784j9tNmvH5iShuSUNZM",
-	"RnSyMFVjKEIimQbJiGlv0clpyviSpGxDhzeZWh51VO8dKv7HYosrIM/0oIqE4RPcbZ6xdDh7V6q0OOCU",
-	"Gb5nCxvV2uTaw8Oj/D2bXRqkafUK5NIAmA2RdbzIMiJXZUuv0RMyV/ZQxz0w41guVAe5cAe6ChHE4XGH",
-	"5zieYXY6l2LJKFBEQROWtglF7VwYO4IHSv9T0NUXYNfWtxv3dMNJt6h2NjD7VgB1fPZ2HL2vm2xaywLW",
-	"rfYRnq99/NW6x3mh1M1xid3GnUONLwHQqsr2AmhZjWhqBN3MdAry1sDw5ZG3bqsZ72vKXyXquqIs0bOC",
-	"pxb4rr1yxus9Mbp2dZRC17nDtX2uEOElBE9XiGmFCnfs3DhwbiKw+7JC4IMz3Zcc8limaybXLdG1Q1oT",
-	"hU4Z59qEN9p/Htc+EETvuEBlQrx3hRSdWEhc6FtRcNpZDG4buNBoZmXOmfU3u2u/wgx3ebVJyG5u0Xlu",
-	"cQ9aMlhacqEYn1cN/Pic/hH0/0VCB28t+DW14IrEvoHFucHiR9DbGrf12DWIFB1g4U6vXeP7kyl7DFYb",
-	"Qkx73Td71C57vkOgOO+k5GJRRq5y8eSBqXWP1Q0l/S+HknC4H0vCw1jSuPJ4samr4ybxqKnrG0D+X2DX",
-	"zt8LCmfM2zj2nHHsrXuetXuW4H2IatsP7Apd7e0nkZAUUVhCKvLM3mBbWVNqMsUxXmidx71eauQWQun4",
-	"KrgKsGlEpabdFTf/jqGQhNRWihYd16HVnYf7vX5Y/y8AAP//1C0NkkQqAAA=",
+	"H4sIAAAAAAACA+1dC1MbRxL+K1O6qzp8J6EHAgOpVB0x8oU7BI4snNjBRQ3aEZp4X9kHQufjv193z8w+",
+	"tLtCMsLBhJSTSLujmZ6e7q8f0zP+XBt5ju+5wo3C2v7n2kRwSwT0sTfkV/h/S4SjQPqR9Nzafu0dvIVP",
+	"zBuzaCKYuIZf1pnPw5DJiEmXnh6NG30ejSZMdccij8W+xSPBvIBZwhbwKfl1rV4TN9zxbQHdn9e2zmvw",
+	"JBxNhMNx+Gjm44swCqR7Vbu9rddOxE30Kg5CLyiSp54b6lxoCbRdiQKFI9XO5wF3gJpgkx1chkAM89R7",
+	"m4fqlznq+r8dzE5ka9qXrZuTdz/dnBx60z7++9rfPR1ebX1wTj59+PkoOhm+nkCbVr/z09bxz0etk+H7",
+	"6OTwqHPyW6/VH45ap8NP2wsnCbNMSKPFOBoTQ4sTxlXKLQYbB56TziGAFUCuK/5vsiG80KsiQ3j7eywD",
+	"Ye3TJxFGIZvKaOLFEfKKBwKe/yZGkbDoOet2duv5poyzMOK2YERIQzdrdzbZee3v5zXmINkiZNydsetU",
+	"drjLxI0MI5juAimQOENFLXxzgR/w3QjXHVLyRgTSsw5h0kWmHQAxJI5IrZYIn9qzjffwT6PfbxwevsjR",
+	"1Gl1dhqtTqPdMoT5PJqkZGF/8M1wtLYfBbHIkjj2AmBG2rJI8lA64r+giz+qGRfIPjo4OWARNGLYyqx6",
+	"HMJablhizGM7YmfDV3m6e3Hg+aLZ98KRN63i6S8NM/YdXDXNfopFMFuFwjqL+CeQAz8QI2EJdwRvrxEa",
+	"4H06uhHNFafzO1GTzCb67x2zOAOCjqw3uH6FKeA7dnSYG3R7uyV2u61WQ3T2LhvdttVt8JftnUa3u7Oz",
+	"vd2FN60KqYhppOXkIo6lVSoXitwKlr+WNoCEUqKQXc6UPNxjAnPMTGawPMW3pjFB1yuAoEj0kL6BQg58",
+	"6uMyBpEUYWFKBYgjWMs+y07tZyE+2TMWCe6wcOaOmCMEwkqRLviVWwEIagh4rWCBwweSYsCGwetXW1tb",
+	"e0xPuhwShu32fqsFfz5Ag5yiN7CfUlpu8HVx9rW3EQ8iGj5EBfJGYKsCVJgQIHNkxxaAcYLxsDjIQli6",
+	"SDhhAWYqR9cPeBDwGX73xuNQRKiGiiDSvtp+qz5HHLZgqjEyx5FuDJNA5jDXi+RYjji2DNHkBAJekzeR",
+	"YVorwx/pRjvdlDr4Kq4AlYCcIIjtkmUaCMMLhg3YBiwOA/ndZmEM5jt6sc9eD3o/fX94cHT8/n8/93r/",
+	"gf/1T0+GPx6/r7Ojk2Fv8O7guM5+eH94AA9enZ6dDOsM/nN0vHnu9hw/mtFUwKYBn2xtUTfZaWYN0CiK",
+	"Gx9EBNYBbQdw5ANC18bww9Eh+g/yFdhD1+JgYcG1CqIXdebBWgVTGZJEAabBaDlJIqoVvd8Rcd8Pz74j",
+	"8r4ne1NYvxCFZJEsU4MvlubWqtJsuLCsSdB+o9i8QkchB+rnte+YSJYCmLUQ/0soiexKrqiX2f6GiBv9",
+	"asjQ8Fec1mHO/k4nHvOmIPflbu2S+Hu3KUjNyK81M5lUFlKESwj/mPTiXaInh3PqBYEXDEQIfn8oimA8",
+	"8qwSBvb5CFwl0UCHkl+ibmAvjBpnp0qTvwAwuBh7sWuV8XQshW2VaTdZB0avgZEcPnJpg5pdc1tavAD9",
+	"6WQLQwB4hui9Fwb5MXa4Oz8J07owDwQ1VjGPudXQjDBdlfKd5KLE+GFAZB1ERWoJblPXfspD5oDbZGFE",
+	"hY+jgIeTOuMqdkF90X4A0q0dW2pTqumtvWFrd39rNU1/pKb6qRpmWaIoZ64ETdEyIcGZRsNL7nzKgnZn",
+	"S3S3d142xC4gTrtjbTU4fG90Ozs77W77ZXcpxCk6Bnc7A8/W/lFY+z/ctmdA6dmI58xGwTAckeQcAVIU",
+	"rQNZKPzw10CM4Vd/aaYZu6YOs5p5i44gZkzNwl9Ro2p2vz3r9w8G7w2P3vXe9U6GpfwtRamUu+qXxiAp",
+	"PUGwlLZYhUELXBaKMUuIOImdS0H5QN1EG8hSHFLexqJOtHUl32QqABvQzprBy7pM0H8e2kLMbmiDHcwy",
+	"HEKmQPSk0jPJzxctY0Z6CgZk3klJSNVzNUOUuStvMMs2H7fn5/Hvt6cnoHjBlWDUWmH0y629nRc5NNhn",
+	"niMjtebg3Clc/SR8gAk3tm0IFQHP4al6fe6CeyPhu45DN9kA31OykCc5S+0nbpDw1lmCkXWmfYc6U3r/",
+	"gtzIMONFKkB+8BQEa+DEYJWsWPEa54pup0kCfc0ERecO2L+btj/UL6ogrzKBsdakRXu7xI+poGgNfk1d",
+	"x79IH2pH0UlZyZ24c2UfOJnQvq/kfaEDkkUWGWHIdJcfsgQlX+yXABgMBOZUlwKCP9RjuYO6MgP9Nh6B",
+	"vofVFhoWnJfaQWqPssXZxoi77BJljKmewYlE/a6jJqjRcTMAVvoUjOGvyzk3n5czoqkvlAeWwvbBx0XZ",
+	"Bc2G0oTCKXCDcIbh8BTyg8JT+zEwfLacJ3RG+3hfPZteMGWPyXR9gyG8YsrcLs1DxewPlswvtYvP8f1j",
+	"y+a3v0rE/x2TY+Pm15UCRR766WlzGZLT/9VyAYBaCi+tbyy1r5p8WX4f+5Lu2FOpfDfiIzIRYOWkjSPG",
+	"PorxP/UMNsEYpZu9B2+O2FvVoJDrpZeoTw53+RVyd2Q0I42p1VrVEp3pqYAZfgpvdf0HIt1ma7NFUOWD",
+	"T+RLeLQFj7YwKuPRhMC2meQwrkRJ6PlW8AACzkzOm0pPPHrPbYyjsXgmsyNOqkM6FHD3SoQYViISJDUo",
+	"BTgAlzFrIijsp4IWkgU9Qo1moUw7ilPtNQBlz3AkW8bz6zewd19fTCPJIjJMW0nQaOLoRh6KyktnWu1h",
+	"K5N9LKOY+n8dkExWlM5UoNbSlMduJO1VSe/sDjtb+9t78Gch6UNv7YSDIj0gw6H3B2G3pvqhmA3dr4XV",
+	"fX4jndiBWHEuxWeqwpQXX0aCLcHW5UhIPK12C3wtR3VN3/CrdPXXootUpOvU57jFo8sUE5f0lwYWPzZ0",
+	"laMultLGyg/EtfTicBHNqr/F1VEFoPXQ9QDLNKLgBVAq9UaqVAHtRylnajwckRlDTvyqv+F4GUOWkPIR",
+	"jaIKEMkidADotFHTloH7vq291OZvoQp3tDyFmc9t/HzN7ViUgPCCXckkpqlyp9C1X9Ztz3rlrZzPV7U9",
+	"Y6zp3PaG8VuWswwlMg/8m8CMIWhEj4Gh3Ihp+XS3h+3tdIeYpru3+3I8ssRlY7vNO43ulvWycZmb7t7e",
+	"3tx0t6rmC913i/N9owkbGMJWnPHHtAINF/4+8f9twQt6A9qVwQlyBxDpqKo4gLECyesqgQ8+RE5byAEh",
+	"654ps86pdBWVun0zU/pMpHVX1gjpUho8HU6rhSn7qF1y60JX+GbKGfbNDzUk1ZSXSY9M4LJqT8SYC8I2",
+	"Bd6UsDdBTbaOcMXtr+KiHekhaRXUYIjwBFss46LBD7eXYuna6IJh0VsFAcdqWLXZRxOPHYdjvSe5kxkP",
+	"m1+hJ1nTDzAb5XtlWzOq7BL3VFwxnXPVlausjIZ3LdHZtUSEmyQFZzZTvamrWGE5f/Cs2T2QOJ1bTz1N",
+	"wqpEhNYH0X8U7C4vvSUFsrf5gBDTsLcFY9i+vzGsyss+dZu4wurMZ7ZLtFilIswG81xOt+REzSKEpzb3",
+	"w3ajnavisX7HLvH3a8P3DOQ+PL5np0BnjdLBa8TSvRVZih38EIel/MR3F5f4Mjt7GhLCHXqxzjniyR1K",
+	"pYU2FvcBsttYxjijgxXkl3OXMpMa4zM5LJp7p/Nl4oTLP8CESRkPdBta5QtKq9SS+s5MsiplT7IP4MS4",
+	"TuATjTFqzIQU6+SZ0sxiFeljtPPKDGh7bQxiwdzDT1RirPlZWrdp+WhJwa53TeeutDhkK0bVMTD1XO+5",
+	"gW1R2VoTseumKGh+HFwJS/1o/mgfLePEo4JdilcD7LRwKq3gVxwS1cavWJglu89eScmRHHnP4zjlopDO",
+	"oGnO6pXEr92qPHaxqpdtuB7T0vlCgVd3RQUGOHhNtcslilss087o6Fzp8/o1MtM3TKy9KjLpRHJfho45",
+	"FTk/P93kwjFtihNU1dQW1s5aWfgMsk7YGsG7ZFQFfrjmpDEQkiT6he1kGMZC8aizuyKP8KCd51oSGwwS",
+	"aS8yKtvuIlGLLLfmVT5zbnTtPCoZC1Yw1Ds2jw6zFYolIFsen5VuXwwEAIoghM5tT6IgYulbrGq8c9Xd",
+	"eQT9l4i+Qfh8kJzecxizvjAmTQTcI3J5opbq0eEPYEAKG6TiZfmh8psDDpB0wp9FVbzaJdGlEwjRgapd",
+	"lklBLdox7GYKbqApvjt38XS3zX2zLZsGJmFudx13XIEjo0/Qi+eCsk4nQm29UAkP3jaQVIngxQ5TF340",
+	"mmCksXnu3sMjpZqNPKKmBc9P2yVdJofnoDg0SHT+UaaxgVC0Z7N5qr4xc16NNtwDz9fF3NmSs/JMH1Ye",
+	"loNjpnxyBSUvlrAvlVlbn5YvDbvqfo6nkz1ar1uYy+tIdbxR45Yqe0FsmKDHrKnRZ0hBUcKnHTt96xkt",
+	"THbcJ6v1HDsuETuuyiMC4EVBYyZONMk+U602zyl6jlkmlNtLoY42rJ0/ZKjM6a7yhN9zCP0oXdg3WJbF",
+	"wdqZK8IWB9N+XBJMqyLPsHirlHI+EWN0WHOvJGadhZSSMw2ERX6NR5CM/u4U9/+Z4CPt8BY8zEz1/rOL",
+	"ufw2sWKbXlLDjZV3iwtnGMpzAJ375wCwLr4qCdBenATIlSw/2JZzySmStTnGa87V/AlWbf1JnKcWTTzJ",
+	"vejnkOTpbbI/hyMPEI487UKE57jkUcYlZ3dHI/lajKYuoaAVKi3JHIgoDlxMlesrv+Zv7C0UZwCPTHIc",
+	"DzolOXV+xaVbx7Q7RiAEWA6fsQm/FiDKmEAnuJpO8DIRqTR17jqwfGAyULT/aTYUlxQkVRRepc5Jzcz6",
+	"3Kwn6BPkBe/JOAi5O7dhpHIH4VFCm1b2tDYsD0ALoS5sWnzW/Iz8va08m2mAzuz3Kdjyk2MSphgdulKH",
+	"KumcqtRnbK8k/EzZZi066F79LUwPEhfg61iGELXNqk5d3pG6yFwVvkSiI3sb8xLN8zdmr/ADfQv4gwLc",
+	"EgElsjZz3mV+LdW16bX7xIjJ1eNfENelIvFgx0XA7mZGeYTqTCukl0fddwB6dYcSO3gGbF1qTJ2tT5Hp",
+	"fNqzKj+r8rMqc6VbdyjzVIhP99Rl7GKhBquoZCyDMCKzrd0f+h14P/oKGX1tDk4P+hnLqzgw1zYX9Rwz",
+	"vs9q/qzmz2rOSY+qtFw55UsqdjbYqgN60F8BNIJX9izJOpAWV9x+ElB3qqQPr+xRl2BtssNsxkI1VcdC",
+	"UJ4c7qoRVA4N+sCqZc/dJDIutMSVYcBQBxyrqX9OoR/uloDkWvaqG9P/XNXGD3qs3oBWLjMWPl7tnaOz",
+	"QnWRw2Hzs2L0bdN4zJtyFC5QaLpnjXHbLi2YDTNXkiVXpr2ovsxI3bXsYQEtVUMMBmfHPSrN7P1yeDDs",
+	"qbu6she+mTvhYKh3B8cHg35BdXvUrSHjC/WX/v6lJdQ3EjdRwrr8Wpf8zUN5ZqassrxR7DzWjIzip1rg",
+	"UcrVZWRK3UpXnXRO7gFIkj3zl04b6fJtj+NVACiddA218flsoVw8l0QimqvHPHe9wDw/r2Hr85quv8S/",
+	"6I05sR1JH3dVMJW7aa4EU85mUhZmTk9vyNSS8zrl78iYvzh3lXnS8uzjDgIgkKoxViU4/BKZmF4xvllS",
+	"460uy16f5FYVuiSzbuKsG6bmIZWp/JWddBV6Ntt9KV0eLHUnKD5ZSUO+SpFHciN7O71XvZ1cpQmMTm53",
+	"ezDbuKieY/237FT2Q+RrV6DRzt27h7FFcr//CvnsdLDTjHPvVIzZyY/5cQW/e+7e/TJ0oxZaL+8VVLzK",
+	"yO+qQUUG5lHR1p3wNxCoys4LQ2YsS7e9tWqtrecd86B8U1tP+QK96Wh2AU0vbGqb5UGShiMqxc1ICCtk",
+	"7Rbryx8ejBNEBm73g1kxQz1Cu6ql04RauM0hXy2yr/hr6q5s4/PYA16D53ctbM936F5WaosaF+DdmpMo",
+	"8vebTRvbTcAa7++2dltkKPRIxWvetG3CfXebDGDkldyraW69007xx9v/A8wtyfi4dwAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -1,6 +1,7 @@
 package mapper
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -10,7 +11,10 @@ import (
 	"github.com/google/uuid"
 )
 
-var ErrInvalidUUID = errors.New("invalid UUID format")
+var (
+	ErrInvalidUUID  = errors.New("invalid UUID format")
+	ErrInvalidPatch = errors.New("invalid merge patch")
+)
 
 func CreateRequestToDomain(req genhandlers.CreateEventRequest) domain.Event {
 	description := ""
@@ -23,6 +27,21 @@ func CreateRequestToDomain(req genhandlers.CreateEventRequest) domain.Event {
 		offsetTime = *req.OffsetTime
 	}
 
+	rrule := ""
+	if req.Rrule != nil {
+		rrule = *req.Rrule
+	}
+
+	var exDates domain.ExDates
+	if req.Exdates != nil {
+		exDates = *req.Exdates
+	}
+
+	timeZone := ""
+	if req.TimeZone != nil {
+		timeZone = *req.TimeZone
+	}
+
 	return domain.Event{
 		Title:       req.Title,
 		StartDate:   req.StartDate,
@@ -30,6 +49,9 @@ func CreateRequestToDomain(req genhandlers.CreateEventRequest) domain.Event {
 		Description: description,
 		UserID:      req.UserId.String(),
 		OffsetTime:  time.Duration(offsetTime) * time.Minute,
+		RRule:       rrule,
+		ExDates:     exDates,
+		TimeZone:    timeZone,
 	}
 }
 
@@ -44,6 +66,21 @@ func UpdateRequestToDomain(req genhandlers.UpdateEventRequest, id string) domain
 		offsetTime = *req.OffsetTime
 	}
 
+	rrule := ""
+	if req.Rrule != nil {
+		rrule = *req.Rrule
+	}
+
+	var exDates domain.ExDates
+	if req.Exdates != nil {
+		exDates = *req.Exdates
+	}
+
+	timeZone := ""
+	if req.TimeZone != nil {
+		timeZone = *req.TimeZone
+	}
+
 	return domain.Event{
 		ID:          id,
 		Title:       req.Title,
@@ -52,9 +89,67 @@ func UpdateRequestToDomain(req genhandlers.UpdateEventRequest, id string) domain
 		Description: description,
 		UserID:      req.UserId.String(),
 		OffsetTime:  time.Duration(offsetTime) * time.Minute,
+		RRule:       rrule,
+		ExDates:     exDates,
+		TimeZone:    timeZone,
 	}
 }
 
+// PatchRequestToDomain разбирает JSON Merge Patch события. Отсутствующие поля не попадают
+// в патч, null сбрасывает поле к значению по умолчанию.
+func PatchRequestToDomain(body []byte) (domain.EventPatch, error) {
+	// Поля читаются дважды: карта показывает, какие поля переданы, структура - их значения
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return domain.EventPatch{}, fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+	}
+	if fields == nil {
+		return domain.EventPatch{}, fmt.Errorf("%w: patch must be a JSON object", ErrInvalidPatch)
+	}
+
+	var req genhandlers.PatchEventRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return domain.EventPatch{}, fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+	}
+
+	patch := domain.EventPatch{
+		Title:       patchField(fields, "title", req.Title),
+		StartDate:   patchField(fields, "startDate", req.StartDate),
+		EndDate:     patchField(fields, "endDate", req.EndDate),
+		Description: patchField(fields, "description", req.Description),
+		RRule:       patchField(fields, "rrule", req.Rrule),
+		TimeZone:    patchField(fields, "timeZone", req.TimeZone),
+	}
+	if _, ok := fields["userId"]; ok {
+		var userID string
+		if req.UserId != nil {
+			userID = req.UserId.String()
+		}
+		patch.UserID = &userID
+	}
+	if offset := patchField(fields, "offsetTime", req.OffsetTime); offset != nil {
+		offsetTime := time.Duration(*offset) * time.Minute
+		patch.OffsetTime = &offsetTime
+	}
+	if exDates := patchField(fields, "exdates", req.Exdates); exDates != nil {
+		dates := domain.ExDates(*exDates)
+		patch.ExDates = &dates
+	}
+	return patch, nil
+}
+
+// patchField возвращает nil для отсутствующего поля, нулевое значение для null
+// и значение поля в остальных случаях.
+func patchField[T any](fields map[string]json.RawMessage, name string, value *T) *T {
+	if _, ok := fields[name]; !ok {
+		return nil
+	}
+	if value == nil {
+		value = new(T)
+	}
+	return value
+}
+
 func DomainToResponse(e domain.Event) (genhandlers.Event, error) {
 	id, err := uuid.Parse(e.ID)
 	if err != nil {
@@ -68,6 +163,19 @@ func DomainToResponse(e domain.Event) (genhandlers.Event, error) {
 
 	offsetMinutes := int64(e.OffsetTime / time.Minute)
 
+	var rrule, timeZone *string
+	var exDates *[]time.Time
+	if e.IsRecurring() {
+		rrule = &e.RRule
+		if len(e.ExDates) > 0 {
+			dates := []time.Time(e.ExDates)
+			exDates = &dates
+		}
+	}
+	if zone := e.ZoneName(); zone != "" {
+		timeZone = &zone
+	}
+
 	return genhandlers.Event{
 		Id:          &id,
 		Title:       &e.Title,
@@ -76,6 +184,10 @@ func DomainToResponse(e domain.Event) (genhandlers.Event, error) {
 		Description: &e.Description,
 		UserId:      &userID,
 		OffsetTime:  &offsetMinutes,
+		Rrule:       rrule,
+		Exdates:     exDates,
+		TimeZone:    timeZone,
+		DeletedAt:   e.DeletedAt,
 	}, nil
 }
 
@@ -4,7 +4,9 @@ import (
 	"context"
 	"time"
 
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/app"
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -29,11 +31,35 @@ func (m *MockApplication) UpdateEvent(ctx context.Context, id string, event doma
 	return args.Get(0).(*domain.Event), args.Error(1)
 }
 
-func (m *MockApplication) DeleteEvent(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockApplication) PatchEvent(ctx context.Context, id string, patch domain.EventPatch, version int64) (*domain.Event, error) {
+	args := m.Called(ctx, id, patch, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Event), args.Error(1)
+}
+
+func (m *MockApplication) DeleteEvent(ctx context.Context, id string, version int64) error {
+	args := m.Called(ctx, id, version)
 	return args.Error(0)
 }
 
+func (m *MockApplication) FindTrash(ctx context.Context, userID string) ([]domain.Event, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Event), args.Error(1)
+}
+
+func (m *MockApplication) RestoreEvent(ctx context.Context, id string) (*domain.Event, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Event), args.Error(1)
+}
+
 func (m *MockApplication) GetEventByID(ctx context.Context, id string) (*domain.Event, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -42,31 +68,82 @@ func (m *MockApplication) GetEventByID(ctx context.Context, id string) (*domain.
 	return args.Get(0).(*domain.Event), args.Error(1)
 }
 
-func (m *MockApplication) FindEvent(ctx context.Context, userID string, startFrom, startTo, endFrom, endTo *time.Time) ([]domain.Event, error) {
-	args := m.Called(ctx, userID, startFrom, startTo, endFrom, endTo)
+func (m *MockApplication) FindEvent(
+	ctx context.Context, userID string, startFrom, startTo, endFrom, endTo *time.Time, page domain.Page,
+) ([]domain.Event, *domain.Cursor, error) {
+	args := m.Called(ctx, userID, startFrom, startTo, endFrom, endTo, page)
+	var next *domain.Cursor
+	if c, ok := args.Get(1).(*domain.Cursor); ok {
+		next = c
+	}
+	if args.Get(0) == nil {
+		return nil, next, args.Error(2)
+	}
+	return args.Get(0).([]domain.Event), next, args.Error(2)
+}
+
+func (m *MockApplication) FindDayEvents(ctx context.Context, userID string, date time.Time) ([]domain.Event, error) {
+	args := m.Called(ctx, userID, date)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]domain.Event), args.Error(1)
 }
 
-// MockLogger - мок для logger.Logger
+func (m *MockApplication) FindWeekEvents(ctx context.Context, userID string, date time.Time) ([]domain.Event, error) {
+	args := m.Called(ctx, userID, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Event), args.Error(1)
+}
+
+func (m *MockApplication) FindMonthEvents(ctx context.Context, userID string, date time.Time) ([]domain.Event, error) {
+	args := m.Called(ctx, userID, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Event), args.Error(1)
+}
+
+func (m *MockApplication) ExportEvents(ctx context.Context, userID string) ([]domain.Event, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Event), args.Error(1)
+}
+
+func (m *MockApplication) ImportEvents(ctx context.Context, userID string, imported []domain.Event) ([]app.ImportResult, error) {
+	args := m.Called(ctx, userID, imported)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]app.ImportResult), args.Error(1)
+}
+
+// MockLogger - мок для logger.Logger. Ожидания задаются по сообщению, поля записи не проверяются.
 type MockLogger struct {
 	mock.Mock
 }
 
-func (m *MockLogger) Debug(msg string) {
+func (m *MockLogger) Debug(msg string, _ ...any) {
 	m.Called(msg)
 }
 
-func (m *MockLogger) Info(msg string) {
+func (m *MockLogger) Info(msg string, _ ...any) {
 	m.Called(msg)
 }
 
-func (m *MockLogger) Warn(msg string) {
+func (m *MockLogger) Warn(msg string, _ ...any) {
 	m.Called(msg)
 }
 
-func (m *MockLogger) Error(msg string) {
+func (m *MockLogger) Error(msg string, _ ...any) {
 	m.Called(msg)
 }
+
+// With возвращает тот же мок, чтобы записи дочерних логгеров проверялись вместе с основными.
+func (m *MockLogger) With(_ ...any) logger.Logger {
+	return m
+}
@@ -3,11 +3,15 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/metrics"
 	genhandlers "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/server/http/handlers/generated"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -19,17 +23,46 @@ func RegisterHandlers(router genhandlers.EchoRouter, handler *EventHandler, url
 	}
 }
 
+// maxRequestIDLength ограничивает длину идентификатора запроса, пришедшего от клиента.
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware берет идентификатор запроса из заголовка X-Request-ID или генерирует новый,
+// возвращает его в ответе и сохраняет в контексте запроса вместе с логгером запроса.
+// Должен подключаться первым, чтобы идентификатор попал в логи остальных middleware.
+func RequestIDMiddleware(log logger.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			requestID := c.Request().Header.Get(echo.HeaderXRequestID)
+			if requestID == "" || len(requestID) > maxRequestIDLength {
+				requestID = uuid.NewString()
+			}
+			c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+
+			ctx := logger.WithRequestID(c.Request().Context(), requestID)
+			ctx = logger.NewContext(ctx, log.With(logger.RequestIDKey, requestID))
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
 func LoggingMiddleware(log logger.Logger) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			start := time.Now()
 			req := c.Request()
+			log := logger.WithContext(req.Context(), log)
 			err := next(c)
+			if err != nil {
+				// Ответ об ошибке формируется до записи в лог, чтобы в лог попал итоговый статус
+				c.Error(err)
+			}
 
 			logHTTPRequest(log, req, c.Response().Status, start)
 
 			if err != nil {
-				log.Error("Handler error: " + err.Error())
+				log.Error("Handler error", "error", err)
 			}
 
 			return err
@@ -37,23 +70,74 @@ func LoggingMiddleware(log logger.Logger) echo.MiddlewareFunc {
 	}
 }
 
+// unknownOperation - метка запросов к маршрутам, которых нет в спецификации API.
+const unknownOperation = "unknown"
+
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// MetricsMiddleware учитывает запросы по operationId из сгенерированной спецификации API
+// (в генераторе он совпадает с именем метода ServerInterface, например GetEvent).
+// Должен подключаться до LoggingMiddleware, чтобы видеть итоговый статус ответа.
+func MetricsMiddleware(m *metrics.Metrics) (echo.MiddlewareFunc, error) {
+	operations, err := operationIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			operation, ok := operations[c.Request().Method+" "+c.Path()]
+			if !ok {
+				operation = unknownOperation
+			}
+			status := c.Response().Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			m.ObserveRequest(metrics.TransportHTTP, operation, strconv.Itoa(status), time.Since(start))
+
+			return err
+		}
+	}, nil
+}
+
+// operationIDs сопоставляет маршрут echo ("GET /event/:id") с operationId из спецификации.
+func operationIDs() (map[string]string, error) {
+	swagger, err := genhandlers.GetSwagger()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API spec: %w", err)
+	}
+
+	operations := make(map[string]string)
+	for path, item := range swagger.Paths.Map() {
+		route := pathParam.ReplaceAllString(path, ":$1")
+		for method, operation := range item.Operations() {
+			operations[method+" "+route] = operation.OperationID
+		}
+	}
+	return operations, nil
+}
+
 func logHTTPRequest(log logger.Logger, r *http.Request, status int, start time.Time) {
 	if status == 0 {
 		status = http.StatusOK
 	}
 
-	logLine := fmt.Sprintf("%s [%s] %s %s %s %d %v \"%s\"",
-		getClientIP(r),
-		start.Format("02/Jan/2006:15:04:05 -0700"),
-		r.Method,
-		r.URL.RequestURI(),
-		r.Proto,
-		status,
-		time.Since(start),
-		getUserAgent(r),
+	log.Info("http request",
+		"client_ip", getClientIP(r),
+		"method", r.Method,
+		"path", r.URL.RequestURI(),
+		"proto", r.Proto,
+		"status", status,
+		"latency", time.Since(start),
+		"user_agent", getUserAgent(r),
 	)
-
-	log.Info(logLine)
 }
 
 func getClientIP(r *http.Request) string {
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/metrics"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	mockApp := new(MockApplication)
+	mockLogger := new(MockLogger)
+	mockLogger.On("Info", mock.Anything).Return()
+	mockLogger.On("Error", mock.Anything).Return()
+
+	m := metrics.New()
+	metricsMiddleware, err := MetricsMiddleware(m)
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler(mockLogger)
+	e.Use(metricsMiddleware)
+	e.Use(LoggingMiddleware(mockLogger))
+	RegisterHandlers(e, NewEventHandler(mockApp, mockLogger), "")
+
+	id := uuid.New().String()
+	mockApp.On("GetEventByID", mock.Anything, id).Return(nil, services.ErrEventNotFound)
+
+	for _, target := range []string{"/event/" + id, "/missing"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metrics.Path, nil))
+	body := rec.Body.String()
+	assert.Contains(t, body, `calendar_requests_total{code="404",operation="GetEvent",transport="http"} 1`)
+	assert.Contains(t, body, `calendar_requests_total{code="404",operation="unknown",transport="http"} 1`)
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var logOutput bytes.Buffer
+	log := logger.New("DEBUG", &logOutput)
+
+	e := echo.New()
+	e.Use(RequestIDMiddleware(log))
+	e.Use(LoggingMiddleware(log))
+	e.GET("/ping", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger.FromContext(ctx).Debug("handled")
+		return c.String(http.StatusOK, logger.RequestID(ctx))
+	})
+
+	t.Run("keeps client id", func(t *testing.T) {
+		logOutput.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set(echo.HeaderXRequestID, "client-id")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, "client-id", rec.Body.String())
+		assert.Equal(t, "client-id", rec.Header().Get(echo.HeaderXRequestID))
+		assert.Contains(t, logOutput.String(), "[DEBUG] handled request_id=client-id")
+		assert.Contains(t, logOutput.String(), "method=GET path=/ping proto=HTTP/1.1 status=200")
+		assert.Equal(t, 2, strings.Count(logOutput.String(), "request_id=client-id"))
+	})
+
+	t.Run("generates id", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+		requestID := rec.Header().Get(echo.HeaderXRequestID)
+		_, err := uuid.Parse(requestID)
+		require.NoError(t, err)
+		assert.Equal(t, requestID, rec.Body.String())
+	})
+}
@@ -0,0 +1,116 @@
+package internalhttp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HealthPath = "/healthz"
+	ReadyPath  = "/readyz"
+
+	healthCheckTimeout = 2 * time.Second
+
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+	statusDraining    = "draining"
+)
+
+// StorageChecker проверяет доступность хранилища событий.
+type StorageChecker interface {
+	Connect(ctx context.Context) error
+}
+
+// BuildInfo - информация о сборке бинарного файла.
+type BuildInfo struct {
+	Release   string `json:"release"`
+	BuildDate string `json:"buildDate"`
+	GitHash   string `json:"gitHash"`
+}
+
+// Health - параметры проверок состояния сервиса.
+type Health struct {
+	// Storage - тип хранилища из конфигурации ("memory", "db", "sqlite").
+	Storage string
+	Checker StorageChecker
+	Build   BuildInfo
+	// DrainDelay - сколько при остановке /readyz отвечает 503 до закрытия сервера.
+	DrainDelay time.Duration
+}
+
+// HealthResponse - ответ /healthz и /readyz.
+type HealthResponse struct {
+	Status  string            `json:"status"`
+	Storage string            `json:"storage"`
+	Checks  map[string]string `json:"checks"`
+	Build   BuildInfo         `json:"build"`
+}
+
+// RegisterHealth подключает /healthz, который всегда отвечает 200, и /readyz.
+func (s *ServerNew) RegisterHealth(health Health) {
+	s.health = health
+	s.echo.GET(HealthPath, s.healthz)
+	s.echo.GET(ReadyPath, s.readyz)
+}
+
+func (s *ServerNew) healthz(c echo.Context) error {
+	return c.JSON(http.StatusOK, s.checkHealth(c.Request().Context()))
+}
+
+func (s *ServerNew) readyz(c echo.Context) error {
+	response := s.checkHealth(c.Request().Context())
+	if s.draining.Load() {
+		response.Status = statusDraining
+	}
+	if response.Status != statusOK {
+		return c.JSON(http.StatusServiceUnavailable, response)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *ServerNew) checkHealth(ctx context.Context) HealthResponse {
+	response := HealthResponse{
+		Status:  statusOK,
+		Storage: s.health.Storage,
+		Checks:  map[string]string{"storage": statusOK},
+		Build:   s.health.Build,
+	}
+	if s.health.Checker == nil {
+		return response
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	if err := s.health.Checker.Connect(ctx); err != nil {
+		s.logger.Warn("health check failed", "check", "storage", "error", err)
+		response.Status = statusUnavailable
+		// Текст ошибки хранилища может раскрывать адреса и учетные данные, клиенту - только статус
+		response.Checks["storage"] = statusUnavailable
+	}
+	return response
+}
+
+// drain переводит /readyz в 503 и ждет DrainDelay с первого вызова; ждут все вызывающие.
+func (s *ServerNew) drain(ctx context.Context) {
+	s.draining.Store(true)
+	s.drainOnce.Do(func() {
+		s.drainUntil = time.Now().Add(s.health.DrainDelay)
+		if s.health.DrainDelay > 0 {
+			s.logger.Info("draining HTTP server", "delay", s.health.DrainDelay)
+		}
+	})
+
+	wait := time.Until(s.drainUntil)
+	if wait <= 0 {
+		return
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
@@ -15,37 +18,101 @@ import (
 const (
 	defaultReadTimeout     = 10 * time.Second
 	defaultWriteTimeout    = 10 * time.Second
+	defaultIdleTimeout     = time.Minute
 	defaultShutdownTimeout = 5 * time.Second
 )
 
+// Settings - параметры сервера, которые можно менять во время работы через ApplySettings.
+type Settings struct {
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// CORSOrigins - разрешенные источники, "*" разрешает любой.
+	CORSOrigins []string
+}
+
+// DefaultSettings возвращает параметры, с которыми сервер создается.
+func DefaultSettings() Settings {
+	return Settings{
+		ReadTimeout:  defaultReadTimeout,
+		WriteTimeout: defaultWriteTimeout,
+		CORSOrigins:  []string{"*"},
+	}
+}
+
 type ServerNew struct {
-	echo   *echo.Echo
-	logger logger.Logger
-	url    string
+	echo     *echo.Echo
+	logger   logger.Logger
+	url      string
+	settings atomic.Pointer[Settings]
+
+	health     Health
+	draining   atomic.Bool
+	drainOnce  sync.Once
+	drainUntil time.Time
 }
 
-func NewServerWithGeneratedHandlers(log logger.Logger, eventHandler *handlers.EventHandler, addr string) *ServerNew {
-	e := echo.New()
+// NewServerWithGeneratedHandlers создает сервер; middlewares подключаются перед LoggingMiddleware.
+func NewServerWithGeneratedHandlers(
+	log logger.Logger, eventHandler *handlers.EventHandler, addr string, middlewares ...echo.MiddlewareFunc,
+) *ServerNew {
+	s := &ServerNew{
+		echo:   echo.New(),
+		logger: log,
+		url:    addr,
+	}
+	s.ApplySettings(DefaultSettings())
 
+	e := s.echo
 	e.HideBanner = true
 	e.HidePort = true
+	e.HTTPErrorHandler = handlers.HTTPErrorHandler(log)
 
 	e.Use(middleware.Recover())
-	e.Use(middleware.CORS())
+	e.Use(handlers.RequestIDMiddleware(log))
+	e.Use(s.deadlineMiddleware)
+	// ETag нужен браузерным клиентам для заголовка If-Match при изменении события, X-Next-Cursor - для
+	// перехода на следующую страницу findEvents
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOriginFunc: s.allowOrigin,
+		ExposeHeaders:   []string{"ETag", handlers.HeaderNextCursor},
+	}))
+	e.Use(middlewares...)
 	e.Use(handlers.LoggingMiddleware(log))
 
 	handlers.RegisterHandlers(e, eventHandler, "")
 
-	e.Server.ReadTimeout = defaultReadTimeout
-	e.Server.WriteTimeout = defaultWriteTimeout
+	// Таймауты чтения тела и записи ответа выставляет deadlineMiddleware, чтобы их можно было
+	// менять без перезапуска; на уровне сервера ограничено только чтение заголовков и простой.
+	e.Server.ReadHeaderTimeout = defaultReadTimeout
+	e.Server.IdleTimeout = defaultIdleTimeout
 
-	return &ServerNew{
-		echo:   e,
-		logger: log,
-		url:    addr,
+	return s
+}
+
+// ApplySettings применяет параметры к последующим запросам, обрабатываемые запросы не прерываются.
+func (s *ServerNew) ApplySettings(settings Settings) {
+	settings.CORSOrigins = slices.Clone(settings.CORSOrigins)
+	s.settings.Store(&settings)
+}
+
+// deadlineMiddleware выставляет соединению дедлайны чтения и записи по текущим Settings.
+func (s *ServerNew) deadlineMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		settings := s.settings.Load()
+		controller := http.NewResponseController(c.Response())
+		now := time.Now()
+		// ResponseWriter без поддержки дедлайнов (например, в тестах) оставляем как есть.
+		_ = controller.SetReadDeadline(now.Add(settings.ReadTimeout))
+		_ = controller.SetWriteDeadline(now.Add(settings.WriteTimeout))
+		return next(c)
 	}
 }
 
+func (s *ServerNew) allowOrigin(origin string) (bool, error) {
+	origins := s.settings.Load().CORSOrigins
+	return slices.Contains(origins, "*") || slices.Contains(origins, origin), nil
+}
+
 func (s *ServerNew) Start(ctx context.Context) error {
 	s.logger.Info("starting HTTP server")
 
@@ -58,19 +125,22 @@ func (s *ServerNew) Start(ctx context.Context) error {
 
 	select {
 	case err := <-errChan:
-		s.logger.Error("server error: " + err.Error())
+		s.logger.Error("server error", "error", err)
 		return err
 	case <-ctx.Done():
 		s.logger.Info("shutdown signal received")
 
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), defaultShutdownTimeout)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.health.DrainDelay+defaultShutdownTimeout)
 		defer cancel()
 
+		s.drain(shutdownCtx)
 		return s.echo.Shutdown(shutdownCtx)
 	}
 }
 
+// Stop выдерживает Health.DrainDelay и дожидается обрабатываемых запросов.
 func (s *ServerNew) Stop(ctx context.Context) error {
 	s.logger.Info("stopping HTTP server")
+	s.drain(ctx)
 	return s.echo.Shutdown(ctx)
 }
@@ -0,0 +1,61 @@
+package services
+
+import "errors"
+
+// ErrorKind - категория ошибки сервиса, по которой транспорт выбирает код ответа.
+type ErrorKind int
+
+const (
+	KindInternal ErrorKind = iota
+	KindNotFound
+	KindConflict
+	KindValidation
+	// KindPrecondition - условие запроса не выполнено, например изменилась версия события.
+	KindPrecondition
+)
+
+// Error - типизированная ошибка сервиса. Code - машиночитаемый код для клиента,
+// Field - поле запроса, не прошедшее валидацию.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Field   string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func newNotFoundError(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func newConflictError(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+func newPreconditionError(code, message string) *Error {
+	return &Error{Kind: KindPrecondition, Code: code, Message: message}
+}
+
+func newValidationError(field, code, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message, Field: field}
+}
+
+// AsError извлекает типизированную ошибку сервиса из цепочки err.
+func AsError(err error) (*Error, bool) {
+	var serviceErr *Error
+	if errors.As(err, &serviceErr) {
+		return serviceErr, true
+	}
+	return nil, false
+}
+
+// KindOf возвращает категорию ошибки, для нетипизированных ошибок - KindInternal.
+func KindOf(err error) ErrorKind {
+	if serviceErr, ok := AsError(err); ok {
+		return serviceErr.Kind
+	}
+	return KindInternal
+}
@@ -2,35 +2,65 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
 	events "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
 	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
 	"github.com/jmoiron/sqlx"
 )
 
-const EntityNotFound = "entity not found"
+var (
+	ErrEventNotFound     = newNotFoundError("event_not_found", "event not found")
+	ErrDateBusy          = newConflictError("date_busy", "date is busy")
+	ErrVersionMismatch   = newPreconditionError("version_mismatch", "event was modified by another request")
+	ErrInvalidEventID    = newValidationError("id", "required", "event ID cannot be empty")
+	ErrInvalidEventTitle = newValidationError("title", "required", "event title cannot be empty")
+	ErrInvalidUserID     = newValidationError("userId", "required", "user ID cannot be empty")
+	ErrInvalidStartDate  = newValidationError("startDate", "required", "start date cannot be empty")
+	ErrInvalidEndDate    = newValidationError("endDate", "required", "end date cannot be empty")
+	ErrInvalidDateRange  = newValidationError("endDate", "invalid_date_range", "end date must be after start date")
+	ErrInvalidRRule      = newValidationError("rrule", "invalid_rrule", "recurrence rule is invalid")
+	ErrInvalidTimeZone   = newValidationError("timeZone", "invalid_time_zone", "time zone must be an IANA name")
+	ErrRangeTooWide      = newValidationError("startTo", "range_too_wide", "requested range contains too many occurrences")
+)
+
+// contextLogger возвращает логгер запроса из ctx с именем сервиса.
+func contextLogger(ctx context.Context) logger.Logger {
+	return logger.FromContext(ctx).With(logger.ComponentKey, "service")
+}
 
 var (
-	ErrEventNotFound     = errors.New("event not found")
-	ErrInvalidEventID    = errors.New("event ID cannot be empty")
-	ErrInvalidEventTitle = errors.New("event title cannot be empty")
-	ErrDateBusy          = errors.New("date is busy")
-	ErrInvalidUserID     = errors.New("user ID cannot be empty")
-	ErrInvalidStartDate  = errors.New("start date cannot be empty")
-	ErrInvalidEndDate    = errors.New("end date cannot be empty")
-	ErrInvalidDateRange  = errors.New("end date must be after start date")
+	// writeTxOptions - SERIALIZABLE, чтобы PostgreSQL прервал одну из двух транзакций, занявших одно время.
+	writeTxOptions = &sql.TxOptions{Isolation: sql.LevelSerializable}
+	readTxOptions  = &sql.TxOptions{ReadOnly: true}
 )
 
 type EventService interface {
 	CreateEvent(ctx context.Context, event events.Event) (*events.Event, error)
+	// UpdateEvent заменяет событие. Если event.Version не 0 и событие уже изменено
+	// другим запросом, возвращается ErrVersionMismatch.
 	UpdateEvent(ctx context.Context, id string, event events.Event) (*events.Event, error)
-	DeleteEvent(ctx context.Context, id string) error
+	// PatchEvent применяет частичное изменение к сохраненному событию. Результат проверяется
+	// как новое событие, пересечения - только если изменилось занятое событием время.
+	// version проверяется так же, как в UpdateEvent.
+	PatchEvent(ctx context.Context, id string, patch events.EventPatch, version int64) (*events.Event, error)
+	// DeleteEvent переносит событие в корзину; version проверяется так же, как в UpdateEvent.
+	DeleteEvent(ctx context.Context, id string, version int64) error
+	// FindTrash возвращает события в корзине, пустой userID - события всех пользователей.
+	FindTrash(ctx context.Context, userID string) ([]events.Event, error)
+	// RestoreEvent возвращает событие из корзины или ErrDateBusy, если его время уже занято.
+	RestoreEvent(ctx context.Context, id string) (*events.Event, error)
 	GetEventByID(ctx context.Context, id string) (*events.Event, error)
 	FindEvent(ctx context.Context, userID string, startFrom, startTo, endFrom, endTo *time.Time) ([]events.Event, error)
+	FindEventPage(
+		ctx context.Context, userID string, startFrom, startTo, endFrom, endTo *time.Time, page events.Page,
+	) ([]events.Event, *events.Cursor, error)
 }
 
 type eventService struct {
@@ -47,11 +77,15 @@ func NewEventService(repo repositories.CompositeEventRepository, txManager datab
 
 func (s *eventService) CreateEvent(ctx context.Context, event events.Event) (*events.Event, error) {
 	if err := s.validateEvent(event); err != nil {
+		contextLogger(ctx).Debug("invalid event", "error", err)
 		return nil, err
 	}
 
 	var createdEvent *events.Event
-	err := s.executeWithTx(ctx, func(ctx context.Context, exec sqlx.ExtContext) error {
+	err := s.executeWithTx(ctx, writeTxOptions, func(ctx context.Context, exec sqlx.ExtContext) error {
+		if err := s.lockUsers(ctx, exec, event.UserID); err != nil {
+			return err
+		}
 		if err := s.checkCrossEvents(ctx, exec, event); err != nil {
 			return err
 		}
@@ -69,53 +103,153 @@ func (s *eventService) UpdateEvent(ctx context.Context, id string, event events.
 	}
 
 	if err := s.validateEvent(event); err != nil {
+		contextLogger(ctx).Debug("invalid event", "event_id", id, "error", err)
 		return nil, err
 	}
 
 	var updatedEvent *events.Event
-	err := s.executeWithTx(ctx, func(ctx context.Context, exec sqlx.ExtContext) error {
-		_, err := s.repository.GetByID(ctx, exec, id)
+	err := s.executeWithTx(ctx, writeTxOptions, func(ctx context.Context, exec sqlx.ExtContext) error {
+		stored, err := s.repository.GetByID(ctx, exec, id)
 		if err != nil {
-			if err.Error() == EntityNotFound {
+			if errors.Is(err, repositories.ErrEntityNotFound) {
 				return ErrEventNotFound
 			}
 			return err
 		}
+		// Устаревшая версия отклоняется до проверки пересечений, окончательно версию
+		// проверяет хранилище при изменении
+		if event.Version != 0 && event.Version != stored.Version {
+			return ErrVersionMismatch
+		}
+		// Часовой пояс серии, не переданный в запросе, сохраняется, как в PatchEvent
+		if event.ZoneName() == "" {
+			event.TimeZone = stored.TimeZone
+		}
+		if err := s.lockUsers(ctx, exec, stored.UserID, event.UserID); err != nil {
+			return err
+		}
 		if err := s.checkCrossEvents(ctx, exec, event); err != nil {
 			return err
 		}
 
 		updatedEvent, err = s.repository.Update(ctx, exec, id, event)
-		return err
+		return storageError(err)
 	})
 
 	return updatedEvent, err
 }
 
-func (s *eventService) DeleteEvent(ctx context.Context, id string) error {
+func (s *eventService) PatchEvent(ctx context.Context, id string, patch events.EventPatch, version int64) (*events.Event, error) {
 	if id == "" {
-		return ErrInvalidEventID
+		return nil, ErrInvalidEventID
 	}
 
-	return s.executeWithTx(ctx, func(ctx context.Context, exec sqlx.ExtContext) error {
-		err := s.repository.Delete(ctx, exec, id)
+	var patchedEvent *events.Event
+	err := s.executeWithTx(ctx, writeTxOptions, func(ctx context.Context, exec sqlx.ExtContext) error {
+		stored, err := s.repository.GetByID(ctx, exec, id)
 		if err != nil {
-			if err.Error() == EntityNotFound {
+			if errors.Is(err, repositories.ErrEntityNotFound) {
 				return ErrEventNotFound
 			}
 			return err
 		}
+		if version != 0 && version != stored.Version {
+			return ErrVersionMismatch
+		}
+
+		event := patch.Apply(*stored)
+		if err := s.validateEvent(event); err != nil {
+			contextLogger(ctx).Debug("invalid event", "event_id", id, "error", err)
+			return err
+		}
+		if events.Reschedules(*stored, event) {
+			if err := s.lockUsers(ctx, exec, stored.UserID, event.UserID); err != nil {
+				return err
+			}
+			if err := s.checkCrossEvents(ctx, exec, event); err != nil {
+				return err
+			}
+		}
+
+		// Хранилище сверяет версию, прочитанную в начале транзакции
+		event.Version = stored.Version
+		patchedEvent, err = s.repository.Update(ctx, exec, id, event)
+		return storageError(err)
+	})
+
+	return patchedEvent, err
+}
+
+func (s *eventService) DeleteEvent(ctx context.Context, id string, version int64) error {
+	if id == "" {
+		return ErrInvalidEventID
+	}
+
+	return s.executeWithTx(ctx, writeTxOptions, func(ctx context.Context, exec sqlx.ExtContext) error {
+		return storageError(s.repository.Delete(ctx, exec, id, version))
+	})
+}
+
+func (s *eventService) FindTrash(ctx context.Context, userID string) ([]events.Event, error) {
+	var founded []events.Event
+	err := s.executeWithTx(ctx, readTxOptions, func(ctx context.Context, exec sqlx.ExtContext) error {
+		var err error
+		founded, err = s.repository.FindTrash(ctx, exec, userID)
 		return err
 	})
+	return founded, err
+}
+
+func (s *eventService) RestoreEvent(ctx context.Context, id string) (*events.Event, error) {
+	if id == "" {
+		return nil, ErrInvalidEventID
+	}
+
+	var restoredEvent *events.Event
+	err := s.executeWithTx(ctx, writeTxOptions, func(ctx context.Context, exec sqlx.ExtContext) error {
+		trashed, err := s.repository.GetTrashedByID(ctx, exec, id)
+		if err != nil {
+			return storageError(err)
+		}
+		if err := s.lockUsers(ctx, exec, trashed.UserID); err != nil {
+			return err
+		}
+		if err := s.checkCrossEvents(ctx, exec, *trashed); err != nil {
+			return err
+		}
+
+		restoredEvent, err = s.repository.Restore(ctx, exec, id)
+		return storageError(err)
+	})
+
+	return restoredEvent, err
+}
+
+// storageError заменяет ошибки хранилища об отсутствии события и несовпадении версии
+// ошибками сервиса.
+func storageError(err error) error {
+	switch {
+	case errors.Is(err, repositories.ErrEntityNotFound):
+		return ErrEventNotFound
+	case errors.Is(err, repositories.ErrVersionMismatch):
+		return ErrVersionMismatch
+	default:
+		return err
+	}
 }
 
 func (s *eventService) GetEventByID(ctx context.Context, id string) (*events.Event, error) {
 	if id == "" {
 		return nil, ErrInvalidEventID
 	}
-	founded, err := s.repository.GetByID(ctx, s.getExecutor(), id)
+	var founded *events.Event
+	err := s.executeWithTx(ctx, readTxOptions, func(ctx context.Context, exec sqlx.ExtContext) error {
+		var err error
+		founded, err = s.repository.GetByID(ctx, exec, id)
+		return err
+	})
 	if err != nil {
-		if err.Error() == EntityNotFound {
+		if errors.Is(err, repositories.ErrEntityNotFound) {
 			return nil, ErrEventNotFound
 		}
 		return nil, err
@@ -124,12 +258,64 @@ func (s *eventService) GetEventByID(ctx context.Context, id string) (*events.Eve
 }
 
 func (s *eventService) FindEvent(ctx context.Context, userID string, startFrom, startTo, endFrom, endTo *time.Time) ([]events.Event, error) {
-	return s.repository.FindEvent(ctx, s.getExecutor(), userID, startFrom, startTo, endFrom, endTo)
+	var founded []events.Event
+	err := s.executeWithTx(ctx, readTxOptions, func(ctx context.Context, exec sqlx.ExtContext) error {
+		var err error
+		founded, err = s.repository.FindEvent(ctx, exec, userID, startFrom, startTo, endFrom, endTo)
+		return err
+	})
+	if errors.Is(err, events.ErrTooManyOccurrences) {
+		return nil, ErrRangeTooWide
+	}
+	return founded, err
+}
+
+func (s *eventService) FindEventPage(
+	ctx context.Context, userID string, startFrom, startTo, endFrom, endTo *time.Time, page events.Page,
+) ([]events.Event, *events.Cursor, error) {
+	var (
+		founded []events.Event
+		next    *events.Cursor
+	)
+	err := s.executeWithTx(ctx, readTxOptions, func(ctx context.Context, exec sqlx.ExtContext) error {
+		var err error
+		founded, next, err = s.repository.FindEventPage(ctx, exec, userID, startFrom, startTo, endFrom, endTo, page)
+		return err
+	})
+	if errors.Is(err, events.ErrTooManyOccurrences) {
+		return nil, nil, ErrRangeTooWide
+	}
+	return founded, next, err
 }
 
+// lockUsers блокирует прежнего и нового владельца в порядке идентификаторов, чтобы встречные переносы не ждали друг друга.
+func (s *eventService) lockUsers(ctx context.Context, exec sqlx.ExtContext, userIDs ...string) error {
+	slices.Sort(userIDs)
+	for _, userID := range slices.Compact(userIDs) {
+		if err := s.repository.LockUser(ctx, exec, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCrossEvents проверяет, что вхождения события не пересекаются с другими событиями пользователя.
+// Бесконечная серия проверяется на RecurrenceHorizon вперед. Владельца события заранее
+// блокирует вызывающий через lockUsers, иначе параллельная запись может занять то же время.
 func (s *eventService) checkCrossEvents(ctx context.Context, exec sqlx.ExtContext, event events.Event) error {
-	startTo := event.EndDate.Add(-time.Nanosecond)
-	endFrom := event.StartDate.Add(time.Nanosecond)
+	occurrences, err := event.OccurrencesMatching(nil, nil, nil, nil)
+	if errors.Is(err, events.ErrTooManyOccurrences) {
+		return ErrRangeTooWide
+	}
+	if err != nil {
+		return ErrInvalidRRule
+	}
+	if len(occurrences) == 0 {
+		return nil
+	}
+
+	startTo := occurrences[len(occurrences)-1].EndDate.Add(-time.Nanosecond)
+	endFrom := occurrences[0].StartDate.Add(time.Nanosecond)
 
 	crossEvents, err := s.repository.FindEvent(ctx, exec, event.UserID, nil, &startTo, &endFrom, nil)
 	if err != nil {
@@ -137,8 +323,15 @@ func (s *eventService) checkCrossEvents(ctx context.Context, exec sqlx.ExtContex
 	}
 
 	for _, e := range crossEvents {
-		if e.ID != event.ID {
-			return ErrDateBusy
+		if e.ID == event.ID {
+			continue
+		}
+		for _, occurrence := range occurrences {
+			if occurrence.Overlaps(e) {
+				contextLogger(ctx).Info("date is busy",
+					"event_id", event.ID, "conflict_id", e.ID, "start", occurrence.StartDate)
+				return ErrDateBusy
+			}
 		}
 	}
 
@@ -166,18 +359,28 @@ func (s *eventService) validateEvent(event events.Event) error {
 		return ErrInvalidDateRange
 	}
 
+	if event.IsRecurring() {
+		if _, err := events.ParseRRule(event.RRule); err != nil {
+			return ErrInvalidRRule
+		}
+	}
+
+	if event.TimeZone != "" {
+		if _, err := time.LoadLocation(event.TimeZone); err != nil || event.TimeZone == "Local" {
+			return ErrInvalidTimeZone
+		}
+	}
+
 	return nil
 }
 
-func (s *eventService) executeWithTx(ctx context.Context, fn func(context.Context, sqlx.ExtContext) error) error {
+func (s *eventService) executeWithTx(
+	ctx context.Context, opts *sql.TxOptions, fn func(context.Context, sqlx.ExtContext) error,
+) error {
 	if s.txManager == nil {
 		return fn(ctx, nil)
 	}
-	return s.txManager.WithTransaction(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+	return s.txManager.WithTransaction(ctx, opts, func(ctx context.Context, tx *sqlx.Tx) error {
 		return fn(ctx, tx)
 	})
 }
-
-func (s *eventService) getExecutor() sqlx.ExtContext {
-	return s.repository.GetDB()
-}
@@ -249,7 +249,7 @@ func TestEventService_DeleteEvent_Success(t *testing.T) {
 	require.NoError(t, err)
 
 	// Удаляем событие
-	err = env.Service.DeleteEvent(ctx, created.ID)
+	err = env.Service.DeleteEvent(ctx, created.ID, created.Version)
 	require.NoError(t, err)
 
 	// Проверяем, что событие удалено
@@ -264,7 +264,7 @@ func TestEventService_DeleteEvent_EmptyID(t *testing.T) {
 
 	ctx := context.Background()
 
-	err := env.Service.DeleteEvent(ctx, "")
+	err := env.Service.DeleteEvent(ctx, "", 0)
 	assert.ErrorIs(t, err, ErrInvalidEventID)
 }
 
@@ -478,3 +478,75 @@ func TestEventService_TransactionRollback_OnError(t *testing.T) {
 	assert.Len(t, result, 1)
 	assert.Equal(t, "Event 1", result[0].Title)
 }
+
+func TestEventService_RecurringEvents(t *testing.T) {
+	env := SetupTestEnvironment(t)
+	defer env.CleanupTestData(t)
+
+	ctx := context.Background()
+	userID := uuid.New().String()
+
+	// Понедельник
+	dtstart := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	series, err := env.Service.CreateEvent(ctx, domain.Event{
+		Title:     "Standup",
+		StartDate: dtstart,
+		EndDate:   dtstart.Add(time.Hour),
+		UserID:    userID,
+		RRule:     "FREQ=WEEKLY;BYDAY=MO,WE",
+	})
+	require.NoError(t, err)
+
+	t.Run("invalid rule is rejected", func(t *testing.T) {
+		_, err := env.Service.CreateEvent(ctx, domain.Event{
+			Title:     "Broken",
+			StartDate: dtstart,
+			EndDate:   dtstart.Add(time.Hour),
+			UserID:    userID,
+			RRule:     "FREQ=YEARLY",
+		})
+		assert.ErrorIs(t, err, ErrInvalidRRule)
+	})
+
+	t.Run("single event overlapping an occurrence is rejected", func(t *testing.T) {
+		// Среда через месяц
+		start := time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC)
+		_, err := env.Service.CreateEvent(ctx, domain.Event{
+			Title:     "Meeting",
+			StartDate: start,
+			EndDate:   start.Add(time.Hour),
+			UserID:    userID,
+		})
+		assert.ErrorIs(t, err, ErrDateBusy)
+	})
+
+	t.Run("single event between occurrences is accepted", func(t *testing.T) {
+		start := time.Date(2024, 1, 30, 10, 0, 0, 0, time.UTC)
+		_, err := env.Service.CreateEvent(ctx, domain.Event{
+			Title:     "Tuesday meeting",
+			StartDate: start,
+			EndDate:   start.Add(time.Hour),
+			UserID:    userID,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("overlapping series is rejected", func(t *testing.T) {
+		start := time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)
+		_, err := env.Service.CreateEvent(ctx, domain.Event{
+			Title:     "Friday sync",
+			StartDate: start,
+			EndDate:   start.Add(time.Hour),
+			UserID:    userID,
+			RRule:     "FREQ=DAILY",
+		})
+		assert.ErrorIs(t, err, ErrDateBusy)
+	})
+
+	t.Run("series does not conflict with itself on update", func(t *testing.T) {
+		update := *series
+		update.ExDates = domain.ExDates{time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC)}
+		_, err := env.Service.UpdateEvent(ctx, series.ID, update)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,440 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories/memory"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const missingID = "00000000-0000-0000-0000-000000000000"
+
+// newTestService возвращает сервис поверх хранилища в памяти. Поведение хранилищ
+// одинаково, его проверяет контракт репозиториев, поэтому сервис проверяется на одном.
+func newTestService(t *testing.T) (EventService, repositories.CompositeEventRepository) {
+	t.Helper()
+	crudRepo := memory.NewEventCrudRepository()
+	repo, err := memory.NewEventRepository(crudRepo)
+	require.NoError(t, err)
+	return NewEventService(repo, memory.NewTxManager(crudRepo)), repo
+}
+
+func TestEventService_UpdateEvent(t *testing.T) {
+	ctx := context.Background()
+	userID := "550e8400-e29b-41d4-a716-446655440002"
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		id      func(stored *domain.Event) string
+		change  func(event *domain.Event)
+		wantErr error
+	}{
+		{name: "current version", change: func(e *domain.Event) { e.Title = "Renamed" }},
+		{name: "without version", change: func(e *domain.Event) { e.Title = "Renamed"; e.Version = 0 }},
+		{name: "stale version", change: func(e *domain.Event) { e.Title = "Renamed"; e.Version-- }, wantErr: ErrVersionMismatch},
+		{
+			name:    "overlaps another event",
+			change:  func(e *domain.Event) { e.StartDate, e.EndDate = start.Add(2*time.Hour), start.Add(3*time.Hour) },
+			wantErr: ErrDateBusy,
+		},
+		{name: "empty title", change: func(e *domain.Event) { e.Title = "" }, wantErr: ErrInvalidEventTitle},
+		{name: "unknown event", id: func(*domain.Event) string { return missingID }, wantErr: ErrEventNotFound},
+		{name: "empty id", id: func(*domain.Event) string { return "" }, wantErr: ErrInvalidEventID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestService(t)
+			created, err := service.CreateEvent(ctx, domain.Event{
+				Title: "Meeting", StartDate: start, EndDate: start.Add(time.Hour), UserID: userID,
+			})
+			require.NoError(t, err)
+			_, err = service.CreateEvent(ctx, domain.Event{
+				Title: "Busy", StartDate: start.Add(2 * time.Hour), EndDate: start.Add(3 * time.Hour), UserID: userID,
+			})
+			require.NoError(t, err)
+			// Версия 2, чтобы устаревшая версия отличалась от "без проверки"
+			created.Title = "First"
+			stored, err := service.UpdateEvent(ctx, created.ID, *created)
+			require.NoError(t, err)
+
+			id, event := stored.ID, *stored
+			if tt.id != nil {
+				id = tt.id(stored)
+			}
+			if tt.change != nil {
+				tt.change(&event)
+			}
+			updated, err := service.UpdateEvent(ctx, id, event)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				current, err := service.GetEventByID(ctx, stored.ID)
+				require.NoError(t, err)
+				assert.Equal(t, stored.Version, current.Version, "rejected update changes nothing")
+				assert.Equal(t, "First", current.Title)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Renamed", updated.Title)
+			assert.Equal(t, stored.Version+1, updated.Version)
+		})
+	}
+}
+
+func TestEventService_DeleteEvent(t *testing.T) {
+	ctx := context.Background()
+	userID := "550e8400-e29b-41d4-a716-446655440005"
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		id      func(stored *domain.Event) string
+		version func(stored *domain.Event) int64
+		wantErr error
+	}{
+		{name: "current version", version: func(e *domain.Event) int64 { return e.Version }},
+		{name: "without version", version: func(*domain.Event) int64 { return 0 }},
+		{name: "stale version", version: func(e *domain.Event) int64 { return e.Version - 1 }, wantErr: ErrVersionMismatch},
+		{name: "unknown event", id: func(*domain.Event) string { return missingID }, wantErr: ErrEventNotFound},
+		{name: "empty id", id: func(*domain.Event) string { return "" }, wantErr: ErrInvalidEventID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestService(t)
+			created, err := service.CreateEvent(ctx, domain.Event{
+				Title: "Meeting", StartDate: start, EndDate: start.Add(time.Hour), UserID: userID,
+			})
+			require.NoError(t, err)
+			stored, err := service.UpdateEvent(ctx, created.ID, *created)
+			require.NoError(t, err)
+
+			id, version := stored.ID, int64(0)
+			if tt.id != nil {
+				id = tt.id(stored)
+			}
+			if tt.version != nil {
+				version = tt.version(stored)
+			}
+			err = service.DeleteEvent(ctx, id, version)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				_, err = service.GetEventByID(ctx, stored.ID)
+				require.NoError(t, err, "rejected delete keeps the event")
+				return
+			}
+			require.NoError(t, err)
+			_, err = service.GetEventByID(ctx, stored.ID)
+			require.ErrorIs(t, err, ErrEventNotFound)
+			require.ErrorIs(t, service.DeleteEvent(ctx, stored.ID, 0), ErrEventNotFound, "deleted event is in trash")
+		})
+	}
+}
+
+// TestEventService_PatchEvent проверяет частичное изменение события: патч проверяется как
+// целое событие, а пересечения - только при изменении занятого времени.
+func TestEventService_PatchEvent(t *testing.T) {
+	ctx := context.Background()
+	userID := "550e8400-e29b-41d4-a716-446655440003"
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	title, emptyTitle, emptyDescription := "Renamed", "", ""
+	moved, movedEnd := start.Add(-time.Hour), start.Add(-30*time.Minute)
+
+	tests := []struct {
+		name    string
+		id      func(stored *domain.Event) string
+		patch   domain.EventPatch
+		version int64
+		wantErr error
+		check   func(t *testing.T, patched *domain.Event)
+	}{
+		{
+			name:  "time unchanged",
+			patch: domain.EventPatch{Title: &title, Description: &emptyDescription},
+			check: func(t *testing.T, patched *domain.Event) {
+				t.Helper()
+				assert.Equal(t, "Renamed", patched.Title)
+				assert.Empty(t, patched.Description)
+				assert.True(t, start.Equal(patched.StartDate))
+			},
+		},
+		{name: "moved event still overlaps", patch: domain.EventPatch{StartDate: &moved}, wantErr: ErrDateBusy},
+		{
+			name:  "moved to free time",
+			patch: domain.EventPatch{StartDate: &moved, EndDate: &movedEnd},
+			check: func(t *testing.T, patched *domain.Event) {
+				t.Helper()
+				assert.True(t, moved.Equal(patched.StartDate))
+				assert.True(t, movedEnd.Equal(patched.EndDate))
+			},
+		},
+		{name: "empty title", patch: domain.EventPatch{Title: &emptyTitle}, wantErr: ErrInvalidEventTitle},
+		{name: "end before start", patch: domain.EventPatch{EndDate: &moved}, wantErr: ErrInvalidDateRange},
+		{name: "other version", patch: domain.EventPatch{Title: &title}, version: 2, wantErr: ErrVersionMismatch},
+		{name: "unknown event", id: func(*domain.Event) string { return missingID }, patch: domain.EventPatch{Title: &title}, wantErr: ErrEventNotFound},
+		{name: "empty id", id: func(*domain.Event) string { return "" }, patch: domain.EventPatch{Title: &title}, wantErr: ErrInvalidEventID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo := newTestService(t)
+			// Пересекающиеся события сохраняются в обход проверки сервиса
+			stored, err := repo.Create(ctx, repo.GetDB(), domain.Event{
+				Title: "Meeting", Description: "Weekly sync", StartDate: start, EndDate: start.Add(time.Hour), UserID: userID,
+			})
+			require.NoError(t, err)
+			_, err = repo.Create(ctx, repo.GetDB(), domain.Event{
+				Title: "Overlapping", StartDate: start.Add(30 * time.Minute), EndDate: start.Add(2 * time.Hour), UserID: userID,
+			})
+			require.NoError(t, err)
+
+			id := stored.ID
+			if tt.id != nil {
+				id = tt.id(stored)
+			}
+			patched, err := service.PatchEvent(ctx, id, tt.patch, tt.version)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				current, err := service.GetEventByID(ctx, stored.ID)
+				require.NoError(t, err)
+				assert.Equal(t, stored.Version, current.Version, "rejected patch changes nothing")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, stored.Version+1, patched.Version)
+			tt.check(t, patched)
+		})
+	}
+}
+
+// TestEventService_RestoreEvent проверяет, что событие восстанавливается из корзины,
+// только если его время не заняли другие события.
+func TestEventService_RestoreEvent(t *testing.T) {
+	ctx := context.Background()
+	userID := "550e8400-e29b-41d4-a716-446655440004"
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	event := domain.Event{Title: "Meeting", StartDate: start, EndDate: start.Add(time.Hour), UserID: userID}
+
+	tests := []struct {
+		name    string
+		id      func(trashed *domain.Event) string
+		prepare func(t *testing.T, service EventService)
+		wantErr error
+	}{
+		{name: "free time"},
+		{
+			name: "time is taken",
+			prepare: func(t *testing.T, service EventService) {
+				t.Helper()
+				// Время удаленного события свободно, пока оно в корзине
+				replacement := event
+				replacement.Title = "Replacement"
+				_, err := service.CreateEvent(ctx, replacement)
+				require.NoError(t, err)
+			},
+			wantErr: ErrDateBusy,
+		},
+		{name: "not in trash", id: func(*domain.Event) string { return missingID }, wantErr: ErrEventNotFound},
+		{name: "empty id", id: func(*domain.Event) string { return "" }, wantErr: ErrInvalidEventID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestService(t)
+			created, err := service.CreateEvent(ctx, event)
+			require.NoError(t, err)
+			require.NoError(t, service.DeleteEvent(ctx, created.ID, created.Version))
+			if tt.prepare != nil {
+				tt.prepare(t, service)
+			}
+
+			id := created.ID
+			if tt.id != nil {
+				id = tt.id(created)
+			}
+			restored, err := service.RestoreEvent(ctx, id)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				trash, err := service.FindTrash(ctx, userID)
+				require.NoError(t, err)
+				require.Len(t, trash, 1, "rejected restore keeps event in trash")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Meeting", restored.Title)
+			assert.Nil(t, restored.DeletedAt)
+
+			stored, err := service.GetEventByID(ctx, created.ID)
+			require.NoError(t, err)
+			assert.Equal(t, restored.Version, stored.Version)
+			trash, err := service.FindTrash(ctx, userID)
+			require.NoError(t, err)
+			assert.Empty(t, trash)
+		})
+	}
+}
+
+// lockRecordingRepository запоминает пользователей, события которых блокирует сервис.
+type lockRecordingRepository struct {
+	repositories.CompositeEventRepository
+	locked []string
+}
+
+func (r *lockRecordingRepository) LockUser(ctx context.Context, exec sqlx.ExtContext, userID string) error {
+	r.locked = append(r.locked, userID)
+	return r.CompositeEventRepository.LockUser(ctx, exec, userID)
+}
+
+func TestEventService_LocksUsersOnce(t *testing.T) {
+	ctx := context.Background()
+	const (
+		first  = "550e8400-e29b-41d4-a716-446655440002"
+		second = "550e8400-e29b-41d4-a716-446655440009"
+	)
+	crudRepo := memory.NewEventCrudRepository()
+	repo, err := memory.NewEventRepository(crudRepo)
+	require.NoError(t, err)
+	recorder := &lockRecordingRepository{CompositeEventRepository: repo}
+	service := NewEventService(recorder, memory.NewTxManager(crudRepo))
+
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	created, err := service.CreateEvent(ctx, domain.Event{
+		Title: "Meeting", StartDate: start, EndDate: start.Add(time.Hour), UserID: second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{second}, recorder.locked)
+
+	// Блокировки берутся в порядке идентификаторов, а не прежний владелец первым
+	recorder.locked = nil
+	moved := *created
+	moved.UserID = first
+	updated, err := service.UpdateEvent(ctx, created.ID, moved)
+	require.NoError(t, err)
+	assert.Equal(t, []string{first, second}, recorder.locked)
+
+	recorder.locked = nil
+	owner := second
+	patched, err := service.PatchEvent(ctx, created.ID, domain.EventPatch{UserID: &owner}, updated.Version)
+	require.NoError(t, err)
+	assert.Equal(t, []string{first, second}, recorder.locked)
+
+	require.NoError(t, service.DeleteEvent(ctx, created.ID, patched.Version))
+	recorder.locked = nil
+	_, err = service.RestoreEvent(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{second}, recorder.locked)
+}
+
+// optionsRecordingTxManager запоминает, открывались ли транзакции только для чтения.
+type optionsRecordingTxManager struct {
+	database.TxManager
+	readOnly []bool
+}
+
+func (tm *optionsRecordingTxManager) WithTransaction(
+	ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx *sqlx.Tx) error,
+) error {
+	tm.readOnly = append(tm.readOnly, opts != nil && opts.ReadOnly)
+	return tm.TxManager.WithTransaction(ctx, opts, fn)
+}
+
+func TestEventService_ReadsAreReadOnly(t *testing.T) {
+	ctx := context.Background()
+	crudRepo := memory.NewEventCrudRepository()
+	repo, err := memory.NewEventRepository(crudRepo)
+	require.NoError(t, err)
+	txManager := &optionsRecordingTxManager{TxManager: memory.NewTxManager(crudRepo)}
+	service := NewEventService(repo, txManager)
+
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	created, err := service.CreateEvent(ctx, domain.Event{
+		Title: "Meeting", StartDate: start, EndDate: start.Add(time.Hour), UserID: "user-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false}, txManager.readOnly, "writes use read-write transactions")
+
+	txManager.readOnly = nil
+	_, err = service.GetEventByID(ctx, created.ID)
+	require.NoError(t, err)
+	_, err = service.FindEvent(ctx, "user-1", nil, nil, nil, nil)
+	require.NoError(t, err)
+	_, _, err = service.FindEventPage(ctx, "user-1", nil, nil, nil, nil, domain.Page{Limit: 10})
+	require.NoError(t, err)
+	_, err = service.FindTrash(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, true, true, true}, txManager.readOnly)
+
+	_, err = service.GetEventByID(ctx, "missing")
+	require.ErrorIs(t, err, ErrEventNotFound)
+}
+
+// TestEventService_RecurringSeries проверяет, что замена серии сохраняет ее часовой пояс,
+// а слишком широкое окно поиска отклоняется.
+func TestEventService_RecurringSeries(t *testing.T) {
+	ctx := context.Background()
+	userID := "550e8400-e29b-41d4-a716-446655440006"
+	service, _ := newTestService(t)
+
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	start := time.Date(2026, 3, 23, 10, 0, 0, 0, loc)
+	created, err := service.CreateEvent(ctx, domain.Event{
+		Title:     "Standup",
+		StartDate: start,
+		EndDate:   start.Add(15 * time.Minute),
+		UserID:    userID,
+		RRule:     "FREQ=DAILY",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", created.TimeZone)
+
+	// Замена события с датами в UTC, как их передает HTTP API, сохраняет часовой пояс серии
+	replacement := *created
+	replacement.Title = "Daily standup"
+	replacement.StartDate = start.UTC()
+	replacement.EndDate = start.Add(15 * time.Minute).UTC()
+	replacement.TimeZone = ""
+	updated, err := service.UpdateEvent(ctx, created.ID, replacement)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", updated.TimeZone)
+
+	tests := []struct {
+		name    string
+		to      time.Time
+		wantLen int
+		wantErr error
+	}{
+		// 29 марта в Берлине переход на летнее время
+		{name: "across DST change", to: start.AddDate(0, 0, 9), wantLen: 10},
+		{name: "too wide window", to: start.AddDate(30, 0, 0), wantErr: ErrRangeTooWide},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := start, tt.to
+			found, err := service.FindEvent(ctx, userID, &from, &to, nil, nil)
+			_, _, pageErr := service.FindEventPage(ctx, userID, &from, &to, nil, nil, domain.Page{Limit: 10})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.ErrorIs(t, pageErr, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, pageErr)
+			require.Len(t, found, tt.wantLen)
+			for _, occurrence := range found {
+				local := occurrence.StartDate.In(loc)
+				assert.Equal(t, 10, local.Hour(), local)
+			}
+		})
+	}
+}
@@ -30,7 +30,7 @@ func SetupTestEnvironment(t *testing.T) *TestEnvironment {
 
 	// Создаем компоненты
 	txManager := database.NewTxManager(pc.DB)
-	crudRepo := db.NewEventCrudRepository(pc.DB)
+	crudRepo := db.NewEventCrudRepository(pc.DB, db.Postgres)
 	repository, err := db.NewEventRepository(crudRepo)
 	if err != nil {
 		t.Fatalf("Failed to create repository: %v", err)
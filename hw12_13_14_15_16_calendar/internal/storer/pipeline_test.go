@@ -0,0 +1,81 @@
+package storer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+	filequeue "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue/file"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories/memory"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Планировщик и хранитель связаны через файловую очередь, как два отдельных процесса.
+func TestPipeline_SchedulerToStorerViaFileQueue(t *testing.T) {
+	ctx := context.Background()
+	log := logger.New("ERROR", &bytes.Buffer{})
+	config := filequeue.Config{Dir: t.TempDir(), PollInterval: 5 * time.Millisecond}
+
+	eventRepo, err := memory.NewEventRepository(memory.NewEventCrudRepository())
+	require.NoError(t, err)
+	now := time.Now()
+	event, err := eventRepo.Create(ctx, nil, domain.Event{
+		Title:      "Standup",
+		StartDate:  now.Add(10 * time.Minute),
+		EndDate:    now.Add(time.Hour),
+		UserID:     "user-1",
+		OffsetTime: time.Hour,
+	})
+	require.NoError(t, err)
+
+	producer, err := filequeue.NewProducer(config)
+	require.NoError(t, err)
+	defer producer.Close()
+
+	sched := scheduler.New(eventRepo, nil, producer, log, scheduler.Config{
+		Topic:     "notifications",
+		Interval:  time.Minute,
+		BatchSize: 10,
+	})
+	published, err := sched.Notify(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+
+	// Первый хранитель падает после получения сообщения, не подтвердив его
+	consumer, err := filequeue.NewConsumer(config, "notifications", "storer")
+	require.NoError(t, err)
+	_, err = consumer.Fetch(ctx)
+	require.NoError(t, err)
+	require.NoError(t, consumer.Close())
+
+	consumer, err = filequeue.NewConsumer(config, "notifications", "storer")
+	require.NoError(t, err)
+	defer consumer.Close()
+
+	notificationRepo := memory.NewNotificationRepository()
+	s := New(notificationRepo, consumer, queue.ConsumeOptions{Backoff: queue.DefaultBackoff()}, log)
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Run(runCtx)
+	}()
+
+	require.Eventually(t, func() bool {
+		stored, err := s.ListByUser(ctx, "user-1")
+		return err == nil && len(stored) == 1
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	stored, err := s.ListByUser(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, event.ID, stored[0].ID)
+	assert.Equal(t, event.Title, stored[0].Title)
+	assert.True(t, event.NotifyAt().Equal(stored[0].NotifyAt))
+}
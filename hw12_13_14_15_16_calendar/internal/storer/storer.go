@@ -0,0 +1,96 @@
+package storer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories"
+)
+
+var ErrInvalidNotification = errors.New("invalid notification")
+
+// Storer читает уведомления из очереди и сохраняет их в хранилище.
+type Storer struct {
+	repository repositories.NotificationRepository
+	consumer   queue.Consumer
+	options    queue.ConsumeOptions
+	logger     logger.Logger
+}
+
+// New создает хранитель. options задает паузы перед повторной доставкой сообщения,
+// которое не удалось сохранить, число попыток и топик для отклоненных сообщений.
+func New(repo repositories.NotificationRepository, consumer queue.Consumer, options queue.ConsumeOptions, log logger.Logger) *Storer {
+	s := &Storer{
+		repository: repo,
+		consumer:   consumer,
+		options:    options,
+		logger:     log.With(logger.ComponentKey, "storer"),
+	}
+	if s.options.OnReject == nil {
+		s.options.OnReject = s.logRejected
+	}
+	return s
+}
+
+// Run обрабатывает сообщения до отмены контекста.
+func (s *Storer) Run(ctx context.Context) error {
+	s.logger.Info("started")
+	if err := queue.Consume(ctx, s.consumer, s.options, s.Handle); err != nil {
+		return fmt.Errorf("failed to consume notifications: %w", err)
+	}
+	s.logger.Info("stopped")
+	return nil
+}
+
+// Handle сохраняет одно уведомление. Повторная доставка того же уведомления не считается ошибкой.
+// Для сообщений, которые невозможно разобрать, возвращается queue.ErrUnprocessable:
+// они не доставляются повторно, а сразу уходят в топик отклоненных сообщений.
+func (s *Storer) Handle(ctx context.Context, msg queue.Message) error {
+	var notification domain.Notification
+	if err := json.Unmarshal(msg.Value, &notification); err != nil {
+		return fmt.Errorf("%w: malformed message: %w", queue.ErrUnprocessable, err)
+	}
+	if err := validateNotification(notification); err != nil {
+		return fmt.Errorf("%w: %w", queue.ErrUnprocessable, err)
+	}
+
+	err := s.repository.Save(ctx, s.repository.GetDB(), notification)
+	if errors.Is(err, repositories.ErrEntityAlreadyExists) {
+		s.logger.Debug("notification already stored", "event_id", notification.ID)
+		return nil
+	}
+	if err != nil {
+		s.logger.Error("failed to store notification", "event_id", notification.ID, "error", err)
+		return err
+	}
+
+	s.logger.Info("notification stored", "event_id", notification.ID)
+	return nil
+}
+
+func (s *Storer) logRejected(msg queue.Message, attempt int, err error) {
+	s.logger.Error("message rejected", "topic", msg.Topic, "attempt", attempt, "error", err)
+}
+
+// ListByUser возвращает сохраненные уведомления пользователя в порядке времени отправки.
+func (s *Storer) ListByUser(ctx context.Context, userID string) ([]domain.Notification, error) {
+	return s.repository.FindByUserID(ctx, s.repository.GetDB(), userID)
+}
+
+func validateNotification(notification domain.Notification) error {
+	if notification.ID == "" {
+		return fmt.Errorf("%w: event ID is empty", ErrInvalidNotification)
+	}
+	if notification.UserID == "" {
+		return fmt.Errorf("%w: user ID is empty", ErrInvalidNotification)
+	}
+	if notification.NotifyAt.IsZero() {
+		return fmt.Errorf("%w: notify time is empty", ErrInvalidNotification)
+	}
+	return nil
+}
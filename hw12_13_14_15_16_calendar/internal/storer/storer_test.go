@@ -0,0 +1,128 @@
+package storer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/domain"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue"
+	memoryqueue "github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/queue/memory"
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/repositories/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func notificationMessage(t *testing.T, n domain.Notification) queue.Message {
+	t.Helper()
+	value, err := json.Marshal(n)
+	require.NoError(t, err)
+	return queue.Message{Topic: "notifications", Key: []byte(n.ID), Value: value}
+}
+
+func TestStorer_Handle(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewNotificationRepository()
+	s := New(repo, nil, queue.ConsumeOptions{Backoff: queue.DefaultBackoff()}, logger.New("ERROR", &bytes.Buffer{}))
+
+	notifyAt := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	first := domain.Notification{
+		ID:       "event-1",
+		Title:    "Standup",
+		Date:     notifyAt.Add(time.Hour),
+		UserID:   "user-1",
+		NotifyAt: notifyAt,
+	}
+	second := domain.Notification{
+		ID:       "event-2",
+		Title:    "Review",
+		Date:     notifyAt.Add(3 * time.Hour),
+		UserID:   "user-1",
+		NotifyAt: notifyAt.Add(2 * time.Hour),
+	}
+
+	t.Run("stores notifications", func(t *testing.T) {
+		require.NoError(t, s.Handle(ctx, notificationMessage(t, second)))
+		require.NoError(t, s.Handle(ctx, notificationMessage(t, first)))
+
+		stored, err := s.ListByUser(ctx, "user-1")
+		require.NoError(t, err)
+		require.Len(t, stored, 2)
+		assert.Equal(t, "event-1", stored[0].ID)
+		assert.Equal(t, "event-2", stored[1].ID)
+	})
+
+	t.Run("redelivery is idempotent", func(t *testing.T) {
+		require.NoError(t, s.Handle(ctx, notificationMessage(t, first)))
+
+		stored, err := s.ListByUser(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Len(t, stored, 2)
+	})
+
+	t.Run("same event with another notify time is stored", func(t *testing.T) {
+		moved := first
+		moved.NotifyAt = notifyAt.Add(30 * time.Minute)
+		require.NoError(t, s.Handle(ctx, notificationMessage(t, moved)))
+
+		stored, err := s.ListByUser(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Len(t, stored, 3)
+	})
+
+	t.Run("malformed message is unprocessable", func(t *testing.T) {
+		err := s.Handle(ctx, queue.Message{Topic: "notifications", Value: []byte("{not json")})
+		require.ErrorIs(t, err, queue.ErrUnprocessable)
+
+		err = s.Handle(ctx, notificationMessage(t, domain.Notification{Title: "No IDs"}))
+		require.ErrorIs(t, err, queue.ErrUnprocessable)
+		require.ErrorIs(t, err, ErrInvalidNotification)
+	})
+
+	t.Run("other users are not listed", func(t *testing.T) {
+		stored, err := s.ListByUser(ctx, "user-2")
+		require.NoError(t, err)
+		assert.Empty(t, stored)
+	})
+}
+
+func TestStorer_Run(t *testing.T) {
+	broker := memoryqueue.NewBroker()
+	repo := memory.NewNotificationRepository()
+	consumer := memoryqueue.NewConsumer(broker, "notifications")
+	s := New(repo, consumer, queue.ConsumeOptions{Backoff: queue.DefaultBackoff(), DeadLetter: broker}, logger.New("ERROR", &bytes.Buffer{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Run(ctx)
+	}()
+
+	n := domain.Notification{
+		ID:       "event-1",
+		Title:    "Standup",
+		Date:     time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		UserID:   "user-1",
+		NotifyAt: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, broker.Publish(ctx, queue.Message{Topic: "notifications", Value: []byte("{not json")}))
+	require.NoError(t, broker.Publish(ctx, notificationMessage(t, n)))
+	require.NoError(t, broker.Publish(ctx, notificationMessage(t, n)))
+
+	require.Eventually(t, func() bool {
+		stored, err := s.ListByUser(ctx, "user-1")
+		return err == nil && len(stored) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// Неразбираемое сообщение не блокирует очередь и уходит в топик отклоненных с первой попытки
+	dead := broker.Messages("notifications.dlq")
+	require.Len(t, dead, 1)
+	assert.Equal(t, "{not json", string(dead[0].Value))
+	assert.Equal(t, "1", dead[0].Headers[queue.HeaderAttempts])
+
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -5,13 +5,11 @@ package testhelpers
 
 import (
 	"context"
-	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
+	"io"
 	"testing"
 	"time"
 
+	"github.com/avmiki80/golang-diasoft/hw12_13_14_15_16_calendar/internal/database"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"github.com/testcontainers/testcontainers-go"
@@ -86,91 +84,13 @@ func SetupPostgresContainer(t *testing.T, dbName string) *PostgresContainer {
 // CleanupTestData очищает все данные из таблиц
 func CleanupTestData(t *testing.T, db *sqlx.DB) {
 	t.Helper()
-	_, err := db.Exec("TRUNCATE TABLE public.events CASCADE")
+	_, err := db.Exec("TRUNCATE TABLE public.events, public.notifications CASCADE")
 	if err != nil {
 		t.Fatalf("Failed to cleanup test data: %v", err)
 	}
 }
 
-// ApplyMigrations применяет миграции из папки migrations
+// ApplyMigrations применяет встроенные миграции goose из пакета migrations
 func ApplyMigrations(db *sqlx.DB) error {
-	projectRoot, err := getProjectRoot()
-	if err != nil {
-		return err
-	}
-
-	migrationsDir := filepath.Join(projectRoot, "migrations")
-
-	migrationFiles := []string{
-		"00001_create_events_table.sql",
-		"00002_add_timestamps_to_events.sql",
-	}
-
-	for _, filename := range migrationFiles {
-		filePath := filepath.Join(migrationsDir, filename)
-		content, err := os.ReadFile(filePath)
-		if err != nil {
-			return fmt.Errorf("failed to read migration file %s: %w", filename, err)
-		}
-
-		sql := extractUpMigration(string(content))
-		if sql == "" {
-			return fmt.Errorf("no SQL found in migration file %s", filename)
-		}
-
-		if _, err := db.Exec(sql); err != nil {
-			return fmt.Errorf("failed to apply migration %s: %w", filename, err)
-		}
-	}
-
-	return nil
-}
-
-// getProjectRoot определяет корень проекта
-func getProjectRoot() (string, error) {
-	// Ищем go.mod начиная с текущей директории
-	dir, err := os.Getwd()
-	if err != nil {
-		return "", fmt.Errorf("failed to get working directory: %w", err)
-	}
-
-	for {
-		goModPath := filepath.Join(dir, "go.mod")
-		if _, err := os.Stat(goModPath); err == nil {
-			return dir, nil
-		}
-
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			return "", fmt.Errorf("go.mod not found")
-		}
-		dir = parent
-	}
-}
-
-// extractUpMigration извлекает SQL для миграции "Up" из goose формата
-func extractUpMigration(content string) string {
-	lines := strings.Split(content, "\n")
-	var sqlLines []string
-	inUpSection := false
-
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-
-		if strings.Contains(trimmed, "-- +goose Up") {
-			inUpSection = true
-			continue
-		}
-
-		if strings.Contains(trimmed, "-- +goose Down") {
-			break
-		}
-
-		if inUpSection && !strings.Contains(trimmed, "-- +goose StatementBegin") &&
-			!strings.Contains(trimmed, "-- +goose StatementEnd") {
-			sqlLines = append(sqlLines, line)
-		}
-	}
-
-	return strings.TrimSpace(strings.Join(sqlLines, "\n"))
+	return database.Migrate(context.Background(), db.DB, database.DialectPostgres, database.MigrateUp, io.Discard)
 }
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE events
+    ADD COLUMN notified_at TIMESTAMPTZ;
+
+CREATE INDEX idx_events_not_notified ON events(start_date) WHERE notified_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_events_not_notified;
+
+ALTER TABLE events
+DROP COLUMN IF EXISTS notified_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE notifications (
+                               id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                               event_id UUID NOT NULL,
+                               title VARCHAR(255) NOT NULL,
+                               event_date TIMESTAMPTZ NOT NULL,
+                               user_id UUID NOT NULL,
+                               notify_at TIMESTAMPTZ NOT NULL,
+                               created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+                               CONSTRAINT uq_notifications_event_notify_at UNIQUE (event_id, notify_at)
+);
+
+CREATE INDEX idx_notifications_user_id ON notifications(user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notifications_user_id;
+DROP TABLE IF EXISTS notifications;
+-- +goose StatementEnd
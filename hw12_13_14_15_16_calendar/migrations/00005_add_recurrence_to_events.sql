@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE events
+    ADD COLUMN rrule TEXT NOT NULL DEFAULT '',
+    ADD COLUMN exdates TEXT NOT NULL DEFAULT '',
+    ADD COLUMN series_end TIMESTAMPTZ;
+
+-- series_end - окончание последнего вхождения серии, NULL - бесконечная серия
+UPDATE events SET series_end = end_date;
+
+CREATE INDEX idx_events_series_end ON events(series_end);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_events_series_end;
+
+ALTER TABLE events
+DROP COLUMN IF EXISTS series_end,
+DROP COLUMN IF EXISTS exdates,
+DROP COLUMN IF EXISTS rrule;
+
+-- +goose StatementEnd
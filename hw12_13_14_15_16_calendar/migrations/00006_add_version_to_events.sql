@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- version - номер версии события для оптимистической блокировки, растет при каждом изменении
+ALTER TABLE events
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE events
+DROP COLUMN IF EXISTS version;
+
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- time_zone - IANA-имя часового пояса, в котором разворачивается серия, пусто - UTC
+ALTER TABLE events
+    ADD COLUMN time_zone TEXT NOT NULL DEFAULT '';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE events
+DROP COLUMN IF EXISTS time_zone;
+
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- next_notify_at - момент уведомления о следующем вхождении, NULL - уведомлять больше не о чем.
+-- Уведомленной серии ставится время последнего уведомления: планировщик найдет вхождение после него.
+ALTER TABLE events
+    ADD COLUMN next_notify_at TIMESTAMPTZ;
+
+UPDATE events
+SET next_notify_at = start_date - make_interval(secs => COALESCE(offset_time, 0) / 1000000000.0)
+WHERE notified_at IS NULL;
+
+UPDATE events
+SET next_notify_at = notified_at
+WHERE notified_at IS NOT NULL AND rrule <> '';
+
+DROP INDEX IF EXISTS idx_events_not_notified;
+
+CREATE INDEX idx_events_next_notify_at ON events(next_notify_at) WHERE next_notify_at IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_events_next_notify_at;
+
+CREATE INDEX idx_events_not_notified ON events(start_date) WHERE notified_at IS NULL;
+
+ALTER TABLE events
+DROP COLUMN IF EXISTS next_notify_at;
+
+-- +goose StatementEnd
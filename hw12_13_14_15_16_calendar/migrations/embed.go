@@ -0,0 +1,9 @@
+// Package migrations содержит SQL-миграции goose, встроенные в бинарный файл.
+package migrations
+
+import "embed"
+
+// FS - файлы миграций, применяются через database.Migrate.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Время хранится текстом в UTC фиксированной ширины (2024-01-01T10:00:00.000000000Z),
+-- поэтому строки сравниваются и сортируются так же, как моменты времени.
+CREATE TABLE events (
+                        id TEXT PRIMARY KEY,
+                        title TEXT NOT NULL,
+                        start_date TEXT NOT NULL,
+                        end_date TEXT NOT NULL,
+                        description TEXT NOT NULL DEFAULT '',
+                        user_id TEXT NOT NULL,
+                        offset_time INTEGER NOT NULL DEFAULT 0,
+                        -- notify_at = start_date - offset_time, вычисляется репозиторием при сохранении
+                        notify_at TEXT NOT NULL,
+                        notified_at TEXT,
+                        rrule TEXT NOT NULL DEFAULT '',
+                        exdates TEXT NOT NULL DEFAULT '',
+                        -- series_end - окончание последнего вхождения серии, NULL - бесконечная серия
+                        series_end TEXT,
+                        created_at TEXT NOT NULL,
+                        updated_at TEXT NOT NULL,
+
+                        CONSTRAINT valid_dates CHECK (end_date > start_date)
+);
+
+CREATE INDEX idx_events_user_id ON events(user_id);
+CREATE INDEX idx_events_start_date ON events(start_date);
+CREATE INDEX idx_events_end_date ON events(end_date);
+CREATE INDEX idx_events_series_end ON events(series_end);
+CREATE INDEX idx_events_not_notified ON events(notify_at) WHERE notified_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_events_not_notified;
+DROP INDEX IF EXISTS idx_events_series_end;
+DROP INDEX IF EXISTS idx_events_end_date;
+DROP INDEX IF EXISTS idx_events_start_date;
+DROP INDEX IF EXISTS idx_events_user_id;
+DROP TABLE IF EXISTS events;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE notifications (
+                               id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+                               event_id TEXT NOT NULL,
+                               title TEXT NOT NULL,
+                               event_date TEXT NOT NULL,
+                               user_id TEXT NOT NULL,
+                               notify_at TEXT NOT NULL,
+                               created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+
+                               CONSTRAINT uq_notifications_event_notify_at UNIQUE (event_id, notify_at)
+);
+
+CREATE INDEX idx_notifications_user_id ON notifications(user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notifications_user_id;
+DROP TABLE IF EXISTS notifications;
+-- +goose StatementEnd
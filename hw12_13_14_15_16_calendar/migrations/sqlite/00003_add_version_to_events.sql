@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- version - номер версии события для оптимистической блокировки, растет при каждом изменении
+ALTER TABLE events ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN version;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- deleted_at - время переноса события в корзину, NULL - событие не удалено
+ALTER TABLE events ADD COLUMN deleted_at TEXT;
+
+CREATE INDEX idx_events_deleted_at ON events(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_events_deleted_at;
+ALTER TABLE events DROP COLUMN deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- time_zone - IANA-имя часового пояса, в котором разворачивается серия, пусто - UTC
+ALTER TABLE events ADD COLUMN time_zone TEXT NOT NULL DEFAULT '';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN time_zone;
+-- +goose StatementEnd
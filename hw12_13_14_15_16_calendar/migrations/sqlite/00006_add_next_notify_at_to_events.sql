@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- next_notify_at заменяет notify_at: момент уведомления о следующем вхождении,
+-- NULL - уведомлять больше не о чем. Уведомленной серии ставится время последнего
+-- уведомления: планировщик найдет вхождение после него.
+ALTER TABLE events ADD COLUMN next_notify_at TEXT;
+
+UPDATE events SET next_notify_at = notify_at WHERE notified_at IS NULL;
+UPDATE events SET next_notify_at = notified_at WHERE notified_at IS NOT NULL AND rrule <> '';
+
+DROP INDEX IF EXISTS idx_events_not_notified;
+ALTER TABLE events DROP COLUMN notify_at;
+
+CREATE INDEX idx_events_next_notify_at ON events(next_notify_at) WHERE next_notify_at IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_events_next_notify_at;
+
+-- Прежний notify_at восстанавливается только для еще не уведомленных событий, для остальных он не читается
+ALTER TABLE events ADD COLUMN notify_at TEXT NOT NULL DEFAULT '';
+UPDATE events SET notify_at = COALESCE(next_notify_at, start_date);
+
+ALTER TABLE events DROP COLUMN next_notify_at;
+
+CREATE INDEX idx_events_not_notified ON events(notify_at) WHERE notified_at IS NULL;
+-- +goose StatementEnd
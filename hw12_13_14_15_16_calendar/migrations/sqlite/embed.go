@@ -0,0 +1,9 @@
+// Package sqlite содержит SQL-миграции goose для хранилища SQLite.
+package sqlite
+
+import "embed"
+
+// FS - файлы миграций SQLite, применяются через database.Migrate.
+//
+//go:embed *.sql
+var FS embed.FS